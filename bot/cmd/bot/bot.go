@@ -1,8 +1,13 @@
 package bot
 
 import (
+	"context"
 	"github.com/nlypage/intele"
+	"os"
+	"os/signal"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis"
 
@@ -19,6 +24,15 @@ import (
 	"gorm.io/gorm"
 )
 
+// shutdownTimeout is the time given to the background services to stop
+const shutdownTimeout = 10 * time.Second
+
+// Service is a background service that runs alongside the bot
+type Service interface {
+	Start()
+	Shutdown(ctx context.Context) error
+}
+
 type Bot struct {
 	*tele.Bot
 	Layout     *layout.Layout
@@ -74,7 +88,16 @@ func New(config *config.Config) (*Bot, error) {
 	return bot, nil
 }
 
-func (b *Bot) Start() {
+// Start starts the bot and the given services and blocks until SIGINT or SIGTERM is received,
+// then it stops the bot and gracefully shuts down the services
+func (b *Bot) Start(services ...Service) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, service := range services {
+		service.Start()
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 
@@ -87,7 +110,7 @@ func (b *Bot) Start() {
 			if err != nil {
 				logger.Log.Errorf("Failed to create notify logger: %v", err)
 			} else {
-				notifyService := service.NewNotifyService(b.Bot, b.Layout, notifyLogger, nil, nil, nil, nil)
+				notifyService := service.NewNotifyService(b.Bot, b.Layout, notifyLogger, nil, nil, nil, nil, nil)
 				logHook, err := notifyService.LogHook(
 					viper.GetInt64("settings.logging.channel-id"),
 					viper.GetString("settings.logging.locale"),
@@ -103,5 +126,17 @@ func (b *Bot) Start() {
 		b.Bot.Start()
 	}()
 
+	<-ctx.Done()
+	logger.Log.Info("Shutting down")
+	b.Bot.Stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, service := range services {
+		if err := service.Shutdown(shutdownCtx); err != nil {
+			logger.Log.Errorf("Failed to shutdown service: %v", err)
+		}
+	}
+
 	wg.Wait()
 }
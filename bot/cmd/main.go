@@ -4,6 +4,7 @@ import (
 	"github.com/Badsnus/cu-clubs-bot/bot/cmd/bot"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/config"
 	setupBot "github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/controller/telegram/setup"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/controller/web"
 	"log"
 
 	_ "time/tzdata"
@@ -18,5 +19,5 @@ func main() {
 
 	setupBot.Setup(b)
 
-	b.Start()
+	b.Start(web.NewServer(b))
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
@@ -95,6 +96,9 @@ func Get() *Config {
 	if errMigrate != nil {
 		logger.Log.Panicf("Failed to migrate database: %v", errMigrate)
 	}
+	if err = postgresStorage.NewUserStorage(database).FillNameParts(context.Background()); err != nil {
+		logger.Log.Panicf("Failed to fill user name parts: %v", err)
+	}
 
 	r, err := redis.New(redis.Options{
 		Host:     viper.GetString("service.redis.host"),
@@ -19,6 +19,7 @@ import (
 	"gorm.io/gorm"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type adminUserService interface {
@@ -46,6 +47,12 @@ type clubOwnerService interface {
 	GetByUserID(ctx context.Context, userID int64) ([]dto.ClubOwner, error)
 }
 
+type venueService interface {
+	Get(ctx context.Context, id string) (*entity.Venue, error)
+	GetAll(ctx context.Context) ([]entity.Venue, error)
+	GetOccupancy(ctx context.Context, venueID string, day time.Time) ([]entity.Event, error)
+}
+
 type Handler struct {
 	layout *layout.Layout
 	logger *types.Logger
@@ -55,6 +62,7 @@ type Handler struct {
 	adminUserService adminUserService
 	clubService      clubService
 	clubOwnerService clubOwnerService
+	venueService     venueService
 }
 
 func New(b *bot.Bot) *Handler {
@@ -70,6 +78,7 @@ func New(b *bot.Bot) *Handler {
 		adminUserService: service.NewUserService(userStorage, nil, nil, nil, ""),
 		clubService:      service.NewClubService(clubStorage),
 		clubOwnerService: service.NewClubOwnerService(clubOwnerStorage, userStorage),
+		venueService:     service.NewVenueService(postgres.NewVenueStorage(b.DB), postgres.NewEventStorage(b.DB)),
 	}
 }
 
@@ -903,5 +912,10 @@ func (h Handler) AdminSetup(group *tele.Group) {
 	group.Handle(h.layout.Callback("admin:club:roles"), h.manageRoles)
 	group.Handle(h.layout.Callback("admin:club:roles:role"), h.manageRoles)
 	group.Handle(h.layout.Callback("admin:club:delete"), h.deleteClub)
+	group.Handle(h.layout.Callback("admin:venues"), h.venuesList)
+	group.Handle(h.layout.Callback("admin:venues:back"), h.venuesList)
+	group.Handle(h.layout.Callback("admin:venues:venue"), h.venueOccupancy)
+	group.Handle(h.layout.Callback("admin:venue:prev_day"), h.venueOccupancy)
+	group.Handle(h.layout.Callback("admin:venue:next_day"), h.venueOccupancy)
 	group.Handle("/ban", h.banUser)
 }
@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	tele "gopkg.in/telebot.v3"
+)
+
+// venueDateLayout is the layout of the day in the venue occupancy callback data
+const venueDateLayout = "20060102"
+
+func (h Handler) venuesList(c tele.Context) error {
+	h.logger.Infof("(user: %d) edit venues list", c.Sender().ID)
+
+	venues, err := h.venueService.GetAll(context.Background())
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get venues: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "admin:backToMenu"),
+		)
+	}
+
+	today := time.Now().In(location.Location()).Format(venueDateLayout)
+
+	markup := c.Bot().NewMarkup()
+	var rows []tele.Row
+	for _, venue := range venues {
+		rows = append(rows, markup.Row(*h.layout.Button(c, "admin:venues:venue", struct {
+			ID   string
+			Name string
+			Date string
+		}{
+			ID:   venue.ID,
+			Name: venue.Name,
+			Date: today,
+		})))
+	}
+	rows = append(rows, markup.Row(*h.layout.Button(c, "admin:back_to_menu")))
+	markup.Inline(rows...)
+
+	return c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "venues_list")),
+		markup,
+	)
+}
+
+// venueOccupancy shows the events in the venue on the day from the callback data
+func (h Handler) venueOccupancy(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	venueID := callbackData[0]
+	day, err := time.ParseInLocation(venueDateLayout, callbackData[1], location.Location())
+	if err != nil {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	h.logger.Infof("(user: %d) edit venue occupancy (venue_id=%s, date=%s)", c.Sender().ID, venueID, callbackData[1])
+
+	backMarkup := h.layout.Markup(c, "admin:backToMenu")
+
+	venue, err := h.venueService.Get(context.Background(), venueID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get venue: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	events, err := h.venueService.GetOccupancy(context.Background(), venueID, day)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get venue occupancy: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	const timeLayout = "15:04"
+
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		lines = append(lines, h.layout.Text(c, "venue_occupancy_event", struct {
+			Name      string
+			ClubName  string
+			StartTime string
+			EndTime   string
+		}{
+			Name:      event.Name,
+			ClubName:  event.Club.Name,
+			StartTime: event.StartTime.In(location.Location()).Format(timeLayout),
+			EndTime:   event.GetEndTime().In(location.Location()).Format(timeLayout),
+		}))
+	}
+
+	return c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "venue_occupancy", struct {
+			Name    string
+			Address string
+			Buffer  int
+			Date    string
+			Events  string
+		}{
+			Name:    venue.Name,
+			Address: venue.Address,
+			Buffer:  int(venue.Buffer / time.Minute),
+			Date:    day.Format("02.01.2006"),
+			Events:  strings.Join(lines, "\n"),
+		})),
+		h.layout.Markup(c, "admin:venue", struct {
+			ID       string
+			PrevDate string
+			NextDate string
+		}{
+			ID:       venue.ID,
+			PrevDate: day.AddDate(0, 0, -1).Format(venueDateLayout),
+			NextDate: day.AddDate(0, 0, 1).Format(venueDateLayout),
+		}),
+	)
+}
@@ -0,0 +1,203 @@
+package clubowner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	tele "gopkg.in/telebot.v3"
+)
+
+// analyticsEventsCounts are the numbers of last events the analytics can be calculated for
+var analyticsEventsCounts = []int{5, 10, 20}
+
+const defaultAnalyticsEventsCount = 10
+
+// parseAnalyticsData parses the "<club_id> [events_count]" callback data
+func parseAnalyticsData(data string) (clubID string, eventsCount int, err error) {
+	parts := strings.Split(data, " ")
+	switch len(parts) {
+	case 1:
+		eventsCount = defaultAnalyticsEventsCount
+	case 2:
+		eventsCount, err = strconv.Atoi(parts[1])
+		if err != nil || !slices.Contains(analyticsEventsCounts, eventsCount) {
+			return "", 0, errorz.ErrInvalidCallbackData
+		}
+	default:
+		return "", 0, errorz.ErrInvalidCallbackData
+	}
+	if parts[0] == "" {
+		return "", 0, errorz.ErrInvalidCallbackData
+	}
+
+	return parts[0], eventsCount, nil
+}
+
+func (h Handler) clubAnalytics(c tele.Context) error {
+	clubID, eventsCount, err := parseAnalyticsData(c.Callback().Data)
+	if err != nil {
+		return err
+	}
+	h.logger.Infof("(user: %d) edit club analytics (club_id=%s, events_count=%d)", c.Sender().ID, clubID, eventsCount)
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+
+	analytics, err := h.analyticsService.GetClubAnalytics(context.Background(), clubID, eventsCount)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club analytics: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+
+	type eventRow struct {
+		Name       string
+		StartTime  string
+		Registered int
+		Visited    int
+		Conversion float64
+	}
+	eventRows := make([]eventRow, 0, len(analytics.Events))
+	for _, event := range analytics.Events {
+		eventRows = append(eventRows, eventRow{
+			Name:       event.Name,
+			StartTime:  event.StartTime.In(location.Location()).Format("02.01.2006"),
+			Registered: event.Registered,
+			Visited:    event.Visited,
+			Conversion: event.Conversion(),
+		})
+	}
+
+	type roleRow struct {
+		Role       string
+		Registered int
+		NoShows    int
+		NoShowRate float64
+	}
+	roleRows := make([]roleRow, 0, len(analytics.Roles))
+	for _, role := range analytics.Roles {
+		roleRows = append(roleRows, roleRow{
+			Role:       h.layout.Text(c, role.Role.String()),
+			Registered: role.Registered,
+			NoShows:    role.NoShows,
+			NoShowRate: role.NoShowRate(),
+		})
+	}
+
+	var countsRow []tele.InlineButton
+	for _, count := range analyticsEventsCounts {
+		countsRow = append(countsRow, *h.layout.Button(c, "clubOwner:club:analytics:count", struct {
+			ID       string
+			Count    int
+			Selected bool
+		}{
+			ID:       clubID,
+			Count:    count,
+			Selected: count == eventsCount,
+		}).Inline())
+	}
+
+	analyticsMarkup := h.layout.Markup(c, "clubOwner:club:analytics", struct {
+		ID    string
+		Count int
+	}{
+		ID:    clubID,
+		Count: eventsCount,
+	})
+	analyticsMarkup.InlineKeyboard = append([][]tele.InlineButton{countsRow}, analyticsMarkup.InlineKeyboard...)
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "club_analytics_text", struct {
+			Name            string
+			Count           int
+			Events          []eventRow
+			Roles           []roleRow
+			Registered      int
+			Visited         int
+			Conversion      float64
+			Trend           float64
+			Attendees       int
+			RepeatAttendees int
+			RepeatRate      float64
+		}{
+			Name:            club.Name,
+			Count:           eventsCount,
+			Events:          eventRows,
+			Roles:           roleRows,
+			Registered:      analytics.Registered,
+			Visited:         analytics.Visited,
+			Conversion:      analytics.Conversion(),
+			Trend:           analytics.Trend(),
+			Attendees:       analytics.Attendees,
+			RepeatAttendees: analytics.RepeatAttendees,
+			RepeatRate:      analytics.RepeatRate(),
+		})),
+		analyticsMarkup,
+	)
+}
+
+func (h Handler) clubAnalyticsExport(c tele.Context) error {
+	clubID, eventsCount, err := parseAnalyticsData(c.Callback().Data)
+	if err != nil {
+		return err
+	}
+	h.logger.Infof("(user: %d) export club analytics (club_id=%s, events_count=%d)", c.Sender().ID, clubID, eventsCount)
+
+	analytics, err := h.analyticsService.GetClubAnalytics(context.Background(), clubID, eventsCount)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club analytics: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	locale, _ := h.layout.Locale(c)
+	buf, err := h.analyticsService.AnalyticsToXLSX(analytics, locale)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while export club analytics to xlsx: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	fileName := fmt.Sprintf("analytics_%s.xlsx", time.Now().In(location.Location()).Format("02.01.2006"))
+	doc := &tele.Document{
+		File: tele.FromReader(buf),
+		Caption: h.layout.Text(c, "club_analytics_exported_text", struct {
+			Count int
+		}{
+			Count: eventsCount,
+		}),
+		FileName: fileName,
+	}
+
+	return c.Send(
+		doc,
+		h.layout.Markup(c, "core:hide"),
+	)
+}
@@ -0,0 +1,333 @@
+package clubowner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+)
+
+const (
+	maxRejectReasonLength = 500
+	// applicationCallbackTTL is how long the review buttons of the application work, the event, the applicant
+	// and the page do not fit into the callback data together, so they are kept in the callbacks storage
+	applicationCallbackTTL = 24 * time.Hour
+)
+
+// eventApprovalSwitch turns the registration approval of the event on and off
+func (h Handler) eventApprovalSwitch(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) switch event approval (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	// The pending applications stay in the queue when the approval is turned off, so they can still be reviewed
+	event.RequiresApproval = !event.RequiresApproval
+	_, err = h.eventService.Update(context.Background(), event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while update event approval: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	return h.eventSettings(c)
+}
+
+// eventApplications shows the oldest pending application on the event with the approve and reject buttons
+func (h Handler) eventApplications(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) open event applications (event_id=%s)", c.Sender().ID, eventID)
+
+	what, markup := h.eventApplication(c, eventID, page)
+	return c.Edit(what, markup)
+}
+
+// eventApplication returns the view of the next application on the event to review
+func (h Handler) eventApplication(c tele.Context, eventID, page string) (interface{}, *tele.ReplyMarkup) {
+	backMarkup := h.layout.Markup(c, "clubOwner:event:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	application, err := h.applicationService.GetNext(context.Background(), eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return banner.ClubOwner.Caption(h.layout.Text(c, "event_applications_empty", event)), backMarkup
+		}
+		h.logger.Errorf("(user: %d) error while get event application: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	applicationsCount, err := h.applicationService.CountByEventID(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while count event applications: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	participantsCount, err := h.eventParticipantService.CountByEventID(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get participants count: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	user, err := h.userService.Get(context.Background(), application.UserID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get applicant: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	text := h.layout.Text(c, "event_application", struct {
+		Name              string
+		FIO               string
+		Username          string
+		Email             string
+		Role              string
+		CreatedAt         string
+		ApplicationsCount int
+		ParticipantsCount int
+		MaxParticipants   int
+	}{
+		Name:              event.Name,
+		FIO:               user.FIO,
+		Username:          user.Username,
+		Email:             user.Email,
+		Role:              h.layout.Text(c, string(user.Role)),
+		CreatedAt:         application.CreatedAt.In(location.Location()).Format("02.01.2006 15:04"),
+		ApplicationsCount: applicationsCount,
+		ParticipantsCount: participantsCount,
+		MaxParticipants:   event.MaxParticipants,
+	})
+	callbackID, err := h.callbacksStorage.Set(fmt.Sprintf("%s %d %s", eventID, application.UserID, page), applicationCallbackTTL)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while setting callback: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+	markup := h.layout.Markup(c, "clubOwner:event:application", struct {
+		CallbackID string
+		ID         string
+		Page       string
+	}{
+		CallbackID: callbackID,
+		ID:         eventID,
+		Page:       page,
+	})
+	return banner.ClubOwner.Caption(text), markup
+}
+
+// applicationCallback returns the event, the applicant and the page of the pressed review button
+func (h Handler) applicationCallback(c tele.Context) (string, int64, string, error) {
+	callbackData, err := h.callbacksStorage.Get(c.Callback().Data)
+	if err != nil {
+		return "", 0, "", err
+	}
+	data := strings.Split(callbackData, " ")
+	if len(data) != 3 {
+		return "", 0, "", errorz.ErrInvalidCallbackData
+	}
+
+	userID, err := strconv.ParseInt(data[1], 10, 64)
+	if err != nil {
+		return "", 0, "", errorz.ErrInvalidCallbackData
+	}
+	return data[0], userID, data[2], nil
+}
+
+func (h Handler) approveEventApplication(c tele.Context) error {
+	eventID, userID, page, err := h.applicationCallback(c)
+	if errors.Is(err, errorz.ErrInvalidCallbackData) {
+		return err
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while getting callback from redis: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_application_expired")),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+	h.logger.Infof("(user: %d) approve event application (event_id=%s, user_id=%d)", c.Sender().ID, eventID, userID)
+
+	_, err = h.applicationService.Approve(context.Background(), eventID, userID)
+	switch {
+	case errors.Is(err, errorz.ErrEventUnavailable):
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_application_unavailable"),
+			ShowAlert: true,
+		})
+	case errors.Is(err, errorz.ErrNoSeats):
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_application_no_seats"),
+			ShowAlert: true,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// The application has been withdrawn or reviewed by another owner
+		_ = c.Respond(&tele.CallbackResponse{
+			Text: h.layout.Text(c, "event_application_not_found"),
+		})
+	case err != nil:
+		h.logger.Errorf("(user: %d) error while approve event application: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	default:
+		_ = c.Respond(&tele.CallbackResponse{
+			Text: h.layout.Text(c, "event_application_approved_owner"),
+		})
+	}
+
+	what, markup := h.eventApplication(c, eventID, page)
+	return c.Edit(what, markup)
+}
+
+// rejectEventApplication asks for the optional rejection reason and rejects the application
+func (h Handler) rejectEventApplication(c tele.Context) error {
+	eventID, userID, page, err := h.applicationCallback(c)
+	if errors.Is(err, errorz.ErrInvalidCallbackData) {
+		return err
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while getting callback from redis: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_application_expired")),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+	h.logger.Infof("(user: %d) reject event application (event_id=%s, user_id=%d)", c.Sender().ID, eventID, userID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:application:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	callbackBtn := h.layout.Button(c, "clubOwner:event:application:reject_skip")
+	markup := &tele.ReplyMarkup{InlineKeyboard: slices.Clone(backMarkup.InlineKeyboard)}
+	markup.InlineKeyboard = append(
+		[][]tele.InlineButton{{*callbackBtn.Inline()}},
+		markup.InlineKeyboard...,
+	)
+
+	inputCollector := collector.New()
+	_ = c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "input_reject_reason")),
+		markup,
+	)
+	inputCollector.Collect(c.Message())
+
+	var (
+		reason string
+		done   bool
+	)
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0, callbackBtn)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input reject reason: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_reject_reason"))),
+				markup,
+			)
+		case response.Callback != nil:
+			reason = ""
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			done = true
+		case response.Message == nil:
+			h.logger.Errorf("(user: %d) error while input reject reason: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_reject_reason"))),
+				markup,
+			)
+		case utf8.RuneCountInString(response.Message.Text) > maxRejectReasonLength:
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "invalid_reject_reason", maxRejectReasonLength)),
+				markup,
+			)
+		default:
+			reason = response.Message.Text
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			done = true
+		}
+		if done {
+			break
+		}
+	}
+
+	err = h.applicationService.Reject(context.Background(), eventID, userID, reason)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		h.logger.Errorf("(user: %d) error while reject event application: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	what, next := h.eventApplication(c, eventID, page)
+	return c.Send(what, next)
+}
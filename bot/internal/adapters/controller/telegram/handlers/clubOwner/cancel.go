@@ -0,0 +1,209 @@
+package clubowner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/calendar"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+)
+
+// cancelEvent asks the club owner to confirm the cancellation of the event
+func (h Handler) cancelEvent(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) cancel event request (event_id=%s)", c.Sender().ID, eventID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventForCancel(c, eventID, backMarkup)
+	if event == nil {
+		return err
+	}
+
+	participantsCount, err := h.eventParticipantService.CountByEventID(context.Background(), event.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get registered users count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "cancel_event_text", struct {
+			Name              string
+			ParticipantsCount int
+		}{
+			Name:              event.Name,
+			ParticipantsCount: participantsCount,
+		})),
+		h.layout.Markup(c, "clubOwner:event:cancel", struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: page,
+		}),
+	)
+}
+
+// acceptEventCancel asks the reason of the cancellation, cancels the event and notifies the users of the event
+func (h Handler) acceptEventCancel(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) cancel event (event_id=%s)", c.Sender().ID, eventID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventForCancel(c, eventID, backMarkup)
+	if event == nil {
+		return err
+	}
+
+	reason, ok := h.inputEventValue(c, backMarkup,
+		h.layout.Text(c, "input_event_cancel_reason", event),
+		h.layout.Text(c, "invalid_event_cancel_reason"),
+		func(text string) bool {
+			return validator.EventCancelReason(text, nil)
+		},
+	)
+	if !ok {
+		return nil
+	}
+
+	event, err = h.cancelAndNotify(c, event, reason)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while cancel event: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	return c.Send(
+		banner.ClubOwner.Caption(h.layout.Text(c, "event_cancelled", event)),
+		backMarkup,
+	)
+}
+
+// cancelAndNotify cancels the event and notifies the participants with the reason and the calendar file
+// that removes the event from their calendars, the users on the waitlist and with pending applications
+// are notified with the reason only
+func (h Handler) cancelAndNotify(c tele.Context, event *entity.Event, reason string) (*entity.Event, error) {
+	event, err := h.eventService.Cancel(context.Background(), event, reason)
+	if err != nil {
+		return nil, err
+	}
+	h.logger.Infof("(user: %d) event cancelled (event_id=%s)", c.Sender().ID, event.ID)
+
+	notification := struct {
+		Name      string
+		StartTime string
+		Reason    string
+	}{
+		Name:      event.Name,
+		StartTime: formatEventTime(event.StartTime),
+		Reason:    reason,
+	}
+
+	ics, err := h.cancelledEventICS(event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while export cancelled event to ics: %v", c.Sender().ID, err)
+		err = h.notificationService.SendEventUpdate(event.ID, "event_notification_cancelled", notification)
+	} else {
+		err = h.notificationService.SendEventDocument(event.ID, "event_notification_cancelled", notification, ics, fmt.Sprintf("%s.ics", event.Name))
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while send event cancel notification: %v", c.Sender().ID, err)
+	}
+
+	err = h.notificationService.SendEventWaiting(event.ID, "event_notification_cancelled_waiting", notification)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while send event cancel notification to waiting users: %v", c.Sender().ID, err)
+	}
+	return event, nil
+}
+
+// eventForCancel returns the event if it can be cancelled,
+// the event is nil if it can not be cancelled and the error is the response to the user then
+func (h Handler) eventForCancel(c tele.Context, eventID string, backMarkup *tele.ReplyMarkup) (*entity.Event, error) {
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return nil, c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	switch {
+	case event.IsDraft:
+		return nil, c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_cancel_draft"),
+			ShowAlert: true,
+		})
+	case event.IsCancelled():
+		return nil, c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_already_cancelled"),
+			ShowAlert: true,
+		})
+	case event.IsOver(0):
+		return nil, c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_cancel_started"),
+			ShowAlert: true,
+		})
+	}
+	return event, nil
+}
+
+// cancelledEventICS exports the cancelled event the same way the participants have exported it:
+// the occurrence of an active series is excluded from the series, other events get the cancelled status
+func (h Handler) cancelledEventICS(event *entity.Event) ([]byte, error) {
+	if event.SeriesID == nil {
+		return calendar.ExportEventToICS(*event, nil)
+	}
+
+	series, err := h.eventSeriesService.ExcludeOccurrence(context.Background(), *event.SeriesID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return calendar.ExportEventToICS(*event, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences, err := h.eventSeriesService.GetOccurrences(context.Background(), series.ID)
+	if err != nil {
+		return nil, err
+	}
+	return calendar.ExportEventToICS(*event, series, occurrences...)
+}
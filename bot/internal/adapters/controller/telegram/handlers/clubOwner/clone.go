@@ -0,0 +1,453 @@
+package clubowner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/service"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+)
+
+// eventDuplicate shows how the event can be reused: duplicated with the new times or saved as the club template
+func (h Handler) eventDuplicate(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) open event duplicate (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "event_duplicate_text", event)),
+		h.layout.Markup(c, "clubOwner:event:duplicate", struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: page,
+		}),
+	)
+}
+
+// duplicateEvent starts the event creation with all fields of the event except the times, only the times are asked
+func (h Handler) duplicateEvent(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) duplicate event (event_id=%s)", c.Sender().ID, eventID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	template := entity.NewEventTemplate("", *event)
+	return h.createEventFromTemplate(c, &template, backMarkup)
+}
+
+// saveEventTemplate asks the title and saves the event as the template of the club
+func (h Handler) saveEventTemplate(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) save event as template (event_id=%s)", c.Sender().ID, eventID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	title, ok := h.inputEventValue(c, backMarkup,
+		h.layout.Text(c, "input_event_template_title"),
+		h.layout.Text(c, "invalid_event_template_title"),
+		func(text string) bool {
+			return validator.EventTemplateTitle(text, nil)
+		},
+	)
+	if !ok {
+		return nil
+	}
+
+	template, err := h.eventTemplateService.Create(context.Background(), title, event)
+	switch {
+	case errors.Is(err, errorz.ErrTemplatesLimit):
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_templates_limit", service.MaxEventTemplates)),
+			backMarkup,
+		)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_template_already_exists", title)),
+			backMarkup,
+		)
+	case err != nil:
+		h.logger.Errorf("(user: %d) error while create event template: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	h.logger.Infof("(user: %d) event template saved (event_id=%s, template_id=%s)", c.Sender().ID, eventID, template.ID)
+
+	return c.Send(
+		banner.ClubOwner.Caption(h.layout.Text(c, "event_template_saved", template)),
+		backMarkup,
+	)
+}
+
+func (h Handler) clubEventTemplates(c tele.Context) error {
+	clubID := c.Callback().Data
+	if clubID == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) get club event templates (club_id=%s)", c.Sender().ID, clubID)
+
+	return h.showClubEventTemplates(c, clubID)
+}
+
+// showClubEventTemplates shows the list of the club templates
+func (h Handler) showClubEventTemplates(c tele.Context, clubID string) error {
+	backMarkup := h.layout.Markup(c, "clubOwner:club:back", struct {
+		ID string
+	}{
+		ID: clubID,
+	})
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	templates, err := h.eventTemplateService.GetByClubID(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event templates: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	var rows [][]tele.InlineButton
+	for _, template := range templates {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "clubOwner:templates:template", template).Inline()})
+	}
+	backMarkup.InlineKeyboard = append(rows, backMarkup.InlineKeyboard...)
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "event_templates_text", struct {
+			Name  string
+			Count int
+			Max   int
+		}{
+			Name:  club.Name,
+			Count: len(templates),
+			Max:   service.MaxEventTemplates,
+		})),
+		backMarkup,
+	)
+}
+
+// getEventTemplate returns the template from the callback data,
+// if the template can't be found the error message is sent to the user and nil is returned
+func (h Handler) getEventTemplate(c tele.Context) (*entity.EventTemplate, error) {
+	templateID := c.Callback().Data
+	if templateID == "" {
+		return nil, errorz.ErrInvalidCallbackData
+	}
+
+	template, err := h.eventTemplateService.Get(context.Background(), templateID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_template_not_found")),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event template: %v", c.Sender().ID, err)
+		return nil, c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+	return template, nil
+}
+
+func (h Handler) eventTemplate(c tele.Context) error {
+	template, err := h.getEventTemplate(c)
+	if template == nil {
+		return err
+	}
+	h.logger.Infof("(user: %d) get event template (template_id=%s)", c.Sender().ID, template.ID)
+
+	roles := make([]string, 0, len(template.AllowedRoles))
+	for _, role := range template.AllowedRoles {
+		roles = append(roles, h.layout.Text(c, role))
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "event_template_text", struct {
+			Title                 string
+			Name                  string
+			Description           string
+			Location              string
+			AfterRegistrationText string
+			MaxParticipants       int
+			MaxGuests             int
+			Roles                 string
+			RequiresApproval      bool
+			QuestionsCount        int
+		}{
+			Title:                 template.Title,
+			Name:                  template.Name,
+			Description:           template.Description,
+			Location:              template.Location,
+			AfterRegistrationText: template.AfterRegistrationText,
+			MaxParticipants:       template.MaxParticipants,
+			MaxGuests:             template.MaxGuests,
+			Roles:                 strings.Join(roles, ", "),
+			RequiresApproval:      template.RequiresApproval,
+			QuestionsCount:        len(template.Questions),
+		})),
+		h.layout.Markup(c, "clubOwner:template", template),
+	)
+}
+
+// createEventFromClubTemplate starts the event creation from the club template, only the times are asked
+func (h Handler) createEventFromClubTemplate(c tele.Context) error {
+	template, err := h.getEventTemplate(c)
+	if template == nil {
+		return err
+	}
+	h.logger.Infof("(user: %d) create event from template (template_id=%s)", c.Sender().ID, template.ID)
+
+	return h.createEventFromTemplate(c, template, h.layout.Markup(c, "clubOwner:template:back", template))
+}
+
+func (h Handler) deleteEventTemplate(c tele.Context) error {
+	template, err := h.getEventTemplate(c)
+	if template == nil {
+		return err
+	}
+
+	err = h.eventTemplateService.Delete(context.Background(), template.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		h.logger.Errorf("(user: %d) error while delete event template: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:template:back", template),
+		)
+	}
+	h.logger.Infof("(user: %d) event template deleted (template_id=%s)", c.Sender().ID, template.ID)
+
+	_ = c.Respond(&tele.CallbackResponse{
+		Text: h.layout.Text(c, "event_template_deleted", template),
+	})
+	return h.showClubEventTemplates(c, template.ClubID)
+}
+
+// createEventFromTemplate asks the times of the new event and shows the event confirmation,
+// all other fields of the event are taken from the template
+func (h Handler) createEventFromTemplate(c tele.Context, template *entity.EventTemplate, backMarkup *tele.ReplyMarkup) error {
+	club, err := h.clubService.Get(context.Background(), template.ClubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	event := template.Event()
+	// The roles of the club could have been changed since the template has been saved
+	event.AllowedRoles = slices.DeleteFunc(slices.Clone(event.AllowedRoles), func(role string) bool {
+		return !slices.Contains(club.AllowedRoles, role)
+	})
+
+	if !h.inputEventTimes(c, &event, backMarkup) {
+		return nil
+	}
+
+	h.eventsStorage.Clear(c.Sender().ID)
+	h.eventsStorage.Set(c.Sender().ID, event, 0)
+
+	return h.showEventConfirmation(c, c.Send, club, event)
+}
+
+// inputEventTimes asks the start, end, registration end and cancellation end of the event the same way the event creation does,
+// returns false if the input has been cancelled
+func (h Handler) inputEventTimes(c tele.Context, event *entity.Event, backMarkup *tele.ReplyMarkup) bool {
+	type step struct {
+		promptKey  string
+		objectFunc func() interface{}
+		errorKey   string
+		result     string
+		validator  func(string, map[string]interface{}) bool
+		skipBtn    *tele.Btn
+	}
+
+	var steps []*step
+	startTimeParams := func() map[string]interface{} {
+		return map[string]interface{}{
+			"startTime": steps[0].result,
+		}
+	}
+	noObject := func() interface{} {
+		return struct{}{}
+	}
+	steps = []*step{
+		{
+			promptKey:  "input_event_start_time",
+			objectFunc: noObject,
+			errorKey:   "invalid_event_start_time",
+			validator:  validator.EventStartTime,
+		},
+		{
+			promptKey:  "input_event_end_time",
+			objectFunc: noObject,
+			errorKey:   "invalid_event_end_time",
+			validator:  validator.EventEndTime,
+			skipBtn:    h.layout.Button(c, "clubOwner:create_event:end_time_skip"),
+		},
+		{
+			promptKey: "input_event_registered_end_time",
+			objectFunc: func() interface{} {
+				return struct {
+					MaxRegisteredEndTime string
+				}{
+					MaxRegisteredEndTime: utils.GetMaxRegisteredEndTime(steps[0].result),
+				}
+			},
+			errorKey:  "invalid_event_registered_end_time",
+			validator: validator.EventRegisteredEndTime,
+		},
+		{
+			promptKey:  "input_event_cancellation_end",
+			objectFunc: noObject,
+			errorKey:   "invalid_event_cancellation_end",
+			validator:  validator.EventCancellationEnd,
+			skipBtn:    h.layout.Button(c, "clubOwner:create_event:cancellation_end_skip"),
+		},
+	}
+
+	inputCollector := collector.New()
+	inputCollector.Collect(c.Message())
+
+	for i, step := range steps {
+		markup := &tele.ReplyMarkup{InlineKeyboard: backMarkup.InlineKeyboard}
+		if step.skipBtn != nil {
+			markup.InlineKeyboard = append(
+				[][]tele.InlineButton{{*step.skipBtn.Inline()}},
+				backMarkup.InlineKeyboard...,
+			)
+		}
+
+		prompt := banner.ClubOwner.Caption(h.layout.Text(c, step.promptKey, step.objectFunc()))
+		if i == 0 {
+			_ = c.Edit(prompt, markup)
+		} else {
+			_ = inputCollector.Send(c, prompt, markup)
+		}
+
+		for done := false; !done; {
+			response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0, step.skipBtn)
+			if response.Message != nil {
+				inputCollector.Collect(response.Message)
+			}
+			switch {
+			case response.Canceled:
+				_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+				return false
+			case errGet != nil:
+				h.logger.Errorf("(user: %d) error while input step (%s): %v", c.Sender().ID, step.promptKey, errGet)
+				_ = inputCollector.Send(c,
+					banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, step.promptKey, step.objectFunc()))),
+					backMarkup,
+				)
+			case response.Callback != nil:
+				step.result = ""
+				_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+				done = true
+			case response.Message == nil || !step.validator(strings.TrimSpace(response.Message.Text), startTimeParams()):
+				_ = inputCollector.Send(c,
+					banner.ClubOwner.Caption(h.layout.Text(c, step.errorKey, step.objectFunc())),
+					backMarkup,
+				)
+			default:
+				step.result = strings.TrimSpace(response.Message.Text)
+				_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+				done = true
+			}
+		}
+	}
+
+	// Skipped optional times are left empty, the same as in the event creation
+	event.StartTime, _ = time.ParseInLocation(eventTimeLayout, steps[0].result, location.Location())
+	event.EndTime, _ = time.ParseInLocation(eventTimeLayout, steps[1].result, location.Location())
+	event.RegistrationEnd, _ = time.ParseInLocation(eventTimeLayout, steps[2].result, location.Location())
+	event.CancellationEnd, _ = time.ParseInLocation(eventTimeLayout, steps[3].result, location.Location())
+	return true
+}
@@ -1,11 +1,13 @@
 package clubowner
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils"
@@ -13,6 +15,7 @@ import (
 	"github.com/Badsnus/cu-clubs-bot/bot/cmd/bot"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/controller/telegram/handlers/middlewares"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/postgres"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis/callbacks"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis/events"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
@@ -57,20 +60,115 @@ type eventService interface {
 	Get(ctx context.Context, id string) (*entity.Event, error)
 	GetByClubID(ctx context.Context, limit, offset int, order string, clubID string) ([]entity.Event, error)
 	CountByClubID(ctx context.Context, clubID string) (int64, error)
+	Cancel(ctx context.Context, event *entity.Event, reason string) (*entity.Event, error)
 	Delete(ctx context.Context, id string) error
 }
 
 type eventParticipantService interface {
 	CountByEventID(ctx context.Context, eventID string) (int, error)
 	CountVisitedByEventID(ctx context.Context, eventID string) (int, error)
+	GetRoster(ctx context.Context, eventID, query string, limit, offset int) ([]dto.EventUser, error)
+	CountRoster(ctx context.Context, eventID, query string) (int, error)
+	CountAttendanceChanges(ctx context.Context, eventID string) (int, error)
+	SwitchVisited(ctx context.Context, event *entity.Event, userID, changedBy int64) (bool, error)
+}
+
+type waitlistService interface {
+	CountByEventID(ctx context.Context, eventID string) (int, error)
+	PromoteNext(ctx context.Context, eventID string) error
+}
+
+type eventSeriesService interface {
+	Create(ctx context.Context, event *entity.Event, interval int) (*entity.EventSeries, error)
+	Get(ctx context.Context, id string) (*entity.EventSeries, error)
+	GetFutureOccurrences(ctx context.Context, seriesID string) ([]entity.Event, error)
+	ApplyToSeries(ctx context.Context, event *entity.Event) ([]entity.Event, error)
+	Cancel(ctx context.Context, seriesID string) ([]entity.Event, error)
+	GetOccurrences(ctx context.Context, seriesID string) ([]entity.Event, error)
+	ExcludeOccurrence(ctx context.Context, seriesID string) (*entity.EventSeries, error)
+}
+
+type calendarService interface {
+	ClubFeedURL(clubID string) string
+	ClubEventsURL(clubID string) string
+}
+
+type analyticsService interface {
+	GetClubAnalytics(ctx context.Context, clubID string, eventsCount int) (*dto.ClubAnalytics, error)
+	AnalyticsToXLSX(analytics *dto.ClubAnalytics, locale string) (*bytes.Buffer, error)
 }
 
 type qrService interface {
-	GetEventQR(ctx context.Context, eventID string) (qr tele.File, err error)
+	GetEventQR(ctx context.Context, eventID string) ([]byte, error)
+	EventQRPeriod() time.Duration
 }
 
 type notificationService interface {
-	SendEventUpdate(eventID string, what interface{}, opts ...interface{}) error
+	SendEventUpdate(eventID string, key string, data interface{}) error
+	SendEventDocument(eventID string, key string, data interface{}, file []byte, fileName string) error
+	SendEventWaiting(eventID string, key string, data interface{}) error
+	ResetReminders(ctx context.Context, eventID string) error
+}
+
+type mailingService interface {
+	Texts(key string, data interface{}) map[string]string
+	Send(mailing dto.Mailing, users []entity.User) (string, error)
+}
+
+type scheduledMailingService interface {
+	Create(ctx context.Context, mailing *entity.ScheduledMailing) (*entity.ScheduledMailing, error)
+	Get(ctx context.Context, id string) (*entity.ScheduledMailing, error)
+	GetByClubID(ctx context.Context, clubID string) ([]entity.ScheduledMailing, error)
+	Update(ctx context.Context, mailing *entity.ScheduledMailing) (*entity.ScheduledMailing, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type eventTemplateService interface {
+	Create(ctx context.Context, title string, event *entity.Event) (*entity.EventTemplate, error)
+	Get(ctx context.Context, id string) (*entity.EventTemplate, error)
+	GetByClubID(ctx context.Context, clubID string) ([]entity.EventTemplate, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type publishService interface {
+	Publish(ctx context.Context, event *entity.Event) error
+	UpdatePublication(ctx context.Context, event *entity.Event) error
+	NotifyFollowers(ctx context.Context, event *entity.Event) error
+}
+
+type clubFollowerService interface {
+	CountByClubID(ctx context.Context, clubID string) (int64, error)
+}
+
+type applicationService interface {
+	GetNext(ctx context.Context, eventID string) (*entity.EventApplication, error)
+	CountByEventID(ctx context.Context, eventID string) (int, error)
+	Approve(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
+	Reject(ctx context.Context, eventID string, userID int64, reason string) error
+}
+
+type eventGuestService interface {
+	CountByEventID(ctx context.Context, eventID string) (int, error)
+}
+
+type checkInService interface {
+	CountRejectedByEventID(ctx context.Context, eventID string) (int, error)
+}
+
+type scannerService interface {
+	CreateInvite(ctx context.Context, event *entity.Event) (*entity.Event, error)
+	ResetInvite(ctx context.Context, event *entity.Event) (*entity.Event, error)
+	GetUsersByEventID(ctx context.Context, eventID string) ([]entity.User, error)
+}
+
+type questionnaireService interface {
+	AnswersToXLSX(ctx context.Context, event *entity.Event, locale string) (*bytes.Buffer, error)
+}
+
+type venueService interface {
+	Get(ctx context.Context, id string) (*entity.Venue, error)
+	GetAll(ctx context.Context) ([]entity.Venue, error)
+	GetConflicts(ctx context.Context, event entity.Event) (*entity.Venue, []entity.Event, error)
 }
 
 type Handler struct {
@@ -78,27 +176,48 @@ type Handler struct {
 	logger *types.Logger
 	input  *intele.InputManager
 
-	eventsStorage *events.Storage
+	eventsStorage    *events.Storage
+	callbacksStorage callbacks.CallbackStorage
 
 	clubService             clubService
 	clubOwnerService        clubOwnerService
 	userService             userService
 	eventService            eventService
 	eventParticipantService eventParticipantService
+	waitlistService         waitlistService
+	applicationService      applicationService
+	eventGuestService       eventGuestService
+	questionnaireService    questionnaireService
+	checkInService          checkInService
+	scannerService          scannerService
+	eventSeriesService      eventSeriesService
+	calendarService         calendarService
+	analyticsService        analyticsService
 	qrService               qrService
 	notificationService     notificationService
+	mailingService          mailingService
+	scheduledMailingService scheduledMailingService
+	publishService          publishService
+	clubFollowerService     clubFollowerService
+	eventTemplateService    eventTemplateService
+	venueService            venueService
 
 	mailingChannelID int64
+
+	// eventQRPresenters holds the running event QR presenters by their messages
+	eventQRPresenters *sync.Map
 }
 
 func NewHandler(b *bot.Bot) *Handler {
 	clubStorage := postgres.NewClubStorage(b.DB)
 	clubOwnerStorage := postgres.NewClubOwnerStorage(b.DB)
 	userStorage := postgres.NewUserStorage(b.DB)
+	clubFollowerStorage := postgres.NewClubFollowerStorage(b.DB)
 	eventStorage := postgres.NewEventStorage(b.DB)
 	eventParticipantStorage := postgres.NewEventParticipantStorage(b.DB)
 
 	eventSrvc := service.NewEventService(eventStorage)
+	mailingSrvc := service.NewMailingService(b.Bot, b.Layout, b.Logger, b.Redis.Mailings, 0)
 
 	qrSrvc, err := service.NewQrService(
 		b.Bot,
@@ -107,6 +226,8 @@ func NewHandler(b *bot.Bot) *Handler {
 		eventSrvc,
 		viper.GetInt64("bot.qr.channel-id"),
 		viper.GetString("settings.qr.logo-path"),
+		viper.GetString("settings.qr.event-secret"),
+		viper.GetDuration("settings.qr.event-period"),
 	)
 	if err != nil {
 		b.Logger.Fatalf("failed to create qr service: %v", err)
@@ -117,25 +238,95 @@ func NewHandler(b *bot.Bot) *Handler {
 		logger: b.Logger,
 		input:  b.Input,
 
-		eventsStorage: b.Redis.Events,
+		eventsStorage:    b.Redis.Events,
+		callbacksStorage: b.Redis.Callbacks,
 
 		clubService:             service.NewClubService(clubStorage),
 		clubOwnerService:        service.NewClubOwnerService(clubOwnerStorage, userStorage),
 		userService:             service.NewUserService(userStorage, nil, nil, nil, ""),
 		eventService:            eventSrvc,
-		eventParticipantService: service.NewEventParticipantService(nil, nil, nil, eventParticipantStorage, nil, nil, nil, nil, nil, 0),
-		qrService:               qrSrvc,
-		notificationService: service.NewNotifyService(
+		eventParticipantService: service.NewEventParticipantService(nil, nil, nil, eventParticipantStorage, nil, nil, nil, nil, nil, "", ""),
+		waitlistService: service.NewWaitlistService(
 			b.Bot,
 			b.Layout,
 			b.Logger,
-			service.NewClubOwnerService(clubOwnerStorage, userStorage),
+			postgres.NewEventWaitlistStorage(b.DB),
+			eventStorage,
+			eventParticipantStorage,
+			userStorage,
+			viper.GetDuration("settings.waitlist.offer-ttl"),
+		),
+		applicationService: service.NewApplicationService(
+			b.Bot,
+			b.Layout,
+			b.Logger,
+			postgres.NewEventApplicationStorage(b.DB),
+			eventStorage,
+			eventParticipantStorage,
+			userStorage,
+		),
+		eventGuestService: service.NewEventGuestService(postgres.NewEventGuestStorage(b.DB)),
+		questionnaireService: service.NewQuestionnaireService(
+			b.Layout,
+			postgres.NewEventAnswersStorage(b.DB),
+			userStorage,
+		),
+		checkInService: service.NewCheckInService(postgres.NewCheckInAttemptStorage(b.DB)),
+		scannerService: service.NewScannerService(postgres.NewEventScannerStorage(b.DB), eventStorage),
+		eventSeriesService: service.NewEventSeriesService(
+			b.Logger,
+			postgres.NewEventSeriesStorage(b.DB),
+			eventStorage,
+			viper.GetDuration("settings.series.horizon"),
+		),
+		calendarService: service.NewCalendarService(
 			nil,
 			nil,
+			nil,
+			nil,
+			viper.GetString("settings.http.public-url"),
+		),
+		analyticsService: service.NewAnalyticsService(
+			b.Layout,
+			eventStorage,
 			eventParticipantStorage,
 		),
+		qrService: qrSrvc,
+		notificationService: service.NewNotifyService(
+			b.Bot,
+			b.Layout,
+			b.Logger,
+			service.NewClubOwnerService(clubOwnerStorage, userStorage),
+			nil,
+			postgres.NewNotificationStorage(b.DB),
+			userStorage,
+			mailingSrvc,
+		),
+		mailingService: mailingSrvc,
+		scheduledMailingService: service.NewScheduledMailingService(
+			b.Bot,
+			b.Logger,
+			postgres.NewScheduledMailingStorage(b.DB),
+			userStorage,
+			mailingSrvc,
+			viper.GetInt64("bot.mailing.channel-id"),
+		),
+		publishService: service.NewPublishService(
+			b.Logger,
+			eventStorage,
+			clubStorage,
+			userStorage,
+			clubFollowerStorage,
+			mailingSrvc,
+			b.Bot.Me.Username,
+		),
+		clubFollowerService:  service.NewClubFollowerService(clubFollowerStorage),
+		eventTemplateService: service.NewEventTemplateService(postgres.NewEventTemplateStorage(b.DB)),
+		venueService:         service.NewVenueService(postgres.NewVenueStorage(b.DB), postgres.NewEventStorage(b.DB)),
 
 		mailingChannelID: viper.GetInt64("bot.mailing.channel-id"),
+
+		eventQRPresenters: &sync.Map{},
 	}
 }
 
@@ -215,6 +406,15 @@ func (h Handler) clubMenu(c tele.Context) error {
 		)
 	}
 
+	followersCount, err := h.clubFollowerService.CountByClubID(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club followers count: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
 	menuMarkup := h.layout.Markup(c, "clubOwner:club:menu", struct {
 		ID string
 	}{
@@ -230,11 +430,13 @@ func (h Handler) clubMenu(c tele.Context) error {
 
 	return c.Edit(
 		banner.ClubOwner.Caption(h.layout.Text(c, "club_owner_club_menu_text", struct {
-			Club   entity.Club
-			Owners []dto.ClubOwner
+			Club           entity.Club
+			Owners         []dto.ClubOwner
+			FollowersCount int64
 		}{
-			Club:   *club,
-			Owners: clubOwners,
+			Club:           *club,
+			Owners:         clubOwners,
+			FollowersCount: followersCount,
 		})),
 		menuMarkup,
 	)
@@ -265,8 +467,10 @@ func (h Handler) clubMailing(c tele.Context) error {
 	inputCollector.Collect(c.Message())
 
 	var (
-		message interface{}
-		done    bool
+		message     interface{}
+		mailing     dto.Mailing
+		mailingText string
+		done        bool
 	)
 	for !done {
 		h.logger.Debug("waiting for input")
@@ -308,22 +512,22 @@ func (h Handler) clubMailing(c tele.Context) error {
 				}),
 			)
 		case validator.MailingText(utils.GetMessageText(response.Message), nil):
-			message = utils.ChangeMessageText(
-				response.Message,
-				h.layout.Text(c, "club_mailing", struct {
-					ClubName string
-					Text     string
-				}{
-					ClubName: club.Name,
-					Text:     utils.GetMessageText(response.Message),
-				}),
-			)
+			mailingData := struct {
+				ClubName string
+				Text     string
+			}{
+				ClubName: club.Name,
+				Text:     utils.GetMessageText(response.Message),
+			}
+			mailing = h.newMailing(c, club.ID, response.Message, "club_mailing", mailingData)
+			mailingText = mailingData.Text
+			message = utils.ChangeMessageText(response.Message, h.layout.Text(c, "club_mailing", mailingData))
 			done = true
 		}
 	}
 	_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
 
-	isCorrectMarkup := h.layout.Markup(c, "clubOwner:isMailingCorrect")
+	isCorrectMarkup := h.layout.Markup(c, "clubOwner:club:isMailingCorrect")
 	confirmMessage, err := c.Bot().Send(
 		c.Chat(),
 		message,
@@ -347,6 +551,7 @@ func (h Handler) clubMailing(c tele.Context) error {
 		0,
 		h.layout.Callback("clubOwner:confirmMailing"),
 		h.layout.Callback("clubOwner:cancelMailing"),
+		h.layout.Callback("clubOwner:scheduleMailing"),
 	)
 	_ = c.Bot().Delete(confirmMessage)
 	if err != nil {
@@ -384,6 +589,17 @@ func (h Handler) clubMailing(c tele.Context) error {
 		)
 	}
 
+	if strings.Contains(response.Callback.Data, "schedule") {
+		return h.scheduleClubMailing(c, club, &entity.ScheduledMailing{
+			ClubID:       club.ID,
+			AuthorID:     mailing.AuthorID,
+			AuthorLocale: mailing.AuthorLocale,
+			Text:         mailingText,
+			MediaType:    mailing.MediaType,
+			FileID:       mailing.FileID,
+		})
+	}
+
 	h.logger.Infof("(user: %d) sending club mailing (club_id=%s)", c.Sender().ID, club.ID)
 	clubUsers, err := h.userService.GetUsersByClubID(context.Background(), club.ID)
 	if err != nil {
@@ -398,29 +614,25 @@ func (h Handler) clubMailing(c tele.Context) error {
 		)
 	}
 
-	loading, _ := c.Bot().Send(c.Chat(), h.layout.Text(c, "loading"))
+	recipients := make([]entity.User, 0, len(clubUsers))
 	for _, user := range clubUsers {
 		if user.IsMailingAllowed(club.ID) {
-			chat, err := c.Bot().ChatByID(user.ID)
-			if err != nil {
-				continue
-			}
-
-			_, _ = c.Bot().Send(
-				chat,
-				message,
-				h.layout.Markup(c, "mailing", struct {
-					ClubID  string
-					Allowed bool
-				}{
-					ClubID:  club.ID,
-					Allowed: true,
-				}),
-			)
+			recipients = append(recipients, user)
 		}
 	}
+	if _, err = h.mailingService.Send(mailing, recipients); err != nil {
+		h.logger.Errorf("(user: %d) error while queue mailing: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: club.ID,
+			}),
+		)
+	}
 
-	h.logger.Infof("(user: %d) club mailing sent (club_id=%s)", c.Sender().ID, club.ID)
+	h.logger.Infof("(user: %d) club mailing queued (club_id=%s)", c.Sender().ID, club.ID)
 
 	mailingChannel, err := c.Bot().ChatByID(h.mailingChannelID)
 	if err != nil {
@@ -435,7 +647,6 @@ func (h Handler) clubMailing(c tele.Context) error {
 		}
 	}
 
-	_ = c.Bot().Delete(loading)
 	return c.Send(
 		banner.ClubOwner.Caption(h.layout.Text(c, "mailing_sent")),
 		h.layout.Markup(c, "clubOwner:club:back", struct {
@@ -446,6 +657,19 @@ func (h Handler) clubMailing(c tele.Context) error {
 	)
 }
 
+// newMailing creates the mailing of the club from the owner message, the text is rendered in every language
+func (h Handler) newMailing(c tele.Context, clubID string, msg *tele.Message, key string, data interface{}) dto.Mailing {
+	locale, _ := h.layout.Locale(c)
+	mailing := dto.Mailing{
+		ClubID:       clubID,
+		AuthorID:     c.Sender().ID,
+		AuthorLocale: locale,
+		Texts:        h.mailingService.Texts(key, data),
+	}
+	mailing.MediaType, mailing.FileID = utils.GetMessageMedia(msg)
+	return mailing
+}
+
 func (h Handler) clubSettings(c tele.Context) error {
 	if c.Callback().Data == "" {
 		return errorz.ErrInvalidCallbackData
@@ -472,11 +696,15 @@ func (h Handler) clubSettings(c tele.Context) error {
 
 	return c.Edit(
 		banner.ClubOwner.Caption(h.layout.Text(c, "club_settings_text", struct {
-			Club   entity.Club
-			Owners []dto.ClubOwner
+			Club        entity.Club
+			Owners      []dto.ClubOwner
+			CalendarURL string
+			EventsURL   string
 		}{
-			Club:   *club,
-			Owners: clubOwners,
+			Club:        *club,
+			Owners:      clubOwners,
+			CalendarURL: h.calendarService.ClubFeedURL(club.ID),
+			EventsURL:   h.calendarService.ClubEventsURL(club.ID),
 		})),
 		h.layout.Markup(c, "clubOwner:club:settings", struct {
 			ID string
@@ -1054,6 +1282,23 @@ func (h Handler) createEvent(c tele.Context) error {
 			},
 			callbackBtn: nil,
 		},
+		{
+			promptKey: "input_event_cancellation_end",
+			objectFunc: func() interface{} {
+				return struct{}{}
+			},
+			errorKey:  "invalid_event_cancellation_end",
+			result:    new(string),
+			validator: validator.EventCancellationEnd,
+			paramsFunc: func(params map[string]interface{}) map[string]interface{} {
+				if params == nil {
+					params = make(map[string]interface{})
+				}
+				params["startTime"] = *steps[3].result
+				return params
+			},
+			callbackBtn: h.layout.Button(c, "clubOwner:create_event:cancellation_end_skip"),
+		},
 		{
 			promptKey: "input_after_registration_text",
 			objectFunc: func() interface{} {
@@ -1173,6 +1418,8 @@ func (h Handler) createEvent(c tele.Context) error {
 		eventEndTimeStr              string
 		eventRegistrationEndTime     time.Time
 		eventRegistrationEndTimeStr  string
+		eventCancellationEndTime     time.Time
+		eventCancellationEndTimeStr  string
 		eventAfterRegistrationText   string
 		eventMaxParticipants         int
 		eventMaxExpectedParticipants int
@@ -1192,9 +1439,16 @@ func (h Handler) createEvent(c tele.Context) error {
 	eventRegistrationEndTime, _ = time.ParseInLocation(timeLayout, *steps[5].result, location.Location())
 	eventRegistrationEndTimeStr = eventRegistrationEndTime.Format(timeLayout)
 
-	eventAfterRegistrationText = *steps[6].result
-	eventMaxParticipants, _ = strconv.Atoi(*steps[7].result)
-	eventMaxExpectedParticipants, _ = strconv.Atoi(*steps[8].result)
+	eventCancellationEndTime, err = time.ParseInLocation(timeLayout, *steps[6].result, location.Location())
+	eventCancellationEndTimeStr = eventCancellationEndTime.Format(timeLayout)
+	if err != nil {
+		eventCancellationEndTime = time.Time{}
+		eventCancellationEndTimeStr = ""
+	}
+
+	eventAfterRegistrationText = *steps[7].result
+	eventMaxParticipants, _ = strconv.Atoi(*steps[8].result)
+	eventMaxExpectedParticipants, _ = strconv.Atoi(*steps[9].result)
 
 	event := entity.Event{
 		ClubID:                club.ID,
@@ -1204,17 +1458,35 @@ func (h Handler) createEvent(c tele.Context) error {
 		StartTime:             eventStartTime,
 		EndTime:               eventEndTime,
 		RegistrationEnd:       eventRegistrationEndTime,
+		CancellationEnd:       eventCancellationEndTime,
 		AfterRegistrationText: eventAfterRegistrationText,
 		MaxParticipants:       eventMaxParticipants,
 		ExpectedParticipants:  eventMaxExpectedParticipants,
 	}
 	h.eventsStorage.Set(c.Sender().ID, event, 0)
 
+	venues, err := h.venueService.GetAll(context.Background())
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get venues: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+
 	markup := h.layout.Markup(c, "clubOwner:createClub:confirm", struct {
 		ID string
 	}{
 		ID: clubID,
 	})
+	markup.InlineKeyboard = append(
+		append(h.venueRows(c, clubID, venues, event.VenueID), h.recurrenceRow(c, clubID, 0)),
+		markup.InlineKeyboard...,
+	)
 
 	var row []tele.InlineButton
 	for _, role := range club.AllowedRoles {
@@ -1240,12 +1512,16 @@ func (h Handler) createEvent(c tele.Context) error {
 		Name                  string
 		Description           string
 		Location              string
+		Venue                 string
+		Conflicts             string
 		StartTime             string
 		EndTime               string
 		RegistrationEnd       string
+		CancellationEnd       string
 		AfterRegistrationText string
 		MaxParticipants       int
 		ExpectedParticipants  int
+		Recurrence            string
 	}{
 		Name:                  event.Name,
 		Description:           event.Description,
@@ -1253,9 +1529,12 @@ func (h Handler) createEvent(c tele.Context) error {
 		StartTime:             eventStartTimeStr,
 		EndTime:               eventEndTimeStr,
 		RegistrationEnd:       eventRegistrationEndTimeStr,
+		CancellationEnd:       eventCancellationEndTimeStr,
 		AfterRegistrationText: event.AfterRegistrationText,
 		MaxParticipants:       event.MaxParticipants,
 		ExpectedParticipants:  event.ExpectedParticipants,
+		Venue:                 venueName(venues, event.VenueID),
+		Recurrence:            h.recurrenceText(c, 0),
 	}
 
 	return c.Send(
@@ -1315,6 +1594,74 @@ func (h Handler) eventAllowedRoles(c tele.Context) error {
 
 	h.eventsStorage.Set(c.Sender().ID, event, 0)
 
+	return h.editEventConfirmation(c, club, event)
+}
+
+func (h Handler) eventRecurrence(c tele.Context) error {
+	clubID := c.Callback().Data
+	if clubID == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+
+	event, err := h.eventsStorage.Get(c.Sender().ID)
+	if err != nil {
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+
+	// cycles through: once -> every week -> every two weeks
+	interval := (h.eventsStorage.GetRecurrence(c.Sender().ID) + 1) % (maxRecurrenceInterval + 1)
+	h.eventsStorage.SetRecurrence(c.Sender().ID, interval, 0)
+
+	return h.editEventConfirmation(c, club, event)
+}
+
+// editEventConfirmation shows the event draft with the roles and recurrence pickers
+func (h Handler) editEventConfirmation(c tele.Context, club *entity.Club, event entity.Event) error {
+	return h.showEventConfirmation(c, c.Edit, club, event)
+}
+
+// showEventConfirmation sends the event draft with the roles and recurrence pickers by send
+func (h Handler) showEventConfirmation(
+	c tele.Context,
+	send func(what interface{}, opts ...interface{}) error,
+	club *entity.Club,
+	event entity.Event,
+) error {
+	interval := h.eventsStorage.GetRecurrence(c.Sender().ID)
+
+	venues, err := h.venueService.GetAll(context.Background())
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get venues: %v", c.Sender().ID, err)
+		return send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: club.ID,
+			}),
+		)
+	}
+
 	markup := h.layout.Markup(c, "clubOwner:createClub:confirm", struct {
 		ID string
 	}{
@@ -1322,7 +1669,7 @@ func (h Handler) eventAllowedRoles(c tele.Context) error {
 	})
 
 	var row []tele.InlineButton
-	for _, role = range club.AllowedRoles {
+	for _, role := range club.AllowedRoles {
 		row = append(row, []tele.InlineButton{*h.layout.Button(c, "clubOwner:create_event:role", struct {
 			Role     entity.Role
 			ID       string
@@ -1336,11 +1683,25 @@ func (h Handler) eventAllowedRoles(c tele.Context) error {
 		}).Inline()}...)
 	}
 
+	rows := append([][]tele.InlineButton{row}, h.venueRows(c, club.ID, venues, event.VenueID)...)
 	markup.InlineKeyboard = append(
-		[][]tele.InlineButton{row},
+		append(rows, h.recurrenceRow(c, club.ID, interval)),
 		markup.InlineKeyboard...,
 	)
 
+	_, conflicts, err := h.venueConflicts(c, event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get venue conflicts: %v", c.Sender().ID, err)
+		return send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: club.ID,
+			}),
+		)
+	}
+
 	const timeLayout = "02.01.2006 15:04"
 
 	eventTimeStr := event.EndTime.Format(timeLayout)
@@ -1348,16 +1709,25 @@ func (h Handler) eventAllowedRoles(c tele.Context) error {
 		eventTimeStr = ""
 	}
 
+	cancellationEndStr := event.CancellationEnd.Format(timeLayout)
+	if event.CancellationEnd.Year() == 1 {
+		cancellationEndStr = ""
+	}
+
 	confirmationPayload := struct {
 		Name                  string
 		Description           string
 		Location              string
+		Venue                 string
+		Conflicts             string
 		StartTime             string
 		EndTime               string
 		RegistrationEnd       string
+		CancellationEnd       string
 		AfterRegistrationText string
 		MaxParticipants       int
 		ExpectedParticipants  int
+		Recurrence            string
 	}{
 		Name:                  event.Name,
 		Description:           event.Description,
@@ -1365,12 +1735,16 @@ func (h Handler) eventAllowedRoles(c tele.Context) error {
 		StartTime:             event.StartTime.Format(timeLayout),
 		EndTime:               eventTimeStr,
 		RegistrationEnd:       event.RegistrationEnd.Format(timeLayout),
+		CancellationEnd:       cancellationEndStr,
 		AfterRegistrationText: event.AfterRegistrationText,
 		MaxParticipants:       event.MaxParticipants,
 		ExpectedParticipants:  event.ExpectedParticipants,
+		Venue:                 venueName(venues, event.VenueID),
+		Conflicts:             conflicts,
+		Recurrence:            h.recurrenceText(c, interval),
 	}
 
-	return c.Edit(
+	return send(
 		banner.ClubOwner.Caption(h.layout.Text(c, "event_confirmation", confirmationPayload)),
 		markup,
 	)
@@ -1401,12 +1775,74 @@ func (h Handler) confirmEventCreation(c tele.Context) error {
 		)
 	}
 
+	venue, fits, err := h.checkVenueCapacity(context.Background(), event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get venue: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+	if !fits {
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_exceeds_venue_capacity", venue)),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	venue, conflicts, err := h.venueConflicts(c, event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get venue conflicts: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+	if conflicts != "" && venue.ConflictMode == entity.VenueConflictBlock {
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_venue_conflict_blocked", struct {
+				Name      string
+				Buffer    int
+				Conflicts string
+			}{
+				Name:      venue.Name,
+				Buffer:    bufferMinutes(venue),
+				Conflicts: conflicts,
+			})),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	// Drafts are published one by one, so the series can't be saved as a draft
+	interval := h.eventsStorage.GetRecurrence(c.Sender().ID)
+	event.IsDraft = c.Callback().Unique == "cOwner_event_draft"
+	if event.IsDraft && interval > 0 {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_series_draft"),
+			ShowAlert: true,
+		})
+	}
+
 	event.StartTime = event.StartTime.UTC()
 	event.EndTime = event.EndTime.UTC()
 	event.RegistrationEnd = event.RegistrationEnd.UTC()
+	event.CancellationEnd = event.CancellationEnd.UTC()
 
-	_, err = h.eventService.Create(context.Background(), &event)
+	if interval > 0 {
+		_, err = h.eventSeriesService.Create(context.Background(), &event, interval)
+	} else {
+		_, err = h.eventService.Create(context.Background(), &event)
+	}
 	if err != nil {
+		h.logger.Errorf("(user: %d) error while create event: %v", c.Sender().ID, err)
 		return c.Edit(
 			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
 			h.layout.Markup(c, "clubOwner:club:back", struct {
@@ -1419,8 +1855,19 @@ func (h Handler) confirmEventCreation(c tele.Context) error {
 
 	h.eventsStorage.Clear(c.Sender().ID)
 
+	// The series is announced to the followers by its first occurrence
+	if !event.IsDraft {
+		if err = h.publishService.NotifyFollowers(context.Background(), &event); err != nil {
+			h.logger.Errorf("(user: %d) error while notify club followers (event_id=%s): %v", c.Sender().ID, event.ID, err)
+		}
+	}
+
+	createdText := "event_created"
+	if event.IsDraft {
+		createdText = "event_draft_created"
+	}
 	return c.Edit(
-		banner.ClubOwner.Caption(h.layout.Text(c, "event_created", struct {
+		banner.ClubOwner.Caption(h.layout.Text(c, createdText, struct {
 			Name string
 		}{
 			Name: event.Name,
@@ -1487,15 +1934,17 @@ func (h Handler) eventsList(c tele.Context) error {
 	markup := c.Bot().NewMarkup()
 	for _, event := range events {
 		rows = append(rows, markup.Row(*h.layout.Button(c, "clubOwner:events:event", struct {
-			ID     string
-			Page   int
-			Name   string
-			IsOver bool
+			ID      string
+			Page    int
+			Name    string
+			IsOver  bool
+			IsDraft bool
 		}{
-			ID:     event.ID,
-			Page:   p,
-			Name:   event.Name,
-			IsOver: event.IsOver(0),
+			ID:      event.ID,
+			Page:    p,
+			Name:    event.Name,
+			IsOver:  event.IsOver(0),
+			IsDraft: event.IsDraft,
 		})))
 	}
 	pagesCount := (int(eventsCount) - 1) / eventsOnPage
@@ -1633,6 +2082,52 @@ func (h Handler) event(c tele.Context) error {
 		)
 	}
 
+	waitlistCount, err := h.waitlistService.CountByEventID(context.Background(), event.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get waitlist count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:events:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ClubID,
+				Page: page,
+			}),
+		)
+	}
+
+	guestsCount, err := h.eventGuestService.CountByEventID(context.Background(), event.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get guests count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:events:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ClubID,
+				Page: page,
+			}),
+		)
+	}
+
+	checkInRejectedCount, err := h.checkInService.CountRejectedByEventID(context.Background(), event.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get rejected check-ins count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:events:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ClubID,
+				Page: page,
+			}),
+		)
+	}
+	checkInOpens, checkInCloses := event.CheckInWindow()
+
 	eventMarkup := h.layout.Markup(c, "clubOwner:event:menu", struct {
 		ID     string
 		ClubID string
@@ -1656,11 +2151,61 @@ func (h Handler) event(c tele.Context) error {
 		)
 	}
 
+	if event.SeriesID != nil {
+		eventMarkup.InlineKeyboard = slices.Insert(
+			eventMarkup.InlineKeyboard,
+			len(eventMarkup.InlineKeyboard)-1,
+			[]tele.InlineButton{*h.layout.Button(c, "clubOwner:event:series", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}).Inline()},
+		)
+	}
+
+	applicationsCount, err := h.applicationService.CountByEventID(context.Background(), event.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while count event applications: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:events:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ClubID,
+				Page: page,
+			}),
+		)
+	}
+
+	// Applications left after the approval was turned off can still be reviewed
+	if event.RequiresApproval || applicationsCount > 0 {
+		eventMarkup.InlineKeyboard = append(
+			[][]tele.InlineButton{{*h.layout.Button(c, "clubOwner:event:applications", struct {
+				ID    string
+				Page  string
+				Count int
+			}{
+				ID:    eventID,
+				Page:  page,
+				Count: applicationsCount,
+			}).Inline()}},
+			eventMarkup.InlineKeyboard...,
+		)
+	}
+
 	endTime := event.EndTime.In(location.Location()).Format("02.01.2006 15:04")
 	if event.EndTime.Year() == 1 {
 		endTime = ""
 	}
 
+	cancellationEnd := event.CancellationEnd.In(location.Location()).Format("02.01.2006 15:04")
+	if event.CancellationEnd.Year() == 1 {
+		cancellationEnd = ""
+	}
+
 	return c.Edit(
 		banner.Events.Caption(h.layout.Text(c, "club_owner_event_text", struct {
 			Name                  string
@@ -1669,12 +2214,23 @@ func (h Handler) event(c tele.Context) error {
 			StartTime             string
 			EndTime               string
 			RegistrationEnd       string
+			CancellationEnd       string
 			MaxParticipants       int
 			VisitedCount          int
+			WaitlistCount         int
+			GuestsCount           int
+			CheckInOpens          string
+			CheckInCloses         string
+			CheckInRejectedCount  int
 			ParticipantsCount     int
 			AfterRegistrationText string
 			IsRegistered          bool
 			Link                  string
+			IsCancelled           bool
+			CancelReason          string
+			IsDraft               bool
+			PublishAt             string
+			Announce              bool
 		}{
 			Name:                  event.Name,
 			Description:           event.Description,
@@ -1682,11 +2238,22 @@ func (h Handler) event(c tele.Context) error {
 			StartTime:             event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
 			EndTime:               endTime,
 			RegistrationEnd:       event.RegistrationEnd.In(location.Location()).Format("02.01.2006 15:04"),
+			CancellationEnd:       cancellationEnd,
 			MaxParticipants:       event.MaxParticipants,
 			ParticipantsCount:     registeredUsersCount,
 			VisitedCount:          visitedUsersCount,
+			WaitlistCount:         waitlistCount,
+			GuestsCount:           guestsCount,
+			CheckInOpens:          checkInOpens.In(location.Location()).Format("02.01.2006 15:04"),
+			CheckInCloses:         checkInCloses.In(location.Location()).Format("02.01.2006 15:04"),
+			CheckInRejectedCount:  checkInRejectedCount,
 			AfterRegistrationText: event.AfterRegistrationText,
 			Link:                  event.Link(c.Bot().Me.Username),
+			IsCancelled:           event.IsCancelled(),
+			CancelReason:          event.CancelReason,
+			IsDraft:               event.IsDraft,
+			PublishAt:             formatPublishAt(event.PublishAt),
+			Announce:              event.Announce,
 		})),
 		eventMarkup,
 	)
@@ -1748,11 +2315,62 @@ func (h Handler) eventSettings(c tele.Context) error {
 		)
 	}
 
+	waitlistCount, err := h.waitlistService.CountByEventID(context.Background(), event.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get waitlist count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:events:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ClubID,
+				Page: page,
+			}),
+		)
+	}
+
+	guestsCount, err := h.eventGuestService.CountByEventID(context.Background(), event.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get guests count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:events:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ClubID,
+				Page: page,
+			}),
+		)
+	}
+
+	checkInRejectedCount, err := h.checkInService.CountRejectedByEventID(context.Background(), event.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get rejected check-ins count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:events:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ClubID,
+				Page: page,
+			}),
+		)
+	}
+	checkInOpens, checkInCloses := event.CheckInWindow()
+
 	endTime := event.EndTime.In(location.Location()).Format("02.01.2006 15:04")
 	if event.EndTime.Year() == 1 {
 		endTime = ""
 	}
 
+	cancellationEnd := event.CancellationEnd.In(location.Location()).Format("02.01.2006 15:04")
+	if event.CancellationEnd.Year() == 1 {
+		cancellationEnd = ""
+	}
+
 	return c.Edit(
 		banner.ClubOwner.Caption(h.layout.Text(c, "club_owner_event_text", struct {
 			Name                  string
@@ -1761,12 +2379,23 @@ func (h Handler) eventSettings(c tele.Context) error {
 			StartTime             string
 			EndTime               string
 			RegistrationEnd       string
+			CancellationEnd       string
 			MaxParticipants       int
 			ParticipantsCount     int
 			VisitedCount          int
+			WaitlistCount         int
+			GuestsCount           int
+			CheckInOpens          string
+			CheckInCloses         string
+			CheckInRejectedCount  int
 			AfterRegistrationText string
 			IsRegistered          bool
 			Link                  string
+			IsCancelled           bool
+			CancelReason          string
+			IsDraft               bool
+			PublishAt             string
+			Announce              bool
 		}{
 			Name:                  event.Name,
 			Description:           event.Description,
@@ -1774,18 +2403,31 @@ func (h Handler) eventSettings(c tele.Context) error {
 			StartTime:             event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
 			EndTime:               endTime,
 			RegistrationEnd:       event.RegistrationEnd.In(location.Location()).Format("02.01.2006 15:04"),
+			CancellationEnd:       cancellationEnd,
 			MaxParticipants:       event.MaxParticipants,
 			ParticipantsCount:     registeredUsersCount,
 			VisitedCount:          visitedUsersCount,
+			WaitlistCount:         waitlistCount,
+			GuestsCount:           guestsCount,
+			CheckInOpens:          checkInOpens.In(location.Location()).Format("02.01.2006 15:04"),
+			CheckInCloses:         checkInCloses.In(location.Location()).Format("02.01.2006 15:04"),
+			CheckInRejectedCount:  checkInRejectedCount,
 			AfterRegistrationText: event.AfterRegistrationText,
 			Link:                  event.Link(c.Bot().Me.Username),
+			IsCancelled:           event.IsCancelled(),
+			CancelReason:          event.CancelReason,
+			IsDraft:               event.IsDraft,
+			PublishAt:             formatPublishAt(event.PublishAt),
+			Announce:              event.Announce,
 		})),
 		h.layout.Markup(c, "clubOwner:event:settings", struct {
-			ID   string
-			Page string
+			ID               string
+			Page             string
+			RequiresApproval bool
 		}{
-			ID:   eventID,
-			Page: page,
+			ID:               eventID,
+			Page:             page,
+			RequiresApproval: event.RequiresApproval,
 		}))
 }
 
@@ -1904,7 +2546,7 @@ func (h Handler) editEventName(c tele.Context) error {
 	}
 
 	err = h.notificationService.SendEventUpdate(eventID,
-		h.layout.Text(c, "event_notification_update", struct {
+		"event_notification_update", struct {
 			Name                  string
 			OldName               string
 			Description           string
@@ -1913,8 +2555,7 @@ func (h Handler) editEventName(c tele.Context) error {
 		}{
 			Name:    event.Name,
 			OldName: oldName,
-		}),
-		h.layout.Markup(c, "core:hide"),
+		},
 	)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while send event update notification: %v", c.Sender().ID, err)
@@ -2047,7 +2688,7 @@ func (h Handler) editEventDescription(c tele.Context) error {
 	}
 
 	err = h.notificationService.SendEventUpdate(eventID,
-		h.layout.Text(c, "event_notification_update", struct {
+		"event_notification_update", struct {
 			Name                  string
 			OldName               string
 			Description           string
@@ -2056,8 +2697,7 @@ func (h Handler) editEventDescription(c tele.Context) error {
 		}{
 			Name:        event.Name,
 			Description: event.Description,
-		}),
-		h.layout.Markup(c, "core:hide"),
+		},
 	)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while send event update notification: %v", c.Sender().ID, err)
@@ -2190,7 +2830,7 @@ func (h Handler) editEventAfterRegistrationText(c tele.Context) error {
 	}
 
 	err = h.notificationService.SendEventUpdate(eventID,
-		h.layout.Text(c, "event_notification_update", struct {
+		"event_notification_update", struct {
 			Name                  string
 			OldName               string
 			Description           string
@@ -2199,8 +2839,7 @@ func (h Handler) editEventAfterRegistrationText(c tele.Context) error {
 		}{
 			Name:                  event.Name,
 			AfterRegistrationText: event.AfterRegistrationText,
-		}),
-		h.layout.Markup(c, "core:hide"),
+		},
 	)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while send event update notification: %v", c.Sender().ID, err)
@@ -2336,6 +2975,33 @@ func (h Handler) editEventMaxParticipants(c tele.Context) error {
 	}
 
 	event.MaxParticipants = maxParticipants
+	venue, fits, err := h.checkVenueCapacity(context.Background(), *event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get venue: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+	if !fits {
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_exceeds_venue_capacity", venue)),
+			h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
 	_, err = h.eventService.Update(context.Background(), event)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while update event max participants: %v", c.Sender().ID, err)
@@ -2351,8 +3017,14 @@ func (h Handler) editEventMaxParticipants(c tele.Context) error {
 		)
 	}
 
+	// New seats are offered to the waitlist first
+	err = h.waitlistService.PromoteNext(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while promote event waitlist: %v", c.Sender().ID, err)
+	}
+
 	err = h.notificationService.SendEventUpdate(eventID,
-		h.layout.Text(c, "event_notification_update", struct {
+		"event_notification_update", struct {
 			Name                  string
 			OldName               string
 			Description           string
@@ -2363,8 +3035,7 @@ func (h Handler) editEventMaxParticipants(c tele.Context) error {
 			Name:                event.Name,
 			MaxParticipants:     event.MaxParticipants,
 			ParticipantsChanged: true,
-		}),
-		h.layout.Markup(c, "core:hide"),
+		},
 	)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while send event update notification: %v", c.Sender().ID, err)
@@ -2438,6 +3109,7 @@ func (h Handler) mailingRegistered(c tele.Context) error {
 
 	var (
 		message interface{}
+		mailing dto.Mailing
 		done    bool
 	)
 	for !done {
@@ -2486,18 +3158,17 @@ func (h Handler) mailingRegistered(c tele.Context) error {
 				}),
 			)
 		case validator.MailingText(utils.GetMessageText(response.Message), nil):
-			message = utils.ChangeMessageText(
-				response.Message,
-				h.layout.Text(c, "event_mailing", struct {
-					ClubName  string
-					EventName string
-					Text      string
-				}{
-					ClubName:  club.Name,
-					EventName: event.Name,
-					Text:      utils.GetMessageText(response.Message),
-				}),
-			)
+			mailingData := struct {
+				ClubName  string
+				EventName string
+				Text      string
+			}{
+				ClubName:  club.Name,
+				EventName: event.Name,
+				Text:      utils.GetMessageText(response.Message),
+			}
+			mailing = h.newMailing(c, club.ID, response.Message, "event_mailing", mailingData)
+			message = utils.ChangeMessageText(response.Message, h.layout.Text(c, "event_mailing", mailingData))
 			done = true
 		}
 	}
@@ -2588,29 +3259,27 @@ func (h Handler) mailingRegistered(c tele.Context) error {
 		)
 	}
 
-	loading, _ := c.Bot().Send(c.Chat(), h.layout.Text(c, "loading"))
+	recipients := make([]entity.User, 0, len(eventUsers))
 	for _, user := range eventUsers {
 		if user.IsMailingAllowed(club.ID) {
-			chat, err := c.Bot().ChatByID(user.ID)
-			if err != nil {
-				continue
-			}
-
-			_, _ = c.Bot().Send(
-				chat,
-				message,
-				h.layout.Markup(c, "mailing", struct {
-					ClubID  string
-					Allowed bool
-				}{
-					ClubID:  club.ID,
-					Allowed: true,
-				}),
-			)
+			recipients = append(recipients, user)
 		}
 	}
+	if _, err = h.mailingService.Send(mailing, recipients); err != nil {
+		h.logger.Errorf("(user: %d) error while queue mailing: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:mailing:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ID,
+				Page: page,
+			}),
+		)
+	}
 
-	h.logger.Infof("(user: %d) event mailing sent (club_id=%s, event_id=%s)", c.Sender().ID, club.ID, event.ID)
+	h.logger.Infof("(user: %d) event mailing queued (club_id=%s, event_id=%s)", c.Sender().ID, club.ID, event.ID)
 
 	mailingChannel, err := c.Bot().ChatByID(h.mailingChannelID)
 	if err != nil {
@@ -2625,7 +3294,6 @@ func (h Handler) mailingRegistered(c tele.Context) error {
 		}
 	}
 
-	_ = c.Bot().Delete(loading)
 	return c.Send(
 		banner.ClubOwner.Caption(h.layout.Text(c, "mailing_sent")),
 		h.layout.Markup(c, "clubOwner:event:mailing:back", struct {
@@ -2676,6 +3344,7 @@ func (h Handler) mailingVisited(c tele.Context) error {
 
 	var (
 		message interface{}
+		mailing dto.Mailing
 		done    bool
 	)
 	for !done {
@@ -2724,18 +3393,17 @@ func (h Handler) mailingVisited(c tele.Context) error {
 				}),
 			)
 		case validator.MailingText(utils.GetMessageText(response.Message), nil):
-			message = utils.ChangeMessageText(
-				response.Message,
-				h.layout.Text(c, "event_mailing", struct {
-					ClubName  string
-					EventName string
-					Text      string
-				}{
-					ClubName:  club.Name,
-					EventName: event.Name,
-					Text:      utils.GetMessageText(response.Message),
-				}),
-			)
+			mailingData := struct {
+				ClubName  string
+				EventName string
+				Text      string
+			}{
+				ClubName:  club.Name,
+				EventName: event.Name,
+				Text:      utils.GetMessageText(response.Message),
+			}
+			mailing = h.newMailing(c, club.ID, response.Message, "event_mailing", mailingData)
+			message = utils.ChangeMessageText(response.Message, h.layout.Text(c, "event_mailing", mailingData))
 			done = true
 		}
 	}
@@ -2826,29 +3494,27 @@ func (h Handler) mailingVisited(c tele.Context) error {
 		)
 	}
 
-	loading, _ := c.Bot().Send(c.Chat(), h.layout.Text(c, "loading"))
+	recipients := make([]entity.User, 0, len(eventUsers))
 	for _, user := range eventUsers {
 		if user.User.IsMailingAllowed(club.ID) && user.UserVisit {
-			chat, err := c.Bot().ChatByID(user.User.ID)
-			if err != nil {
-				continue
-			}
-
-			_, _ = c.Bot().Send(
-				chat,
-				message,
-				h.layout.Markup(c, "mailing", struct {
-					ClubID  string
-					Allowed bool
-				}{
-					ClubID:  club.ID,
-					Allowed: true,
-				}),
-			)
+			recipients = append(recipients, user.User)
 		}
 	}
+	if _, err = h.mailingService.Send(mailing, recipients); err != nil {
+		h.logger.Errorf("(user: %d) error while queue mailing: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:mailing:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ID,
+				Page: page,
+			}),
+		)
+	}
 
-	h.logger.Infof("(user: %d) event mailing sent (club_id=%s, event_id=%s)", c.Sender().ID, club.ID, event.ID)
+	h.logger.Infof("(user: %d) event mailing queued (club_id=%s, event_id=%s)", c.Sender().ID, club.ID, event.ID)
 
 	mailingChannel, err := c.Bot().ChatByID(h.mailingChannelID)
 	if err != nil {
@@ -2863,7 +3529,6 @@ func (h Handler) mailingVisited(c tele.Context) error {
 		}
 	}
 
-	_ = c.Bot().Delete(loading)
 	return c.Send(
 		banner.ClubOwner.Caption(h.layout.Text(c, "mailing_sent")),
 		h.layout.Markup(c, "clubOwner:event:mailing:back", struct {
@@ -2958,12 +3623,11 @@ func (h Handler) acceptEventDelete(c tele.Context) error {
 	}
 
 	err = h.notificationService.SendEventUpdate(eventID,
-		h.layout.Text(c, "event_notification_delete", struct {
+		"event_notification_delete", struct {
 			Name string
 		}{
 			Name: event.Name,
-		}),
-		h.layout.Markup(c, "core:hide"),
+		},
 	)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while send event delete notification: %v", c.Sender().ID, err)
@@ -3053,6 +3717,52 @@ func (h Handler) declineEventDelete(c tele.Context) error {
 		)
 	}
 
+	waitlistCount, err := h.waitlistService.CountByEventID(context.Background(), event.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get waitlist count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:events:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ClubID,
+				Page: page,
+			}),
+		)
+	}
+
+	guestsCount, err := h.eventGuestService.CountByEventID(context.Background(), event.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get guests count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:events:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ClubID,
+				Page: page,
+			}),
+		)
+	}
+
+	checkInRejectedCount, err := h.checkInService.CountRejectedByEventID(context.Background(), event.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get rejected check-ins count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:events:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ClubID,
+				Page: page,
+			}),
+		)
+	}
+	checkInOpens, checkInCloses := event.CheckInWindow()
+
 	eventMarkup := h.layout.Markup(c, "clubOwner:event:menu", struct {
 		ID     string
 		ClubID string
@@ -3081,6 +3791,11 @@ func (h Handler) declineEventDelete(c tele.Context) error {
 		endTime = ""
 	}
 
+	cancellationEnd := event.CancellationEnd.In(location.Location()).Format("02.01.2006 15:04")
+	if event.CancellationEnd.Year() == 1 {
+		cancellationEnd = ""
+	}
+
 	return c.Edit(
 		banner.ClubOwner.Caption(h.layout.Text(c, "club_owner_event_text", struct {
 			Name                  string
@@ -3089,12 +3804,23 @@ func (h Handler) declineEventDelete(c tele.Context) error {
 			StartTime             string
 			EndTime               string
 			RegistrationEnd       string
+			CancellationEnd       string
 			MaxParticipants       int
 			ParticipantsCount     int
 			VisitedCount          int
+			WaitlistCount         int
+			GuestsCount           int
+			CheckInOpens          string
+			CheckInCloses         string
+			CheckInRejectedCount  int
 			AfterRegistrationText string
 			IsRegistered          bool
 			Link                  string
+			IsCancelled           bool
+			CancelReason          string
+			IsDraft               bool
+			PublishAt             string
+			Announce              bool
 		}{
 			Name:                  event.Name,
 			Description:           event.Description,
@@ -3102,11 +3828,22 @@ func (h Handler) declineEventDelete(c tele.Context) error {
 			StartTime:             event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
 			EndTime:               endTime,
 			RegistrationEnd:       event.RegistrationEnd.In(location.Location()).Format("02.01.2006 15:04"),
+			CancellationEnd:       cancellationEnd,
 			MaxParticipants:       event.MaxParticipants,
 			ParticipantsCount:     registeredUsersCount,
 			VisitedCount:          visitedUsersCount,
+			WaitlistCount:         waitlistCount,
+			GuestsCount:           guestsCount,
+			CheckInOpens:          checkInOpens.In(location.Location()).Format("02.01.2006 15:04"),
+			CheckInCloses:         checkInCloses.In(location.Location()).Format("02.01.2006 15:04"),
+			CheckInRejectedCount:  checkInRejectedCount,
 			AfterRegistrationText: event.AfterRegistrationText,
 			Link:                  event.Link(c.Bot().Me.Username),
+			IsCancelled:           event.IsCancelled(),
+			CancelReason:          event.CancelReason,
+			IsDraft:               event.IsDraft,
+			PublishAt:             formatPublishAt(event.PublishAt),
+			Announce:              event.Announce,
 		})),
 		eventMarkup,
 	)
@@ -3306,8 +4043,7 @@ func (h Handler) eventQRCode(c tele.Context) error {
 		)
 	}
 
-	loading, _ := c.Bot().Send(c.Chat(), h.layout.Text(c, "loading"))
-	file, err := h.qrService.GetEventQR(context.Background(), eventID)
+	qrData, err := h.qrService.GetEventQR(context.Background(), eventID)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while get event QR: %v", c.Sender().ID, err)
 		return c.Edit(
@@ -3321,21 +4057,35 @@ func (h Handler) eventQRCode(c tele.Context) error {
 			}),
 		)
 	}
-	_ = c.Bot().Delete(loading)
 
-	return c.Edit(
+	caption := h.layout.Text(c, "event_qr_text", struct {
+		Name   string
+		Period int
+	}{
+		Name:   event.Name,
+		Period: int(h.qrService.EventQRPeriod().Seconds()),
+	})
+	markup := h.layout.Markup(c, "clubOwner:event:qr:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+	err = c.Edit(
 		&tele.Photo{
-			File:    file,
-			Caption: h.layout.Text(c, "event_qr_text"),
+			File:    tele.FromReader(bytes.NewReader(qrData)),
+			Caption: caption,
 		},
-		h.layout.Markup(c, "clubOwner:event:back", struct {
-			ID   string
-			Page string
-		}{
-			ID:   eventID,
-			Page: page,
-		}),
+		markup,
 	)
+	if err != nil {
+		return err
+	}
+
+	locale, _ := h.layout.Locale(c)
+	h.startEventQRPresenter(c.Bot(), c.Message(), eventID, caption, h.layout.TextLocale(locale, "event_qr_stopped"), markup)
+	return nil
 }
 
 func (h Handler) ClubOwnerSetup(group *tele.Group, middle *middlewares.Handler) {
@@ -3348,7 +4098,10 @@ func (h Handler) ClubOwnerSetup(group *tele.Group, middle *middlewares.Handler)
 	group.Handle(h.layout.Callback("clubOwner:club:create_event"), h.createEvent)
 	group.Handle(h.layout.Callback("clubOwner:create_event:refill"), h.createEvent)
 	group.Handle(h.layout.Callback("clubOwner:create_event:confirm"), h.confirmEventCreation)
+	group.Handle(h.layout.Callback("clubOwner:create_event:draft"), h.confirmEventCreation)
 	group.Handle(h.layout.Callback("clubOwner:create_event:role"), h.eventAllowedRoles)
+	group.Handle(h.layout.Callback("clubOwner:create_event:recurrence"), h.eventRecurrence)
+	group.Handle(h.layout.Callback("clubOwner:create_event:venue"), h.eventVenue)
 	group.Handle(h.layout.Callback("clubOwner:club:back"), h.clubMenu)
 
 	group.Handle(h.layout.Callback("clubOwner:club:events"), h.eventsList)
@@ -3363,18 +4116,84 @@ func (h Handler) ClubOwnerSetup(group *tele.Group, middle *middlewares.Handler)
 	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_description"), h.editEventDescription)
 	group.Handle(h.layout.Callback("clubOwner:event:settings:edit_after_reg_text"), h.editEventAfterRegistrationText)
 	group.Handle(h.layout.Callback("clubOwner:event:settings:edit:max_participants"), h.editEventMaxParticipants)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit:max_guests"), h.editEventMaxGuests)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit:check_in"), h.editEventCheckIn)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit:start"), h.editEventStartTime)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit:end"), h.editEventEndTime)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit:reg_end"), h.editEventRegistrationEnd)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit:location"), h.editEventLocation)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:edit:roles"), h.editEventRoles)
+	group.Handle(h.layout.Callback("clubOwner:event:roles:role"), h.editEventRoles)
+	group.Handle(h.layout.Callback("clubOwner:event:roles:save"), h.saveEventRoles)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:approval"), h.eventApprovalSwitch)
+	group.Handle(h.layout.Callback("clubOwner:event:applications"), h.eventApplications)
+	group.Handle(h.layout.Callback("clubOwner:event:application:approve"), h.approveEventApplication)
+	group.Handle(h.layout.Callback("clubOwner:event:application:reject"), h.rejectEventApplication)
+	group.Handle(h.layout.Callback("clubOwner:event:application:back"), h.eventApplications)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:questionnaire"), h.eventQuestionnaire)
+	group.Handle(h.layout.Callback("clubOwner:event:questionnaire:back"), h.eventQuestionnaire)
+	group.Handle(h.layout.Callback("clubOwner:event:questionnaire:delete"), h.deleteEventQuestion)
+	group.Handle(h.layout.Callback("clubOwner:event:questionnaire:add"), h.addEventQuestion)
+	group.Handle(h.layout.Callback("clubOwner:event:questionnaire:export"), h.exportEventAnswers)
+	group.Handle(h.layout.Callback("clubOwner:event:settings:reminders"), h.eventReminders)
+	group.Handle(h.layout.Callback("clubOwner:event:reminders:reminder"), h.eventReminders)
+	group.Handle(h.layout.Callback("clubOwner:event:reminders:reset"), h.eventReminders)
+	group.Handle(h.layout.Callback("clubOwner:event:cancel"), h.cancelEvent)
+	group.Handle(h.layout.Callback("clubOwner:event:cancel:accept"), h.acceptEventCancel)
 	group.Handle(h.layout.Callback("clubOwner:event:delete"), h.deleteEvent)
 	group.Handle(h.layout.Callback("clubOwner:event:delete:accept"), h.acceptEventDelete)
 	group.Handle(h.layout.Callback("clubOwner:event:delete:decline"), h.declineEventDelete)
+	group.Handle(h.layout.Callback("clubOwner:event:series"), h.eventSeries)
+	group.Handle(h.layout.Callback("clubOwner:event:series:back"), h.eventSeries)
+	group.Handle(h.layout.Callback("clubOwner:event:series:apply"), h.eventSeriesApply)
+	group.Handle(h.layout.Callback("clubOwner:event:series:cancel"), h.eventSeriesCancel)
+	group.Handle(h.layout.Callback("clubOwner:event:series:cancel:accept"), h.eventSeriesCancelAccept)
 	// removed due to legal issues
 	//group.Handle(h.layout.Callback("clubOwner:event:users"), h.users)
 	group.Handle(h.layout.Callback("clubOwner:event:qr"), h.eventQRCode)
+	group.Handle(h.layout.Callback("clubOwner:event:scanners"), h.eventScanners)
+	group.Handle(h.layout.Callback("clubOwner:event:roster"), h.eventRoster)
+	group.Handle(h.layout.Callback("clubOwner:roster:user"), h.switchRosterVisit)
+	group.Handle(h.layout.Callback("clubOwner:roster:prev_page"), h.rosterPage)
+	group.Handle(h.layout.Callback("clubOwner:roster:next_page"), h.rosterPage)
+	group.Handle(h.layout.Callback("clubOwner:roster:back"), h.rosterPage)
+	group.Handle(h.layout.Callback("clubOwner:roster:search"), h.searchRoster)
+	group.Handle(h.layout.Callback("clubOwner:roster:reset_search"), h.resetRosterSearch)
+	group.Handle(h.layout.Callback("clubOwner:event:scanners:reset"), h.resetEventScanners)
+	group.Handle(h.layout.Callback("clubOwner:event:duplicate"), h.eventDuplicate)
+	group.Handle(h.layout.Callback("clubOwner:event:duplicate:new"), h.duplicateEvent)
+	group.Handle(h.layout.Callback("clubOwner:event:duplicate:template"), h.saveEventTemplate)
+	group.Handle(h.layout.Callback("clubOwner:event:publication"), h.eventPublication)
+	group.Handle(h.layout.Callback("clubOwner:event:publication:back"), h.eventPublication)
+	group.Handle(h.layout.Callback("clubOwner:event:publication:now"), h.publishEvent)
+	group.Handle(h.layout.Callback("clubOwner:event:publication:at"), h.editEventPublishAt)
+	group.Handle(h.layout.Callback("clubOwner:event:publication:reset"), h.resetEventPublishAt)
+	group.Handle(h.layout.Callback("clubOwner:event:publication:announce"), h.toggleEventAnnounce)
+	group.Handle(h.layout.Callback("clubOwner:event:qr:back"), h.stopEventQR)
 
 	group.Handle(h.layout.Callback("clubOwner:event:mailing"), h.eventMailing)
 	group.Handle(h.layout.Callback("clubOwner:event:mailing:back"), h.eventMailing)
 	group.Handle(h.layout.Callback("clubOwner:event:mailing:registered"), h.mailingRegistered)
 	group.Handle(h.layout.Callback("clubOwner:event:mailing:visited"), h.mailingVisited)
 	group.Handle(h.layout.Callback("clubOwner:club:mailing"), h.clubMailing)
+	group.Handle(h.layout.Callback("clubOwner:club:templates"), h.clubEventTemplates)
+	group.Handle(h.layout.Callback("clubOwner:templates:back"), h.clubEventTemplates)
+	group.Handle(h.layout.Callback("clubOwner:templates:template"), h.eventTemplate)
+	group.Handle(h.layout.Callback("clubOwner:template:back"), h.eventTemplate)
+	group.Handle(h.layout.Callback("clubOwner:template:create"), h.createEventFromClubTemplate)
+	group.Handle(h.layout.Callback("clubOwner:template:delete"), h.deleteEventTemplate)
+	group.Handle(h.layout.Callback("clubOwner:club:mailings"), h.clubScheduledMailings)
+	group.Handle(h.layout.Callback("clubOwner:mailings:back"), h.clubScheduledMailings)
+	group.Handle(h.layout.Callback("clubOwner:mailings:mailing"), h.scheduledMailing)
+	group.Handle(h.layout.Callback("clubOwner:mailing:back"), h.scheduledMailing)
+	group.Handle(h.layout.Callback("clubOwner:mailing:audience:switch"), h.switchScheduledMailingAudience)
+	group.Handle(h.layout.Callback("clubOwner:mailing:edit_text"), h.editScheduledMailingText)
+	group.Handle(h.layout.Callback("clubOwner:mailing:edit_send_at"), h.editScheduledMailingSendAt)
+	group.Handle(h.layout.Callback("clubOwner:mailing:cancel"), h.cancelScheduledMailing)
+
+	group.Handle(h.layout.Callback("clubOwner:club:analytics"), h.clubAnalytics)
+	group.Handle(h.layout.Callback("clubOwner:club:analytics:count"), h.clubAnalytics)
+	group.Handle(h.layout.Callback("clubOwner:club:analytics:export"), h.clubAnalyticsExport)
 
 	group.Handle(h.layout.Callback("clubOwner:club:settings"), h.clubSettings)
 	group.Handle(h.layout.Callback("clubOwner:club:settings:back"), h.clubSettings)
@@ -3383,6 +4202,8 @@ func (h Handler) ClubOwnerSetup(group *tele.Group, middle *middlewares.Handler)
 	group.Handle(h.layout.Callback("clubOwner:club:settings:add_owner"), h.addOwner)
 	group.Handle(h.layout.Callback("clubOwner:club:settings:warnings"), h.warnings)
 	group.Handle(h.layout.Callback("clubOwner:club:settings:warnings:user"), h.warnings)
+	group.Handle(h.layout.Callback("clubOwner:club:settings:reminders"), h.clubReminders)
+	group.Handle(h.layout.Callback("clubOwner:club:reminders:reminder"), h.clubReminders)
 }
 
 func parseEventCallback(callbackData string) (string, int, error) {
@@ -0,0 +1,94 @@
+package clubowner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// eventQRPresenterTTL is how long the event QR code is regenerated after it has been opened
+const eventQRPresenterTTL = 4 * time.Hour
+
+// eventQRPresenter regenerates the event QR code in the message until it is stopped
+type eventQRPresenter struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (p *eventQRPresenter) stop() {
+	p.cancel()
+	<-p.done
+}
+
+// startEventQRPresenter regenerates the event QR code in the message every rotation period,
+// the previous presenter in the same message is stopped
+func (h Handler) startEventQRPresenter(b *tele.Bot, msg *tele.Message, eventID, caption, stoppedCaption string, markup *tele.ReplyMarkup) {
+	key := eventQRPresenterKey(msg)
+	ctx, cancel := context.WithTimeout(context.Background(), eventQRPresenterTTL)
+	presenter := &eventQRPresenter{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	if previous, loaded := h.eventQRPresenters.Swap(key, presenter); loaded {
+		previous.(*eventQRPresenter).stop()
+	}
+
+	go func() {
+		defer close(presenter.done)
+		defer cancel()
+		defer h.eventQRPresenters.CompareAndDelete(key, presenter)
+
+		ticker := time.NewTicker(h.qrService.EventQRPeriod())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				// The presenter is stopped by the owner when the context is canceled, the message is already replaced then
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					_, _ = b.EditCaption(msg, stoppedCaption, markup)
+				}
+				return
+			case <-ticker.C:
+				qrData, err := h.qrService.GetEventQR(ctx, eventID)
+				if err != nil {
+					h.logger.Errorf("error while regenerate event QR (event_id=%s): %v", eventID, err)
+					continue
+				}
+				if ctx.Err() != nil {
+					continue
+				}
+
+				_, err = b.Edit(msg, &tele.Photo{
+					File:    tele.FromReader(bytes.NewReader(qrData)),
+					Caption: caption,
+				}, markup)
+				if err != nil && strings.Contains(err.Error(), "message to edit not found") {
+					h.logger.Infof("event QR message has been deleted (event_id=%s)", eventID)
+					return
+				}
+				if err != nil {
+					h.logger.Errorf("error while edit event QR (event_id=%s): %v", eventID, err)
+				}
+			}
+		}
+	}()
+}
+
+// stopEventQR stops regenerating the event QR code and returns to the event
+func (h Handler) stopEventQR(c tele.Context) error {
+	h.logger.Infof("(user: %d) stop event QR", c.Sender().ID)
+
+	if presenter, ok := h.eventQRPresenters.LoadAndDelete(eventQRPresenterKey(c.Message())); ok {
+		presenter.(*eventQRPresenter).stop()
+	}
+	return h.event(c)
+}
+
+func eventQRPresenterKey(msg *tele.Message) string {
+	return fmt.Sprintf("%d:%d", msg.Chat.ID, msg.ID)
+}
@@ -0,0 +1,105 @@
+package clubowner
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+)
+
+// editEventMaxGuests changes how many guests without Telegram each participant can bring,
+// the guests already added stay registered when the limit is lowered
+func (h Handler) editEventMaxGuests(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) edit event max guests (event_id=%s)", c.Sender().ID, eventID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	inputCollector := collector.New()
+	_ = c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "input_edit_max_guests", event.MaxGuests)),
+		backMarkup,
+	)
+	inputCollector.Collect(c.Message())
+
+	var (
+		maxGuests int
+		done      bool
+	)
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input event max guests: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_edit_max_guests", event.MaxGuests))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			h.logger.Errorf("(user: %d) error while input event max guests: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_edit_max_guests", event.MaxGuests))),
+				backMarkup,
+			)
+		case !validator.EventMaxGuests(response.Message.Text, nil):
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "invalid_edit_max_guests")),
+				backMarkup,
+			)
+		case validator.EventMaxGuests(response.Message.Text, nil):
+			maxGuests, _ = strconv.Atoi(response.Message.Text)
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			done = true
+		}
+		if done {
+			break
+		}
+	}
+
+	event.MaxGuests = maxGuests
+	_, err = h.eventService.Update(context.Background(), event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while update event max guests: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	return c.Send(
+		banner.ClubOwner.Caption(h.layout.Text(c, "event_max_guests_changed")),
+		backMarkup,
+	)
+}
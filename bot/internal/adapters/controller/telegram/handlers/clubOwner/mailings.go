@@ -0,0 +1,484 @@
+package clubowner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+)
+
+const mailingTimeLayout = "02.01.2006 15:04"
+
+// audienceText returns the localised name of the mailing audience
+func (h Handler) audienceText(c tele.Context, audience entity.MailingAudience) string {
+	return h.layout.Text(c, "mailing_audience_"+audience.String())
+}
+
+// scheduleClubMailing asks the owner for the send time and the audience of the mailing and saves it,
+// the mailing is sent by the scheduled mailing scheduler
+func (h Handler) scheduleClubMailing(c tele.Context, club *entity.Club, mailing *entity.ScheduledMailing) error {
+	h.logger.Infof("(user: %d) schedule club mailing (club_id=%s)", c.Sender().ID, club.ID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:club:back", struct {
+		ID string
+	}{
+		ID: club.ID,
+	})
+
+	inputCollector := collector.New()
+	_ = inputCollector.Send(c,
+		banner.ClubOwner.Caption(h.layout.Text(c, "input_mailing_send_at")),
+		backMarkup,
+	)
+
+	var done bool
+	for !done {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input mailing send time: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_mailing_send_at"))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_mailing_send_at"))),
+				backMarkup,
+			)
+		case !validator.MailingSendAt(response.Message.Text, nil):
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "invalid_mailing_send_at")),
+				backMarkup,
+			)
+		case validator.MailingSendAt(response.Message.Text, nil):
+			mailing.SendAt, _ = time.ParseInLocation(mailingTimeLayout, response.Message.Text, location.Location())
+			done = true
+		}
+	}
+
+	audienceMarkup := h.layout.Markup(c, "clubOwner:club:back", struct {
+		ID string
+	}{
+		ID: club.ID,
+	})
+	var rows [][]tele.InlineButton
+	for _, audience := range entity.MailingAudiences {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "clubOwner:mailing:audience", struct {
+			Audience string
+			Text     string
+		}{
+			Audience: audience.String(),
+			Text:     h.audienceText(c, audience),
+		}).Inline()})
+	}
+	audienceMarkup.InlineKeyboard = append(rows, audienceMarkup.InlineKeyboard...)
+
+	_ = inputCollector.Send(c,
+		banner.ClubOwner.Caption(h.layout.Text(c, "choose_mailing_audience")),
+		audienceMarkup,
+	)
+	response, err := h.input.Get(
+		context.Background(),
+		c.Sender().ID,
+		0,
+		h.layout.Callback("clubOwner:mailing:audience"),
+	)
+	switch {
+	case response.Canceled:
+		_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+		return nil
+	case err != nil:
+		h.logger.Errorf("(user: %d) error while choose mailing audience: %v", c.Sender().ID, err)
+		_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "input_error", err.Error())),
+			backMarkup,
+		)
+	case response.Callback == nil:
+		h.logger.Errorf("(user: %d) error while choose mailing audience: callback is nil", c.Sender().ID)
+		_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "input_error", "callback is nil")),
+			backMarkup,
+		)
+	}
+	_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+
+	// the button isn't handled by the bot, so the callback data is "\f<unique>|<audience>"
+	data := strings.Split(response.Callback.Data, "|")
+	mailing.Audience = entity.MailingAudience(data[len(data)-1])
+	if !slices.Contains(entity.MailingAudiences, mailing.Audience) {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	mailing, err = h.scheduledMailingService.Create(context.Background(), mailing)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while create scheduled mailing: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	h.logger.Infof("(user: %d) club mailing scheduled (club_id=%s, mailing_id=%s, send_at=%s)", c.Sender().ID, club.ID, mailing.ID, mailing.SendAt)
+
+	return c.Send(
+		banner.ClubOwner.Caption(h.layout.Text(c, "mailing_scheduled", struct {
+			SendAt   string
+			Audience string
+		}{
+			SendAt:   mailing.SendAt.In(location.Location()).Format(mailingTimeLayout),
+			Audience: h.audienceText(c, mailing.Audience),
+		})),
+		backMarkup,
+	)
+}
+
+func (h Handler) clubScheduledMailings(c tele.Context) error {
+	clubID := c.Callback().Data
+	if clubID == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) get club scheduled mailings (club_id=%s)", c.Sender().ID, clubID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:club:back", struct {
+		ID string
+	}{
+		ID: clubID,
+	})
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	mailings, err := h.scheduledMailingService.GetByClubID(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get scheduled mailings: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	var rows [][]tele.InlineButton
+	for _, mailing := range mailings {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "clubOwner:mailings:mailing", struct {
+			ID       string
+			SendAt   string
+			Audience string
+		}{
+			ID:       mailing.ID,
+			SendAt:   mailing.SendAt.In(location.Location()).Format(mailingTimeLayout),
+			Audience: h.audienceText(c, mailing.Audience),
+		}).Inline()})
+	}
+	backMarkup.InlineKeyboard = append(rows, backMarkup.InlineKeyboard...)
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "scheduled_mailings_text", struct {
+			Name  string
+			Count int
+		}{
+			Name:  club.Name,
+			Count: len(mailings),
+		})),
+		backMarkup,
+	)
+}
+
+// getScheduledMailing returns the mailing from the callback data,
+// if the mailing can't be found the error message is sent to the user and nil is returned
+func (h Handler) getScheduledMailing(c tele.Context) (*entity.ScheduledMailing, error) {
+	mailingID := c.Callback().Data
+	if mailingID == "" {
+		return nil, errorz.ErrInvalidCallbackData
+	}
+
+	mailing, err := h.scheduledMailingService.Get(context.Background(), mailingID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "scheduled_mailing_not_found")),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get scheduled mailing: %v", c.Sender().ID, err)
+		return nil, c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+	return mailing, nil
+}
+
+func (h Handler) scheduledMailing(c tele.Context) error {
+	mailing, err := h.getScheduledMailing(c)
+	if mailing == nil {
+		return err
+	}
+	h.logger.Infof("(user: %d) get scheduled mailing (mailing_id=%s)", c.Sender().ID, mailing.ID)
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "scheduled_mailing_text", struct {
+			ClubName string
+			SendAt   string
+			Audience string
+			Text     string
+			HasMedia bool
+		}{
+			ClubName: mailing.Club.Name,
+			SendAt:   mailing.SendAt.In(location.Location()).Format(mailingTimeLayout),
+			Audience: h.audienceText(c, mailing.Audience),
+			Text:     mailing.Text,
+			HasMedia: mailing.FileID != "",
+		})),
+		h.layout.Markup(c, "clubOwner:mailing", struct {
+			ID     string
+			ClubID string
+		}{
+			ID:     mailing.ID,
+			ClubID: mailing.ClubID,
+		}),
+	)
+}
+
+func (h Handler) switchScheduledMailingAudience(c tele.Context) error {
+	mailing, err := h.getScheduledMailing(c)
+	if mailing == nil {
+		return err
+	}
+
+	index := slices.Index(entity.MailingAudiences, mailing.Audience)
+	mailing.Audience = entity.MailingAudiences[(index+1)%len(entity.MailingAudiences)]
+	_, err = h.scheduledMailingService.Update(context.Background(), mailing)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "scheduled_mailing_not_found")),
+			h.layout.Markup(c, "clubOwner:mailings:back", struct {
+				ClubID string
+			}{
+				ClubID: mailing.ClubID,
+			}),
+		)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while update scheduled mailing audience: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:mailings:back", struct {
+				ClubID string
+			}{
+				ClubID: mailing.ClubID,
+			}),
+		)
+	}
+	h.logger.Infof("(user: %d) scheduled mailing audience changed (mailing_id=%s, audience=%s)", c.Sender().ID, mailing.ID, mailing.Audience)
+
+	return h.scheduledMailing(c)
+}
+
+func (h Handler) editScheduledMailingText(c tele.Context) error {
+	mailing, err := h.getScheduledMailing(c)
+	if mailing == nil {
+		return err
+	}
+	h.logger.Infof("(user: %d) edit scheduled mailing text (mailing_id=%s)", c.Sender().ID, mailing.ID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:mailing:back", struct {
+		ID string
+	}{
+		ID: mailing.ID,
+	})
+
+	inputCollector := collector.New()
+	_ = c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "input_scheduled_mailing")),
+		backMarkup,
+	)
+	inputCollector.Collect(c.Message())
+
+	var done bool
+	for !done {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input scheduled mailing text: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_scheduled_mailing"))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_scheduled_mailing"))),
+				backMarkup,
+			)
+		case !validator.MailingText(utils.GetMessageText(response.Message), nil):
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "invalid_mailing_text")),
+				backMarkup,
+			)
+		case validator.MailingText(utils.GetMessageText(response.Message), nil):
+			mailing.Text = utils.GetMessageText(response.Message)
+			mailing.MediaType, mailing.FileID = utils.GetMessageMedia(response.Message)
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			done = true
+		}
+	}
+
+	return h.updateScheduledMailing(c, mailing)
+}
+
+func (h Handler) editScheduledMailingSendAt(c tele.Context) error {
+	mailing, err := h.getScheduledMailing(c)
+	if mailing == nil {
+		return err
+	}
+	h.logger.Infof("(user: %d) edit scheduled mailing send time (mailing_id=%s)", c.Sender().ID, mailing.ID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:mailing:back", struct {
+		ID string
+	}{
+		ID: mailing.ID,
+	})
+
+	inputCollector := collector.New()
+	_ = c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "input_mailing_send_at")),
+		backMarkup,
+	)
+	inputCollector.Collect(c.Message())
+
+	var done bool
+	for !done {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input scheduled mailing send time: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_mailing_send_at"))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_mailing_send_at"))),
+				backMarkup,
+			)
+		case !validator.MailingSendAt(response.Message.Text, nil):
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "invalid_mailing_send_at")),
+				backMarkup,
+			)
+		case validator.MailingSendAt(response.Message.Text, nil):
+			mailing.SendAt, _ = time.ParseInLocation(mailingTimeLayout, response.Message.Text, location.Location())
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			done = true
+		}
+	}
+
+	return h.updateScheduledMailing(c, mailing)
+}
+
+// updateScheduledMailing saves the edited mailing and reports the result to the owner,
+// the mailing may have already been sent while the owner was editing it
+func (h Handler) updateScheduledMailing(c tele.Context, mailing *entity.ScheduledMailing) error {
+	_, err := h.scheduledMailingService.Update(context.Background(), mailing)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "scheduled_mailing_not_found")),
+			h.layout.Markup(c, "clubOwner:mailings:back", struct {
+				ClubID string
+			}{
+				ClubID: mailing.ClubID,
+			}),
+		)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while update scheduled mailing: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:mailing:back", struct {
+				ID string
+			}{
+				ID: mailing.ID,
+			}),
+		)
+	}
+	h.logger.Infof("(user: %d) scheduled mailing changed (mailing_id=%s)", c.Sender().ID, mailing.ID)
+
+	return c.Send(
+		banner.ClubOwner.Caption(h.layout.Text(c, "scheduled_mailing_changed")),
+		h.layout.Markup(c, "clubOwner:mailing:back", struct {
+			ID string
+		}{
+			ID: mailing.ID,
+		}),
+	)
+}
+
+func (h Handler) cancelScheduledMailing(c tele.Context) error {
+	mailing, err := h.getScheduledMailing(c)
+	if mailing == nil {
+		return err
+	}
+	h.logger.Infof("(user: %d) cancel scheduled mailing (mailing_id=%s)", c.Sender().ID, mailing.ID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:mailings:back", struct {
+		ClubID string
+	}{
+		ClubID: mailing.ClubID,
+	})
+
+	err = h.scheduledMailingService.Delete(context.Background(), mailing.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "scheduled_mailing_not_found")),
+			backMarkup,
+		)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while delete scheduled mailing: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "scheduled_mailing_cancelled")),
+		backMarkup,
+	)
+}
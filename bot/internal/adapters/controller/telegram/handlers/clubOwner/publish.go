@@ -0,0 +1,258 @@
+package clubowner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+)
+
+// formatPublishAt formats the publish time of the draft, empty if the draft is published manually
+func formatPublishAt(publishAt *time.Time) string {
+	if publishAt == nil {
+		return ""
+	}
+	return formatEventTime(*publishAt)
+}
+
+// eventPublication shows the publication settings of the draft
+func (h Handler) eventPublication(c tele.Context) error {
+	event, page, err := h.draftForPublication(c, "open publication")
+	if event == nil {
+		return err
+	}
+
+	what, markup := h.eventPublicationView(c, event, page)
+	return c.Edit(what, markup)
+}
+
+// publishEvent publishes the draft right away
+func (h Handler) publishEvent(c tele.Context) error {
+	event, page, err := h.draftForPublication(c, "publish")
+	if event == nil {
+		return err
+	}
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   event.ID,
+		Page: page,
+	})
+
+	err = h.publishService.Publish(context.Background(), event)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_already_published"),
+			ShowAlert: true,
+		})
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while publish event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "event_published", event)),
+		backMarkup,
+	)
+}
+
+// editEventPublishAt asks the time when the draft is published automatically
+func (h Handler) editEventPublishAt(c tele.Context) error {
+	event, page, err := h.draftForPublication(c, "publish time")
+	if event == nil {
+		return err
+	}
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:publication:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   event.ID,
+		Page: page,
+	})
+
+	registrationEnd := formatEventTime(event.RegistrationEnd)
+	params := map[string]interface{}{
+		"registrationEnd": registrationEnd,
+	}
+	publishAt, ok := h.inputEventValue(c, backMarkup,
+		h.layout.Text(c, "input_event_publish_at", struct {
+			RegistrationEnd string
+		}{
+			RegistrationEnd: registrationEnd,
+		}),
+		h.layout.Text(c, "invalid_event_publish_at", struct {
+			RegistrationEnd string
+		}{
+			RegistrationEnd: registrationEnd,
+		}),
+		func(text string) bool {
+			return validator.EventPublishAt(text, params)
+		},
+	)
+	if !ok {
+		return nil
+	}
+
+	publishAtTime, _ := time.ParseInLocation(eventTimeLayout, publishAt, location.Location())
+	publishAtTime = publishAtTime.UTC()
+	event.PublishAt = &publishAtTime
+
+	err = h.publishService.UpdatePublication(context.Background(), event)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The scheduler has published the draft while the time was typed
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_already_published")),
+			h.layout.Markup(c, "clubOwner:event:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ID,
+				Page: page,
+			}),
+		)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while update event publish time: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	h.logger.Infof("(user: %d) event publish time set (event_id=%s, publish_at=%s)", c.Sender().ID, event.ID, publishAt)
+
+	what, markup := h.eventPublicationView(c, event, page)
+	return c.Send(what, markup)
+}
+
+// resetEventPublishAt turns off the automatic publication of the draft
+func (h Handler) resetEventPublishAt(c tele.Context) error {
+	event, page, err := h.draftForPublication(c, "reset publish time")
+	if event == nil {
+		return err
+	}
+
+	event.PublishAt = nil
+	return h.updatePublication(c, event, page)
+}
+
+// toggleEventAnnounce turns on or off the announcement that is sent to the club users on the publication
+func (h Handler) toggleEventAnnounce(c tele.Context) error {
+	event, page, err := h.draftForPublication(c, "toggle announce")
+	if event == nil {
+		return err
+	}
+
+	event.Announce = !event.Announce
+	return h.updatePublication(c, event, page)
+}
+
+// updatePublication saves the publication settings of the draft and shows them again
+func (h Handler) updatePublication(c tele.Context, event *entity.Event, page string) error {
+	err := h.publishService.UpdatePublication(context.Background(), event)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_already_published"),
+			ShowAlert: true,
+		})
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while update event publication: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   event.ID,
+				Page: page,
+			}),
+		)
+	}
+
+	what, markup := h.eventPublicationView(c, event, page)
+	return c.Edit(what, markup)
+}
+
+// eventPublicationView returns the publication settings of the draft
+func (h Handler) eventPublicationView(c tele.Context, event *entity.Event, page string) (interface{}, *tele.ReplyMarkup) {
+	data := struct {
+		ID        string
+		Page      string
+		Name      string
+		PublishAt string
+		Announce  bool
+	}{
+		ID:        event.ID,
+		Page:      page,
+		Name:      event.Name,
+		PublishAt: formatPublishAt(event.PublishAt),
+		Announce:  event.Announce,
+	}
+
+	markup := c.Bot().NewMarkup()
+	rows := []tele.Row{
+		markup.Row(*h.layout.Button(c, "clubOwner:event:publication:now", data)),
+		markup.Row(*h.layout.Button(c, "clubOwner:event:publication:at", data)),
+	}
+	if event.PublishAt != nil {
+		rows = append(rows, markup.Row(*h.layout.Button(c, "clubOwner:event:publication:reset", data)))
+	}
+	rows = append(rows,
+		markup.Row(*h.layout.Button(c, "clubOwner:event:publication:announce", data)),
+		markup.Row(*h.layout.Button(c, "clubOwner:event:back", data)),
+	)
+	markup.Inline(rows...)
+
+	return banner.ClubOwner.Caption(h.layout.Text(c, "event_publication_text", data)), markup
+}
+
+// draftForPublication returns the event if it is still a draft,
+// the event is nil if it has already been published and the error is the response to the user then
+func (h Handler) draftForPublication(c tele.Context, action string) (*entity.Event, string, error) {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return nil, "", errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) event %s (event_id=%s)", c.Sender().ID, action, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return nil, page, c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	if !event.IsDraft {
+		return nil, page, c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_already_published"),
+			ShowAlert: true,
+		})
+	}
+	return event, page, nil
+}
@@ -0,0 +1,454 @@
+package clubowner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+)
+
+const maxEventQuestions = 10
+
+// eventQuestionnaire shows the registration questionnaire of the event
+func (h Handler) eventQuestionnaire(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) open event questionnaire (event_id=%s)", c.Sender().ID, eventID)
+
+	what, markup := h.eventQuestionnaireView(c, eventID, page)
+	return c.Edit(what, markup)
+}
+
+// eventQuestionnaireView returns the list of the questions of the event with the buttons to delete them
+func (h Handler) eventQuestionnaireView(c tele.Context, eventID, page string) (interface{}, *tele.ReplyMarkup) {
+	backMarkup := h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	type question struct {
+		Number   int
+		Text     string
+		Type     string
+		Options  string
+		Required bool
+	}
+	questions := make([]question, 0, len(event.Questions))
+	markup := h.layout.Markup(c, "clubOwner:event:questionnaire", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+	var rows [][]tele.InlineButton
+	for i, q := range event.Questions {
+		questions = append(questions, question{
+			Number:   i + 1,
+			Text:     q.Text,
+			Type:     h.layout.Text(c, "question_type_"+string(q.Type)),
+			Options:  strings.Join(q.Options, ", "),
+			Required: q.Required,
+		})
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "clubOwner:event:questionnaire:delete", struct {
+			ID     string
+			Index  int
+			Number int
+			Text   string
+			Page   string
+		}{
+			ID:     eventID,
+			Index:  i,
+			Number: i + 1,
+			Text:   q.Text,
+			Page:   page,
+		}).Inline()})
+	}
+	markup.InlineKeyboard = append(rows, markup.InlineKeyboard...)
+
+	text := h.layout.Text(c, "event_questionnaire", struct {
+		Name         string
+		Questions    []question
+		MaxQuestions int
+	}{
+		Name:         event.Name,
+		Questions:    questions,
+		MaxQuestions: maxEventQuestions,
+	})
+	return banner.ClubOwner.Caption(text), markup
+}
+
+// deleteEventQuestion removes the question from the questionnaire, the answers already given to it are kept for the export
+func (h Handler) deleteEventQuestion(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 3 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	index, err := strconv.Atoi(data[1])
+	if err != nil {
+		return errorz.ErrInvalidCallbackData
+	}
+	page := data[2]
+	h.logger.Infof("(user: %d) delete event question (event_id=%s, index=%d)", c.Sender().ID, eventID, index)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:questionnaire:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	// The questionnaire could have been changed by another owner since the list was shown
+	if index >= 0 && index < len(event.Questions) {
+		event.Questions = append(event.Questions[:index], event.Questions[index+1:]...)
+		_, err = h.eventService.Update(context.Background(), event)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while update event questionnaire: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				backMarkup,
+			)
+		}
+	}
+
+	what, markup := h.eventQuestionnaireView(c, eventID, page)
+	return c.Edit(what, markup)
+}
+
+// addEventQuestion asks for the text, the answer type, the options and the required flag of the new question
+func (h Handler) addEventQuestion(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) add event question (event_id=%s)", c.Sender().ID, eventID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:questionnaire:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	if len(event.Questions) >= maxEventQuestions {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_questions_limit", maxEventQuestions),
+			ShowAlert: true,
+		})
+	}
+
+	var (
+		question entity.Question
+		done     bool
+	)
+	inputCollector := collector.New()
+	_ = c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "input_event_question")),
+		backMarkup,
+	)
+	inputCollector.Collect(c.Message())
+
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input event question: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_event_question"))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			h.logger.Errorf("(user: %d) error while input event question: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_event_question"))),
+				backMarkup,
+			)
+		case !validator.EventQuestion(response.Message.Text, nil):
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "invalid_event_question")),
+				backMarkup,
+			)
+		case validator.EventQuestion(response.Message.Text, nil):
+			question.Text = strings.TrimSpace(response.Message.Text)
+			done = true
+		}
+		if done {
+			break
+		}
+	}
+
+	typeBtn := h.layout.Button(c, "clubOwner:event:question:type")
+	typeMarkup := &tele.ReplyMarkup{}
+	for _, questionType := range entity.QuestionTypes {
+		typeMarkup.InlineKeyboard = append(typeMarkup.InlineKeyboard, []tele.InlineButton{*h.layout.Button(c, "clubOwner:event:question:type", struct {
+			Type string
+			Text string
+		}{
+			Type: string(questionType),
+			Text: h.layout.Text(c, "question_type_"+string(questionType)),
+		}).Inline()})
+	}
+	typeMarkup.InlineKeyboard = append(typeMarkup.InlineKeyboard, backMarkup.InlineKeyboard...)
+
+	_ = inputCollector.Send(c,
+		banner.ClubOwner.Caption(h.layout.Text(c, "input_event_question_type")),
+		typeMarkup,
+	)
+	done = false
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0, typeBtn)
+		if response.Message != nil && response.Callback == nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input event question type: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_event_question_type"))),
+				typeMarkup,
+			)
+		case response.Callback != nil:
+			questionType := entity.QuestionType(response.Callback.Data)
+			for _, t := range entity.QuestionTypes {
+				if t == questionType {
+					question.Type = questionType
+					done = true
+				}
+			}
+		default:
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_event_question_type")),
+				typeMarkup,
+			)
+		}
+		if done {
+			break
+		}
+	}
+
+	if question.Type.IsChoice() {
+		_ = inputCollector.Send(c,
+			banner.ClubOwner.Caption(h.layout.Text(c, "input_event_question_options")),
+			backMarkup,
+		)
+		done = false
+		for {
+			response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+			if response.Message != nil {
+				inputCollector.Collect(response.Message)
+			}
+			switch {
+			case response.Canceled:
+				_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+				return nil
+			case errGet != nil:
+				h.logger.Errorf("(user: %d) error while input event question options: %v", c.Sender().ID, errGet)
+				_ = inputCollector.Send(c,
+					banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_event_question_options"))),
+					backMarkup,
+				)
+			case response.Message == nil:
+				h.logger.Errorf("(user: %d) error while input event question options: %v", c.Sender().ID, errGet)
+				_ = inputCollector.Send(c,
+					banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_event_question_options"))),
+					backMarkup,
+				)
+			case !validator.EventQuestionOptions(response.Message.Text, nil):
+				_ = inputCollector.Send(c,
+					banner.ClubOwner.Caption(h.layout.Text(c, "invalid_event_question_options")),
+					backMarkup,
+				)
+			case validator.EventQuestionOptions(response.Message.Text, nil):
+				for _, option := range strings.Split(response.Message.Text, "\n") {
+					if option = strings.TrimSpace(option); option != "" {
+						question.Options = append(question.Options, option)
+					}
+				}
+				done = true
+			}
+			if done {
+				break
+			}
+		}
+	}
+
+	requiredBtn := h.layout.Button(c, "clubOwner:event:question:required")
+	requiredMarkup := &tele.ReplyMarkup{}
+	requiredMarkup.InlineKeyboard = append([][]tele.InlineButton{{
+		*h.layout.Button(c, "clubOwner:event:question:required", struct {
+			Required bool
+		}{
+			Required: true,
+		}).Inline(),
+		*h.layout.Button(c, "clubOwner:event:question:required", struct {
+			Required bool
+		}{
+			Required: false,
+		}).Inline(),
+	}}, backMarkup.InlineKeyboard...)
+
+	_ = inputCollector.Send(c,
+		banner.ClubOwner.Caption(h.layout.Text(c, "input_event_question_required")),
+		requiredMarkup,
+	)
+	done = false
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0, requiredBtn)
+		if response.Message != nil && response.Callback == nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input event question required: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_event_question_required"))),
+				requiredMarkup,
+			)
+		case response.Callback != nil:
+			required, errParse := strconv.ParseBool(response.Callback.Data)
+			if errParse == nil {
+				question.Required = required
+				done = true
+			}
+		default:
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_event_question_required")),
+				requiredMarkup,
+			)
+		}
+		if done {
+			break
+		}
+	}
+	_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+
+	// The event is loaded again so the questions added by other owners meanwhile are not lost
+	event, err = h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	if len(event.Questions) >= maxEventQuestions {
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_questions_limit", maxEventQuestions)),
+			backMarkup,
+		)
+	}
+
+	event.Questions = append(event.Questions, question)
+	_, err = h.eventService.Update(context.Background(), event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while update event questionnaire: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	what, markup := h.eventQuestionnaireView(c, eventID, page)
+	return c.Send(what, markup)
+}
+
+// exportEventAnswers sends the participants of the event with their answers to the questionnaire as XLSX
+func (h Handler) exportEventAnswers(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	h.logger.Infof("(user: %d) export event answers (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	locale, _ := h.layout.Locale(c)
+	buf, err := h.questionnaireService.AnswersToXLSX(context.Background(), event, locale)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while export event answers to xlsx: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	fileName := fmt.Sprintf("answers_%s.xlsx", time.Now().In(location.Location()).Format("02.01.2006"))
+	doc := &tele.Document{
+		File:     tele.FromReader(buf),
+		Caption:  h.layout.Text(c, "event_answers_exported_text", event.Name),
+		FileName: fileName,
+	}
+
+	return c.Send(
+		doc,
+		h.layout.Markup(c, "core:hide"),
+	)
+}
@@ -0,0 +1,245 @@
+package clubowner
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/lib/pq"
+	tele "gopkg.in/telebot.v3"
+)
+
+// clubRemindersData is passed instead of the reminder to reset the event reminders to the club ones
+const clubRemindersData = "club"
+
+// toggleReminder enables the reminder if it is disabled and disables it otherwise,
+// the result is ordered as entity.ReminderTypes
+func toggleReminder(reminders pq.StringArray, reminder entity.NotificationType) pq.StringArray {
+	enabled := slices.Contains(reminders, reminder.String())
+
+	toggled := pq.StringArray{}
+	for _, r := range entity.ReminderTypes {
+		isCurrent := r == reminder
+		if (isCurrent && !enabled) || (!isCurrent && slices.Contains(reminders, r.String())) {
+			toggled = append(toggled, r.String())
+		}
+	}
+	return toggled
+}
+
+// remindersText returns the comma separated list of the enabled reminders
+func (h Handler) remindersText(c tele.Context, reminders pq.StringArray) string {
+	var texts []string
+	for _, r := range entity.ReminderTypes {
+		if slices.Contains(reminders, r.String()) {
+			texts = append(texts, h.layout.Text(c, "reminder_"+r.String()))
+		}
+	}
+	return strings.Join(texts, ", ")
+}
+
+func (h Handler) clubReminders(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) == 0 || len(data) > 2 || data[0] == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	clubID := data[0]
+	h.logger.Infof("(user: %d) edit club reminders (club_id=%s)", c.Sender().ID, clubID)
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:club:settings:back", struct {
+				ID string
+			}{
+				ID: clubID,
+			}),
+		)
+	}
+
+	if len(data) == 2 {
+		reminder := entity.NotificationType(data[1])
+		if !reminder.IsReminder() {
+			return errorz.ErrInvalidCallbackData
+		}
+
+		club.Reminders = toggleReminder(club.Reminders, reminder)
+		club, err = h.clubService.Update(context.Background(), club)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while update club reminders: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "clubOwner:club:settings:back", struct {
+					ID string
+				}{
+					ID: clubID,
+				}),
+			)
+		}
+		h.logger.Infof("(user: %d) club reminders changed (club_id=%s, reminders=%v)", c.Sender().ID, clubID, club.Reminders)
+	}
+
+	markup := h.layout.Markup(c, "clubOwner:club:settings:back", struct {
+		ID string
+	}{
+		ID: clubID,
+	})
+	var rows [][]tele.InlineButton
+	for _, r := range entity.ReminderTypes {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "clubOwner:club:reminders:reminder", struct {
+			ID       string
+			Reminder string
+			Text     string
+			Enabled  bool
+		}{
+			ID:       clubID,
+			Reminder: r.String(),
+			Text:     h.layout.Text(c, "reminder_"+r.String()),
+			Enabled:  slices.Contains(club.Reminders, r.String()),
+		}).Inline()})
+	}
+	markup.InlineKeyboard = append(rows, markup.InlineKeyboard...)
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "club_reminders_text", struct {
+			Name      string
+			Reminders string
+		}{
+			Name:      club.Name,
+			Reminders: h.remindersText(c, club.Reminders),
+		})),
+		markup,
+	)
+}
+
+func (h Handler) eventReminders(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 && len(data) != 3 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) edit event reminders (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	club, err := h.clubService.Get(context.Background(), event.ClubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	// the club is not assigned to event.Club so that it isn't saved with the event
+	eventReminders := func() pq.StringArray {
+		if event.Reminders != nil {
+			return event.Reminders
+		}
+		return club.Reminders
+	}
+
+	if len(data) == 3 {
+		switch reminder := entity.NotificationType(data[2]); {
+		case data[2] == clubRemindersData:
+			event.Reminders = nil
+		case reminder.IsReminder():
+			event.Reminders = toggleReminder(eventReminders(), reminder)
+		default:
+			return errorz.ErrInvalidCallbackData
+		}
+
+		event, err = h.eventService.Update(context.Background(), event)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while update event reminders: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+					ID   string
+					Page string
+				}{
+					ID:   eventID,
+					Page: page,
+				}),
+			)
+		}
+		h.logger.Infof("(user: %d) event reminders changed (event_id=%s, reminders=%v)", c.Sender().ID, eventID, event.Reminders)
+	}
+
+	reminders := eventReminders()
+	markup := h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+	var rows [][]tele.InlineButton
+	for _, r := range entity.ReminderTypes {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "clubOwner:event:reminders:reminder", struct {
+			ID       string
+			Page     string
+			Reminder string
+			Text     string
+			Enabled  bool
+		}{
+			ID:       eventID,
+			Page:     page,
+			Reminder: r.String(),
+			Text:     h.layout.Text(c, "reminder_"+r.String()),
+			Enabled:  slices.Contains(reminders, r.String()),
+		}).Inline()})
+	}
+	if event.Reminders != nil {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "clubOwner:event:reminders:reset", struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: page,
+		}).Inline()})
+	}
+	markup.InlineKeyboard = append(rows, markup.InlineKeyboard...)
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "event_reminders_text", struct {
+			Name          string
+			Reminders     string
+			ClubReminders string
+			IsOverridden  bool
+		}{
+			Name:          event.Name,
+			Reminders:     h.remindersText(c, reminders),
+			ClubReminders: h.remindersText(c, club.Reminders),
+			IsOverridden:  event.Reminders != nil,
+		})),
+		markup,
+	)
+}
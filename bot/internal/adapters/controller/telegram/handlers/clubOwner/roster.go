@@ -0,0 +1,418 @@
+package clubowner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+)
+
+const (
+	rosterUsersOnPage = 8
+	// rosterStateTTL is how long the buttons of the roster work, the event and the search query of the roster
+	// do not fit into the callback data, so they are kept in the callbacks storage
+	rosterStateTTL = 24 * time.Hour
+)
+
+// rosterState is the roster opened by the club owner
+type rosterState struct {
+	EventID   string
+	EventPage string
+	Query     string
+}
+
+func (h Handler) setRosterState(state rosterState) (string, error) {
+	return h.callbacksStorage.Set(fmt.Sprintf("%s %s %s", state.EventID, state.EventPage, state.Query), rosterStateTTL)
+}
+
+func (h Handler) getRosterState(key string) (rosterState, error) {
+	data, err := h.callbacksStorage.Get(key)
+	if err != nil {
+		return rosterState{}, err
+	}
+	parts := strings.SplitN(data, " ", 3)
+	if len(parts) != 3 {
+		return rosterState{}, errorz.ErrInvalidCallbackData
+	}
+	return rosterState{
+		EventID:   parts[0],
+		EventPage: parts[1],
+		Query:     parts[2],
+	}, nil
+}
+
+// eventRoster opens the participants of the event, where the club owners can mark the visits by hand
+func (h Handler) eventRoster(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) open event roster (event_id=%s)", c.Sender().ID, eventID)
+
+	key, err := h.setRosterState(rosterState{EventID: eventID, EventPage: page})
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while setting callback: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	what, markup := h.eventRosterView(c, key, 0)
+	return c.Edit(what, markup)
+}
+
+// rosterPage opens the page of the roster, also the first page after the search is closed
+func (h Handler) rosterPage(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) == 0 || len(data) > 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	var page int
+	if len(data) == 2 {
+		var err error
+		page, err = strconv.Atoi(data[1])
+		if err != nil {
+			return errorz.ErrInvalidCallbackData
+		}
+	}
+
+	what, markup := h.eventRosterView(c, data[0], page)
+	return c.Edit(what, markup)
+}
+
+// resetRosterSearch opens the roster without the search query
+func (h Handler) resetRosterSearch(c tele.Context) error {
+	state, err := h.getRosterState(c.Callback().Data)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while getting callback from redis: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "roster_expired")),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	state.Query = ""
+	key, err := h.setRosterState(state)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while setting callback: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	what, markup := h.eventRosterView(c, key, 0)
+	return c.Edit(what, markup)
+}
+
+// searchRoster asks the club owner the full name or the username of the participant and opens the found participants
+func (h Handler) searchRoster(c tele.Context) error {
+	state, err := h.getRosterState(c.Callback().Data)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while getting callback from redis: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "roster_expired")),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+	h.logger.Infof("(user: %d) search event roster (event_id=%s)", c.Sender().ID, state.EventID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:roster:back", struct {
+		Key string
+	}{
+		Key: c.Callback().Data,
+	})
+
+	inputCollector := collector.New()
+	_ = c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "input_roster_search")),
+		backMarkup,
+	)
+	inputCollector.Collect(c.Message())
+
+	var (
+		query string
+		done  bool
+	)
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input roster search: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_roster_search"))),
+				backMarkup,
+			)
+		case response.Message == nil || strings.TrimSpace(response.Message.Text) == "":
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "input_roster_search"))),
+				backMarkup,
+			)
+		default:
+			query = strings.Join(strings.Fields(response.Message.Text), " ")
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			done = true
+		}
+		if done {
+			break
+		}
+	}
+
+	state.Query = query
+	key, err := h.setRosterState(state)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while setting callback: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	what, markup := h.eventRosterView(c, key, 0)
+	return c.Send(what, markup)
+}
+
+// switchRosterVisit marks the participant as visited or removes the visit mark
+func (h Handler) switchRosterVisit(c tele.Context) error {
+	callbackData, err := h.callbacksStorage.Get(c.Callback().Data)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while getting callback from redis: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "roster_expired")),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+	data := strings.Split(callbackData, " ")
+	if len(data) != 3 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	key := data[0]
+	userID, err := strconv.ParseInt(data[1], 10, 64)
+	if err != nil {
+		return errorz.ErrInvalidCallbackData
+	}
+	page, err := strconv.Atoi(data[2])
+	if err != nil {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	state, err := h.getRosterState(key)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while getting callback from redis: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "roster_expired")),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	event, err := h.eventService.Get(context.Background(), state.EventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	visited, err := h.eventParticipantService.SwitchVisited(context.Background(), event, userID, c.Sender().ID)
+	switch {
+	case errors.Is(err, errorz.ErrCheckInNotOpened):
+		opens, _ := event.CheckInWindow()
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "roster_check_in_not_opened", opens.In(location.Location()).Format("02.01.2006 15:04")),
+			ShowAlert: true,
+		})
+	case err != nil:
+		h.logger.Errorf("(user: %d) error while switch participant visit: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+	h.logger.Infof("(user: %d) participant visit changed by hand (event_id=%s, user_id=%d, visited=%t)", c.Sender().ID, event.ID, userID, visited)
+
+	what, markup := h.eventRosterView(c, key, page)
+	return c.Edit(what, markup)
+}
+
+// eventRosterView returns the page of the participants of the roster with the buttons to switch their visit marks
+func (h Handler) eventRosterView(c tele.Context, key string, page int) (interface{}, *tele.ReplyMarkup) {
+	state, err := h.getRosterState(key)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while getting callback from redis: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "roster_expired")), h.layout.Markup(c, "mainMenu:back")
+	}
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   state.EventID,
+		Page: state.EventPage,
+	})
+
+	event, err := h.eventService.Get(context.Background(), state.EventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	participantsCount, err := h.eventParticipantService.CountRoster(context.Background(), state.EventID, "")
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while count event roster: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+	foundCount := participantsCount
+	if state.Query != "" {
+		foundCount, err = h.eventParticipantService.CountRoster(context.Background(), state.EventID, state.Query)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while count event roster: %v", c.Sender().ID, err)
+			return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+		}
+	}
+
+	visitedCount, err := h.eventParticipantService.CountVisitedByEventID(context.Background(), state.EventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while count visited users: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	changesCount, err := h.eventParticipantService.CountAttendanceChanges(context.Background(), state.EventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while count attendance changes: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	pagesCount := max(foundCount-1, 0) / rosterUsersOnPage
+	page = min(max(page, 0), pagesCount)
+
+	users, err := h.eventParticipantService.GetRoster(
+		context.Background(),
+		state.EventID,
+		state.Query,
+		rosterUsersOnPage,
+		page*rosterUsersOnPage,
+	)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event roster: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	var rows []tele.Row
+	markup := c.Bot().NewMarkup()
+	for _, user := range users {
+		callbackID, errSet := h.callbacksStorage.Set(fmt.Sprintf("%s %d %d", key, user.User.ID, page), rosterStateTTL)
+		if errSet != nil {
+			h.logger.Errorf("(user: %d) error while setting callback: %v", c.Sender().ID, errSet)
+			continue
+		}
+		rows = append(rows, markup.Row(*h.layout.Button(c, "clubOwner:roster:user", struct {
+			CallbackID string
+			FIO        string
+			Username   string
+			Visited    bool
+		}{
+			CallbackID: callbackID,
+			FIO:        user.User.FIO,
+			Username:   user.User.Username,
+			Visited:    user.UserVisit,
+		})))
+	}
+
+	if pagesCount > 0 {
+		prevPage, nextPage := page-1, page+1
+		if page == 0 {
+			prevPage = pagesCount
+		}
+		if page >= pagesCount {
+			nextPage = 0
+		}
+		rows = append(rows, markup.Row(
+			*h.layout.Button(c, "clubOwner:roster:prev_page", struct {
+				Key  string
+				Page int
+			}{
+				Key:  key,
+				Page: prevPage,
+			}),
+			*h.layout.Button(c, "core:page_counter", struct {
+				Page       int
+				PagesCount int
+			}{
+				Page:       page + 1,
+				PagesCount: pagesCount + 1,
+			}),
+			*h.layout.Button(c, "clubOwner:roster:next_page", struct {
+				Key  string
+				Page int
+			}{
+				Key:  key,
+				Page: nextPage,
+			}),
+		))
+	}
+
+	searchButton := "clubOwner:roster:search"
+	if state.Query != "" {
+		searchButton = "clubOwner:roster:reset_search"
+	}
+	rows = append(rows,
+		markup.Row(*h.layout.Button(c, searchButton, struct {
+			Key string
+		}{
+			Key: key,
+		})),
+		markup.Row(*h.layout.Button(c, "clubOwner:event:back", struct {
+			ID   string
+			Page string
+		}{
+			ID:   state.EventID,
+			Page: state.EventPage,
+		})),
+	)
+	markup.Inline(rows...)
+
+	text := h.layout.Text(c, "event_roster", struct {
+		Name              string
+		ParticipantsCount int
+		VisitedCount      int
+		ChangesCount      int
+		Query             string
+		FoundCount        int
+	}{
+		Name:              event.Name,
+		ParticipantsCount: participantsCount,
+		VisitedCount:      visitedCount,
+		ChangesCount:      changesCount,
+		Query:             html.EscapeString(state.Query),
+		FoundCount:        foundCount,
+	})
+	return banner.ClubOwner.Caption(text), markup
+}
@@ -0,0 +1,109 @@
+package clubowner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	tele "gopkg.in/telebot.v3"
+)
+
+// eventScanners shows the invite link for the door scanners of the event and who has used it
+func (h Handler) eventScanners(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) open event scanners (event_id=%s)", c.Sender().ID, eventID)
+
+	what, markup := h.eventScannersView(c, eventID, page, false)
+	return c.Edit(what, markup)
+}
+
+// resetEventScanners replaces the invite link of the event, so the old link stops working, and removes all its scanners
+func (h Handler) resetEventScanners(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) reset event scanners (event_id=%s)", c.Sender().ID, eventID)
+
+	what, markup := h.eventScannersView(c, eventID, page, true)
+	_ = c.Respond(&tele.CallbackResponse{
+		Text: h.layout.Text(c, "scanners_reset"),
+	})
+	return c.Edit(what, markup)
+}
+
+// eventScannersView returns the invite link and the scanners of the event, the invite link is created on the first open
+func (h Handler) eventScannersView(c tele.Context, eventID, page string, reset bool) (interface{}, *tele.ReplyMarkup) {
+	backMarkup := h.layout.Markup(c, "clubOwner:event:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	if reset {
+		event, err = h.scannerService.ResetInvite(context.Background(), event)
+	} else {
+		event, err = h.scannerService.CreateInvite(context.Background(), event)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while update event scanners invite: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	scanners, err := h.scannerService.GetUsersByEventID(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event scanners: %v", c.Sender().ID, err)
+		return banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())), backMarkup
+	}
+
+	type scanner struct {
+		FIO      string
+		Username string
+	}
+	scannersList := make([]scanner, 0, len(scanners))
+	for _, user := range scanners {
+		scannersList = append(scannersList, scanner{
+			FIO:      user.FIO,
+			Username: user.Username,
+		})
+	}
+
+	_, closes := event.CheckInWindow()
+	text := h.layout.Text(c, "event_scanners", struct {
+		Name     string
+		Link     string
+		Closes   string
+		Scanners []scanner
+	}{
+		Name:     event.Name,
+		Link:     event.ScannerInviteLink(c.Bot().Me.Username),
+		Closes:   closes.In(location.Location()).Format("02.01.2006 15:04"),
+		Scanners: scannersList,
+	})
+	return banner.ClubOwner.Caption(text), h.layout.Markup(c, "clubOwner:event:scanners", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+}
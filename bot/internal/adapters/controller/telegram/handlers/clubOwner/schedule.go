@@ -0,0 +1,653 @@
+package clubowner
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+)
+
+const eventTimeLayout = "02.01.2006 15:04"
+
+// eventChanges is the diff of the event sent to the participants, only the changed fields are set
+type eventChanges struct {
+	Name               string
+	OldStartTime       string
+	StartTime          string
+	OldEndTime         string
+	EndTime            string
+	OldRegistrationEnd string
+	RegistrationEnd    string
+	OldLocation        string
+	Location           string
+	OldRoles           []string
+	Roles              []string
+
+	// venueChanged is set when the venue is changed, the venue is not shown to the participants
+	venueChanged bool
+}
+
+// formatEventTime formats the time of the event for the users, empty for the time that is not set
+func formatEventTime(t time.Time) string {
+	if t.Year() == 1 {
+		return ""
+	}
+	return t.In(location.Location()).Format(eventTimeLayout)
+}
+
+// editEventStartTime moves the event to the new start time, the end time is moved together with it
+func (h Handler) editEventStartTime(c tele.Context) error {
+	event, backMarkup, err := h.eventForEdit(c, "start time")
+	if event == nil {
+		return err
+	}
+
+	startTime, ok := h.inputEventValue(c, backMarkup,
+		h.layout.Text(c, "input_event_start_time"),
+		h.layout.Text(c, "invalid_event_start_time"),
+		func(text string) bool {
+			return validator.EventStartTime(text, nil)
+		},
+	)
+	if !ok {
+		return nil
+	}
+
+	newStart, _ := time.ParseInLocation(eventTimeLayout, startTime, location.Location())
+	// The deadlines are not moved by themselves, as the participants could have planned around them
+	maxRegistrationEnd, _ := time.ParseInLocation(eventTimeLayout, utils.GetMaxRegisteredEndTime(startTime), location.Location())
+	if event.RegistrationEnd.After(maxRegistrationEnd) ||
+		(event.CancellationEnd.Year() != 1 && !event.CancellationEnd.Before(newStart)) {
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "invalid_edit_start_time", struct {
+				RegistrationEnd      string
+				CancellationEnd      string
+				MaxRegisteredEndTime string
+			}{
+				RegistrationEnd:      formatEventTime(event.RegistrationEnd),
+				CancellationEnd:      formatEventTime(event.CancellationEnd),
+				MaxRegisteredEndTime: formatEventTime(maxRegistrationEnd),
+			})),
+			backMarkup,
+		)
+	}
+
+	changes := eventChanges{
+		Name:         event.Name,
+		OldStartTime: formatEventTime(event.StartTime),
+		StartTime:    formatEventTime(newStart),
+	}
+	if event.EndTime.Year() != 1 {
+		newEnd := event.EndTime.Add(newStart.Sub(event.StartTime))
+		changes.OldEndTime = formatEventTime(event.EndTime)
+		changes.EndTime = formatEventTime(newEnd)
+		event.EndTime = newEnd.UTC()
+	}
+	event.StartTime = newStart.UTC()
+
+	return h.saveEventChanges(c, c.Send, event, changes, backMarkup, "event_start_time_changed")
+}
+
+// editEventEndTime changes when the event ends
+func (h Handler) editEventEndTime(c tele.Context) error {
+	event, backMarkup, err := h.eventForEdit(c, "end time")
+	if event == nil {
+		return err
+	}
+
+	params := map[string]interface{}{
+		"startTime": formatEventTime(event.StartTime),
+	}
+	endTime, ok := h.inputEventValue(c, backMarkup,
+		h.layout.Text(c, "input_event_end_time"),
+		h.layout.Text(c, "invalid_event_end_time"),
+		func(text string) bool {
+			return validator.EventEndTime(text, params)
+		},
+	)
+	if !ok {
+		return nil
+	}
+
+	newEnd, _ := time.ParseInLocation(eventTimeLayout, endTime, location.Location())
+	changes := eventChanges{
+		Name:       event.Name,
+		OldEndTime: formatEventTime(event.EndTime),
+		EndTime:    formatEventTime(newEnd),
+	}
+	event.EndTime = newEnd.UTC()
+
+	return h.saveEventChanges(c, c.Send, event, changes, backMarkup, "event_end_time_changed")
+}
+
+// editEventRegistrationEnd changes until when the users can register on the event
+func (h Handler) editEventRegistrationEnd(c tele.Context) error {
+	event, backMarkup, err := h.eventForEdit(c, "registration end")
+	if event == nil {
+		return err
+	}
+
+	startTime := formatEventTime(event.StartTime)
+	maxRegisteredEndTime := struct {
+		MaxRegisteredEndTime string
+	}{
+		MaxRegisteredEndTime: utils.GetMaxRegisteredEndTime(startTime),
+	}
+	params := map[string]interface{}{
+		"startTime": startTime,
+	}
+	registrationEnd, ok := h.inputEventValue(c, backMarkup,
+		h.layout.Text(c, "input_event_registered_end_time", maxRegisteredEndTime),
+		h.layout.Text(c, "invalid_event_registered_end_time", maxRegisteredEndTime),
+		func(text string) bool {
+			return validator.EventRegisteredEndTime(text, params)
+		},
+	)
+	if !ok {
+		return nil
+	}
+
+	newRegistrationEnd, _ := time.ParseInLocation(eventTimeLayout, registrationEnd, location.Location())
+	changes := eventChanges{
+		Name:               event.Name,
+		OldRegistrationEnd: formatEventTime(event.RegistrationEnd),
+		RegistrationEnd:    formatEventTime(newRegistrationEnd),
+	}
+	event.RegistrationEnd = newRegistrationEnd.UTC()
+
+	return h.saveEventChanges(c, c.Send, event, changes, backMarkup, "event_registration_end_changed")
+}
+
+// editEventLocation changes where the event is held, the venue is chosen again as the event could have moved to another building
+func (h Handler) editEventLocation(c tele.Context) error {
+	event, backMarkup, err := h.eventForEdit(c, "location")
+	if event == nil {
+		return err
+	}
+
+	eventLocation, ok := h.inputEventValue(c, backMarkup,
+		h.layout.Text(c, "input_event_location"),
+		h.layout.Text(c, "invalid_event_location"),
+		func(text string) bool {
+			return validator.EventLocation(text, nil)
+		},
+	)
+	if !ok {
+		return nil
+	}
+
+	venueID, ok, err := h.inputEventVenue(c, event.VenueID, backMarkup)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while input event venue: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	if !ok {
+		return nil
+	}
+
+	changes := eventChanges{
+		Name:         event.Name,
+		venueChanged: !sameVenue(event.VenueID, venueID),
+	}
+	if eventLocation != event.Location {
+		changes.OldLocation = event.Location
+		changes.Location = eventLocation
+	}
+	event.Location = eventLocation
+	event.VenueID = venueID
+
+	venue, fits, err := h.checkVenueCapacity(context.Background(), *event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get venue: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	if !fits {
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_exceeds_venue_capacity", venue)),
+			backMarkup,
+		)
+	}
+
+	return h.saveEventChanges(c, c.Send, event, changes, backMarkup, "event_location_changed")
+}
+
+// inputEventVenue asks the club owner to choose the venue of the event, nil is returned for the event without venue.
+// It returns false if the input has been cancelled
+func (h Handler) inputEventVenue(c tele.Context, selected *string, backMarkup *tele.ReplyMarkup) (*string, bool, error) {
+	venues, err := h.venueService.GetAll(context.Background())
+	if err != nil {
+		return nil, false, err
+	}
+
+	markup := &tele.ReplyMarkup{}
+	for i, venue := range venues {
+		markup.InlineKeyboard = append(markup.InlineKeyboard, []tele.InlineButton{*h.layout.Button(c, "clubOwner:event:edit:venue", struct {
+			Index    int
+			Name     string
+			Selected bool
+		}{
+			Index:    i,
+			Name:     venue.Name,
+			Selected: selected != nil && *selected == venue.ID,
+		}).Inline()})
+	}
+	markup.InlineKeyboard = append(markup.InlineKeyboard, []tele.InlineButton{*h.layout.Button(c, "clubOwner:event:edit:venue", struct {
+		Index    int
+		Name     string
+		Selected bool
+	}{
+		Index:    -1,
+		Name:     h.layout.Text(c, "no_venue"),
+		Selected: selected == nil,
+	}).Inline()})
+	markup.InlineKeyboard = append(markup.InlineKeyboard, backMarkup.InlineKeyboard...)
+
+	inputCollector := collector.New()
+	prompt := banner.ClubOwner.Caption(h.layout.Text(c, "input_event_venue"))
+	_ = inputCollector.Send(c, prompt, markup)
+
+	venueBtn := h.layout.Button(c, "clubOwner:event:edit:venue")
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0, venueBtn)
+		if response.Message != nil && response.Callback == nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil, false, nil
+		case errGet != nil:
+			return nil, false, errGet
+		case response.Callback != nil:
+			index, errAtoi := strconv.Atoi(response.Callback.Data)
+			if errAtoi != nil || index < -1 || index >= len(venues) {
+				continue
+			}
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			if index == -1 {
+				return nil, true, nil
+			}
+			return &venues[index].ID, true, nil
+		default:
+			// the venue is chosen only by the buttons
+			_ = inputCollector.Send(c, prompt, markup)
+		}
+	}
+}
+
+// sameVenue reports whether both venue ids point to the same venue, nil is the event without venue
+func sameVenue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// editEventRoles shows the roles allowed to register on the event, the selected roles are kept in the callback data until they are saved
+func (h Handler) editEventRoles(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 && len(data) != 3 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.settingsBackMarkup(c, eventID, page),
+		)
+	}
+
+	club, err := h.clubService.Get(context.Background(), event.ClubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.settingsBackMarkup(c, eventID, page),
+		)
+	}
+
+	mask := rolesMask(event.AllowedRoles)
+	if len(data) == 3 {
+		mask, err = strconv.Atoi(data[2])
+		if err != nil {
+			return errorz.ErrInvalidCallbackData
+		}
+	}
+	h.logger.Infof("(user: %d) edit event allowed roles (event_id=%s, mask=%d)", c.Sender().ID, eventID, mask)
+
+	markup := h.layout.Markup(c, "clubOwner:event:roles", struct {
+		ID   string
+		Page string
+		Mask int
+	}{
+		ID:   eventID,
+		Page: page,
+		Mask: mask,
+	})
+	var row []tele.InlineButton
+	for i, role := range entity.AllRoles {
+		// the roles the club is not allowed to invite are not offered, like on the event creation
+		if !slices.Contains(club.AllowedRoles, role.String()) && mask&(1<<i) == 0 {
+			continue
+		}
+		row = append(row, *h.layout.Button(c, "clubOwner:event:roles:role", struct {
+			ID       string
+			Page     string
+			Mask     int
+			RoleName string
+			Allowed  bool
+		}{
+			ID:       eventID,
+			Page:     page,
+			Mask:     mask ^ (1 << i),
+			RoleName: h.layout.Text(c, role.String()),
+			Allowed:  mask&(1<<i) != 0,
+		}).Inline())
+	}
+	markup.InlineKeyboard = append([][]tele.InlineButton{row}, markup.InlineKeyboard...)
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "edit_event_roles_text", event)),
+		markup,
+	)
+}
+
+// saveEventRoles saves the roles selected in editEventRoles
+func (h Handler) saveEventRoles(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 3 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	mask, err := strconv.Atoi(data[2])
+	if err != nil {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) save event allowed roles (event_id=%s, mask=%d)", c.Sender().ID, eventID, mask)
+
+	roles := rolesFromMask(mask)
+	if len(roles) == 0 {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_without_allowed_roles"),
+			ShowAlert: true,
+		})
+	}
+
+	backMarkup := h.settingsBackMarkup(c, eventID, page)
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	if rolesMask(event.AllowedRoles) == mask {
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "event_roles_changed")),
+			backMarkup,
+		)
+	}
+
+	changes := eventChanges{
+		Name:     event.Name,
+		OldRoles: event.AllowedRoles,
+		Roles:    roles,
+	}
+	event.AllowedRoles = roles
+
+	return h.saveEventChanges(c, c.Edit, event, changes, backMarkup, "event_roles_changed")
+}
+
+// rolesMask returns the bit mask of the roles by their indexes in entity.AllRoles
+func rolesMask(roles []string) int {
+	var mask int
+	for i, role := range entity.AllRoles {
+		if slices.Contains(roles, role.String()) {
+			mask |= 1 << i
+		}
+	}
+	return mask
+}
+
+func rolesFromMask(mask int) []string {
+	var roles []string
+	for i, role := range entity.AllRoles {
+		if mask&(1<<i) != 0 {
+			roles = append(roles, role.String())
+		}
+	}
+	return roles
+}
+
+func (h Handler) settingsBackMarkup(c tele.Context, eventID, page string) *tele.ReplyMarkup {
+	return h.layout.Markup(c, "clubOwner:event:settings:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+}
+
+// eventForEdit returns the event of the callback if it has not started yet and has not been cancelled,
+// the event is nil if it can not be edited and the error is the response to the user then
+func (h Handler) eventForEdit(c tele.Context, field string) (*entity.Event, *tele.ReplyMarkup, error) {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return nil, nil, errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) edit event %s (event_id=%s)", c.Sender().ID, field, eventID)
+
+	backMarkup := h.settingsBackMarkup(c, eventID, page)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return nil, backMarkup, c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	switch {
+	case event.IsCancelled():
+		return nil, backMarkup, c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_already_cancelled"),
+			ShowAlert: true,
+		})
+	case event.IsOver(0):
+		return nil, backMarkup, c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_edit_started"),
+			ShowAlert: true,
+		})
+	}
+	return event, backMarkup, nil
+}
+
+// inputEventValue asks the club owner the new value of the event field until the validation passes,
+// returns false if the input has been cancelled
+func (h Handler) inputEventValue(c tele.Context, backMarkup *tele.ReplyMarkup, prompt, invalid string, validate func(string) bool) (string, bool) {
+	inputCollector := collector.New()
+	_ = c.Edit(banner.ClubOwner.Caption(prompt), backMarkup)
+	inputCollector.Collect(c.Message())
+
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return "", false
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input event value: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", prompt)),
+				backMarkup,
+			)
+		case response.Message == nil:
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(h.layout.Text(c, "input_error", prompt)),
+				backMarkup,
+			)
+		case !validate(strings.TrimSpace(response.Message.Text)):
+			_ = inputCollector.Send(c,
+				banner.ClubOwner.Caption(invalid),
+				backMarkup,
+			)
+		default:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			return strings.TrimSpace(response.Message.Text), true
+		}
+	}
+}
+
+// saveEventChanges saves the edited event and sends the changes to the participants, the club owner gets the doneKey text by send.
+// The new time and venue are checked against the other events in the venue, and the reminders are sent again if the event has been moved
+func (h Handler) saveEventChanges(
+	c tele.Context,
+	send func(what interface{}, opts ...interface{}) error,
+	event *entity.Event,
+	changes eventChanges,
+	backMarkup *tele.ReplyMarkup,
+	doneKey string,
+) error {
+	moved := changes.StartTime != "" || changes.EndTime != "" || changes.Location != "" || changes.venueChanged
+	if moved {
+		venue, conflicts, err := h.venueConflicts(c, *event)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while get venue conflicts: %v", c.Sender().ID, err)
+			return send(
+				banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				backMarkup,
+			)
+		}
+		if conflicts != "" {
+			conflictData := struct {
+				Name      string
+				Buffer    int
+				Conflicts string
+			}{
+				Name:      venue.Name,
+				Buffer:    bufferMinutes(venue),
+				Conflicts: conflicts,
+			}
+			if venue.ConflictMode == entity.VenueConflictBlock {
+				return send(
+					banner.ClubOwner.Caption(h.layout.Text(c, "event_venue_conflict_blocked", conflictData)),
+					backMarkup,
+				)
+			}
+
+			confirmed, err := h.confirmVenueConflict(c, send, conflictData, backMarkup)
+			if err != nil {
+				h.logger.Errorf("(user: %d) error while confirm venue conflict: %v", c.Sender().ID, err)
+				return c.Send(
+					banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+					backMarkup,
+				)
+			}
+			if !confirmed {
+				return nil
+			}
+			// the warning has been deleted, so the result is sent as the new message
+			send = c.Send
+		}
+	}
+
+	// The pass of the event with the old data must not be used anymore
+	event.QRFileID = ""
+	// The pass office gets the guests again for the new day or venue
+	if changes.StartTime != "" || changes.venueChanged {
+		event.PassListSentAt = nil
+	}
+	_, err := h.eventService.Update(context.Background(), event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while update event: %v", c.Sender().ID, err)
+		return send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	h.logger.Infof("(user: %d) event updated (event_id=%s)", c.Sender().ID, event.ID)
+
+	if changes.StartTime != "" {
+		if err = h.notificationService.ResetReminders(context.Background(), event.ID); err != nil {
+			h.logger.Errorf("(user: %d) error while reset event reminders: %v", c.Sender().ID, err)
+		}
+	}
+
+	err = h.notificationService.SendEventUpdate(event.ID, "event_notification_changes", changes)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while send event update notification: %v", c.Sender().ID, err)
+	}
+
+	return send(
+		banner.ClubOwner.Caption(h.layout.Text(c, doneKey)),
+		backMarkup,
+	)
+}
+
+// confirmVenueConflict warns the club owner that the edited event overlaps other events in the venue
+// and waits until the changes are confirmed, returns false if the changes have been cancelled
+func (h Handler) confirmVenueConflict(
+	c tele.Context,
+	send func(what interface{}, opts ...interface{}) error,
+	conflictData interface{},
+	backMarkup *tele.ReplyMarkup,
+) (bool, error) {
+	confirmBtn := h.layout.Button(c, "clubOwner:event:edit:conflict:confirm")
+	markup := &tele.ReplyMarkup{
+		InlineKeyboard: append([][]tele.InlineButton{{*confirmBtn.Inline()}}, backMarkup.InlineKeyboard...),
+	}
+	if err := send(banner.ClubOwner.Caption(h.layout.Text(c, "event_venue_conflict_warning", conflictData)), markup); err != nil {
+		return false, err
+	}
+
+	for {
+		response, err := h.input.Get(context.Background(), c.Sender().ID, 0, confirmBtn)
+		switch {
+		case response.Canceled:
+			return false, nil
+		case err != nil:
+			return false, err
+		case response.Callback != nil:
+			if response.Message != nil {
+				_ = c.Bot().Delete(response.Message)
+			}
+			return true, nil
+		case response.Message != nil:
+			// the changes are confirmed only by the button
+			_ = c.Bot().Delete(response.Message)
+		}
+	}
+}
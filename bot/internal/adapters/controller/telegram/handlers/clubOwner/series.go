@@ -0,0 +1,308 @@
+package clubowner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	tele "gopkg.in/telebot.v3"
+)
+
+// maxRecurrenceInterval is the longest interval (in weeks) between the occurrences of a series
+const maxRecurrenceInterval = 2
+
+func (h Handler) recurrenceText(c tele.Context, interval int) string {
+	if interval == 0 {
+		return h.layout.Text(c, "recurrence_once")
+	}
+	return h.layout.Text(c, "recurrence_interval", struct {
+		Interval int
+	}{
+		Interval: interval,
+	})
+}
+
+func (h Handler) recurrenceRow(c tele.Context, clubID string, interval int) []tele.InlineButton {
+	return []tele.InlineButton{*h.layout.Button(c, "clubOwner:create_event:recurrence", struct {
+		ID         string
+		Recurrence string
+	}{
+		ID:         clubID,
+		Recurrence: h.recurrenceText(c, interval),
+	}).Inline()}
+}
+
+func (h Handler) eventSeries(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) edit event series (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+	if event.SeriesID == nil {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	series, err := h.eventSeriesService.Get(context.Background(), *event.SeriesID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event series: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	occurrences, err := h.eventSeriesService.GetFutureOccurrences(context.Background(), series.ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get series occurrences: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	dates := make([]string, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		dates = append(dates, occurrence.StartTime.In(location.Location()).Format("02.01.2006 15:04"))
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "club_owner_series_text", struct {
+			Name        string
+			Recurrence  string
+			StartTime   string
+			Occurrences []string
+		}{
+			Name:        series.Name,
+			Recurrence:  h.recurrenceText(c, series.Interval),
+			StartTime:   series.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+			Occurrences: dates,
+		})),
+		h.layout.Markup(c, "clubOwner:event:series", struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: page,
+		}),
+	)
+}
+
+func (h Handler) eventSeriesApply(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) apply event settings to series (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:series:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+	if event.SeriesID == nil {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	updated, err := h.eventSeriesService.ApplyToSeries(context.Background(), event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while apply event settings to series: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:series:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	for _, occurrence := range updated {
+		if err = h.waitlistService.PromoteNext(context.Background(), occurrence.ID); err != nil {
+			h.logger.Errorf("(user: %d) error while promote waitlist: %v", c.Sender().ID, err)
+		}
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "series_applied", struct {
+			Name  string
+			Count int
+		}{
+			Name:  event.Name,
+			Count: len(updated),
+		})),
+		h.layout.Markup(c, "clubOwner:event:series:back", struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: page,
+		}),
+	)
+}
+
+func (h Handler) eventSeriesCancel(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) cancel event series request (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "clubOwner:event:series:back", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}),
+		)
+	}
+
+	return c.Edit(
+		banner.ClubOwner.Caption(h.layout.Text(c, "cancel_series_text", struct {
+			Name string
+		}{
+			Name: event.Name,
+		})),
+		h.layout.Markup(c, "clubOwner:event:series:cancel", struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: page,
+		}),
+	)
+}
+
+// eventSeriesCancelAccept asks the reason of the cancellation, stops the series and cancels its future occurrences
+// one by one, so the users of every occurrence are notified the same way as for a single event
+func (h Handler) eventSeriesCancelAccept(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	eventID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) cancel event series (event_id=%s)", c.Sender().ID, eventID)
+
+	backMarkup := h.layout.Markup(c, "clubOwner:event:series:back", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	if event.SeriesID == nil {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	reason, ok := h.inputEventValue(c, backMarkup,
+		h.layout.Text(c, "input_event_cancel_reason", event),
+		h.layout.Text(c, "invalid_event_cancel_reason"),
+		func(text string) bool {
+			return validator.EventCancelReason(text, nil)
+		},
+	)
+	if !ok {
+		return nil
+	}
+
+	occurrences, err := h.eventSeriesService.Cancel(context.Background(), *event.SeriesID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while cancel event series: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	var cancelled int
+	for i := range occurrences {
+		if _, err = h.cancelAndNotify(c, &occurrences[i], reason); err != nil {
+			h.logger.Errorf("(user: %d) error while cancel series occurrence (event_id=%s): %v", c.Sender().ID, occurrences[i].ID, err)
+			continue
+		}
+		cancelled++
+	}
+
+	return c.Send(
+		banner.ClubOwner.Caption(h.layout.Text(c, "series_cancelled", struct {
+			Name  string
+			Count int
+		}{
+			Name:  event.Name,
+			Count: cancelled,
+		})),
+		h.layout.Markup(c, "clubOwner:event:delete:back", struct {
+			ClubID string
+			Page   string
+		}{
+			ClubID: event.ClubID,
+			Page:   page,
+		}),
+	)
+}
@@ -0,0 +1,153 @@
+package clubowner
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	tele "gopkg.in/telebot.v3"
+)
+
+// venueRows returns the venue picker of the event draft, one venue per row
+//
+// The venue is passed by its index in the list because the callback data is too short for two uuids
+func (h Handler) venueRows(c tele.Context, clubID string, venues []entity.Venue, selected *string) [][]tele.InlineButton {
+	var rows [][]tele.InlineButton
+	for i, venue := range venues {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "clubOwner:create_event:venue", struct {
+			ID       string
+			Index    int
+			Name     string
+			Selected bool
+		}{
+			ID:       clubID,
+			Index:    i,
+			Name:     venue.Name,
+			Selected: selected != nil && *selected == venue.ID,
+		}).Inline()})
+	}
+	return rows
+}
+
+// venueName returns the name of the selected venue, empty if the venue is not selected
+func venueName(venues []entity.Venue, selected *string) string {
+	if selected == nil {
+		return ""
+	}
+	for _, venue := range venues {
+		if venue.ID == *selected {
+			return venue.Name
+		}
+	}
+	return ""
+}
+
+// eventVenue selects the venue of the event draft, the selected venue is unselected
+func (h Handler) eventVenue(c tele.Context) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	clubID := data[0]
+	index, err := strconv.Atoi(data[1])
+	if err != nil {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	backMarkup := h.layout.Markup(c, "clubOwner:club:back", struct {
+		ID string
+	}{
+		ID: clubID,
+	})
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	event, err := h.eventsStorage.Get(c.Sender().ID)
+	if err != nil {
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	venues, err := h.venueService.GetAll(context.Background())
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get venues: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	if index < 0 || index >= len(venues) {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	venueID := venues[index].ID
+	if event.VenueID != nil && *event.VenueID == venueID {
+		event.VenueID = nil
+	} else {
+		event.VenueID = &venueID
+	}
+	h.eventsStorage.Set(c.Sender().ID, event, 0)
+
+	return h.editEventConfirmation(c, club, event)
+}
+
+// checkVenueCapacity checks that the maximum number of participants of the event fits the venue
+func (h Handler) checkVenueCapacity(ctx context.Context, event entity.Event) (*entity.Venue, bool, error) {
+	if event.VenueID == nil {
+		return nil, true, nil
+	}
+
+	venue, err := h.venueService.Get(ctx, *event.VenueID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	fits := venue.Capacity == 0 || (event.MaxParticipants != 0 && event.MaxParticipants <= venue.Capacity)
+	return venue, fits, nil
+}
+
+// venueConflicts returns the venue of the event and the list of the events overlapping it in the venue,
+// the list is empty if the venue is free
+func (h Handler) venueConflicts(c tele.Context, event entity.Event) (*entity.Venue, string, error) {
+	venue, conflicts, err := h.venueService.GetConflicts(context.Background(), event)
+	if err != nil || venue == nil {
+		return nil, "", err
+	}
+
+	const timeLayout = "02.01.2006 15:04"
+
+	lines := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		lines = append(lines, h.layout.Text(c, "venue_conflict_event", struct {
+			Name      string
+			ClubName  string
+			StartTime string
+			EndTime   string
+		}{
+			Name:      conflict.Name,
+			ClubName:  conflict.Club.Name,
+			StartTime: conflict.StartTime.In(location.Location()).Format(timeLayout),
+			EndTime:   conflict.GetEndTime().In(location.Location()).Format(timeLayout),
+		}))
+	}
+	return venue, strings.Join(lines, "\n"), nil
+}
+
+// bufferMinutes returns the setup and teardown buffer of the venue in minutes
+func bufferMinutes(venue *entity.Venue) int {
+	return int(venue.Buffer / time.Minute)
+}
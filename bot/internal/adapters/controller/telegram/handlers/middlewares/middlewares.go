@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/localisation"
 	"strings"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
@@ -133,36 +134,25 @@ func (h Handler) IsClubOwner(next tele.HandlerFunc) tele.HandlerFunc {
 	}
 }
 
-//func (h Handler) Localisation(next tele.HandlerFunc) tele.HandlerFunc {
-//	return func(c tele.Context) error {
-//		user, err := h.userService.Get(context.Background(), c.Sender().ID)
-//		if err != nil {
-//			return err
-//		}
-//		if user.Localisation == "" {
-//			return c.Send(
-//				"<b>🌍 Выберите предпочтительный язык</b>\n"+
-//					"<b>🌍 Choose your preferred language</b>\n",
-//				h.layout.MarkupLocale("en", "pickLanguage"),
-//			)
-//		}
+// Localisation returns the language of the recipient for the layout middleware
 //
-//		return next(c)
-//	}
-//}
-
-//func (h Handler) SetupLocalisation(r tele.Recipient) string {
-//	userID, err := strconv.Atoi(r.Recipient())
-//	if err != nil {
-//		return ""
-//	}
-//
-//	user, err := h.userService.Get(context.Background(), int64(userID))
-//	if err != nil {
-//		return ""
-//	}
-//	return user.Localisation
-//}
+// Registered users get the language saved in their profile,
+// others get the language of their Telegram client if there is a locale for it
+func (h Handler) Localisation(r tele.Recipient) string {
+	sender, ok := r.(*tele.User)
+	if !ok || sender == nil {
+		return ""
+	}
+
+	user, err := h.userService.Get(context.Background(), sender.ID)
+	if err == nil {
+		return localisation.Resolve(user.Localisation)
+	}
+	if localisation.IsSupported(sender.LanguageCode) {
+		return sender.LanguageCode
+	}
+	return ""
+}
 
 // ResetInputOnBack middleware clears the input state when the back button is pressed.
 func (h Handler) ResetInputOnBack(next tele.HandlerFunc) tele.HandlerFunc {
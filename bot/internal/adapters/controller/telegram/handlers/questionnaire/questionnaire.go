@@ -0,0 +1,260 @@
+package questionnaire
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/cmd/bot"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/postgres"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/service"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+	"github.com/nlypage/intele"
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+	"gopkg.in/telebot.v3/layout"
+)
+
+const maxAnswerLength = 500
+
+type userService interface {
+	SetPhone(ctx context.Context, userID int64, phone string) (*entity.User, error)
+}
+
+// Handler asks the users the registration questionnaire of the event and the phone for the pass office,
+// it is shared by the handlers that register users on the events
+type Handler struct {
+	userService userService
+
+	input  *intele.InputManager
+	layout *layout.Layout
+	logger *types.Logger
+}
+
+func New(b *bot.Bot) *Handler {
+	return &Handler{
+		userService: service.NewUserService(postgres.NewUserStorage(b.DB), nil, nil, nil, ""),
+
+		input:  b.Input,
+		layout: b.Layout,
+		logger: b.Logger,
+	}
+}
+
+// Fill asks the user the questions of the event one by one in new messages, the message of the callback is kept.
+//
+// It returns false if the user has cancelled the questionnaire
+func (h Handler) Fill(c tele.Context, event *entity.Event) ([]entity.QuestionAnswer, bool) {
+	h.logger.Infof("(user: %d) fill event questionnaire (event_id=%s)", c.Sender().ID, event.ID)
+
+	optionBtn := h.layout.Button(c, "questionnaire:option")
+	doneBtn := h.layout.Button(c, "questionnaire:done")
+	skipBtn := h.layout.Button(c, "questionnaire:skip")
+
+	inputCollector := collector.New()
+	answers := make([]entity.QuestionAnswer, 0, len(event.Questions))
+	for i, question := range event.Questions {
+		var (
+			selected []int
+			values   []string
+			done     bool
+		)
+		prompt := banner.Events.Caption(h.layout.Text(c, "questionnaire_question", struct {
+			Name     string
+			Number   int
+			Count    int
+			Text     string
+			Hint     string
+			Required bool
+		}{
+			Name:     event.Name,
+			Number:   i + 1,
+			Count:    len(event.Questions),
+			Text:     question.Text,
+			Hint:     h.layout.Text(c, "questionnaire_hint_"+string(question.Type)),
+			Required: question.Required,
+		}))
+		_ = inputCollector.Send(c, prompt, h.questionMarkup(c, question, selected))
+
+		for !done {
+			response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0, optionBtn, doneBtn, skipBtn)
+			if response.Message != nil && response.Callback == nil {
+				inputCollector.Collect(response.Message)
+			}
+			switch {
+			case response.Canceled:
+				_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+				return nil, false
+			case errGet != nil:
+				h.logger.Errorf("(user: %d) error while input questionnaire answer: %v", c.Sender().ID, errGet)
+				_ = inputCollector.Send(c,
+					banner.Events.Caption(h.layout.Text(c, "input_error", question.Text)),
+					h.questionMarkup(c, question, selected),
+				)
+			case response.Callback != nil:
+				switch response.Callback.Unique {
+				case skipBtn.Unique:
+					done = !question.Required
+				case doneBtn.Unique:
+					if len(selected) > 0 || !question.Required {
+						for _, index := range selected {
+							values = append(values, question.Options[index])
+						}
+						done = true
+					}
+				case optionBtn.Unique:
+					index, errAtoi := strconv.Atoi(response.Callback.Data)
+					if errAtoi != nil || index < 0 || index >= len(question.Options) {
+						continue
+					}
+					if question.Type == entity.QuestionSingle {
+						values = []string{question.Options[index]}
+						done = true
+						continue
+					}
+
+					if position := slices.Index(selected, index); position >= 0 {
+						selected = slices.Delete(selected, position, position+1)
+					} else {
+						selected = append(selected, index)
+						slices.Sort(selected)
+					}
+					if response.Message != nil {
+						_, _ = c.Bot().EditReplyMarkup(response.Message, h.questionMarkup(c, question, selected))
+					}
+				}
+			case response.Message == nil:
+				h.logger.Errorf("(user: %d) error while input questionnaire answer: %v", c.Sender().ID, errGet)
+				_ = inputCollector.Send(c,
+					banner.Events.Caption(h.layout.Text(c, "input_error", question.Text)),
+					h.questionMarkup(c, question, selected),
+				)
+			case question.Type.IsChoice():
+				_ = inputCollector.Send(c,
+					banner.Events.Caption(h.layout.Text(c, "questionnaire_choose_option")),
+					h.questionMarkup(c, question, selected),
+				)
+			case question.Type == entity.QuestionNumber && !isNumber(response.Message.Text):
+				_ = inputCollector.Send(c,
+					banner.Events.Caption(h.layout.Text(c, "questionnaire_invalid_number")),
+					h.questionMarkup(c, question, selected),
+				)
+			case utf8.RuneCountInString(response.Message.Text) > maxAnswerLength:
+				_ = inputCollector.Send(c,
+					banner.Events.Caption(h.layout.Text(c, "questionnaire_invalid_text", maxAnswerLength)),
+					h.questionMarkup(c, question, selected),
+				)
+			default:
+				values = []string{strings.TrimSpace(response.Message.Text)}
+				done = true
+			}
+		}
+
+		answers = append(answers, entity.QuestionAnswer{
+			Question: question.Text,
+			Values:   values,
+		})
+	}
+	_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+
+	return answers, true
+}
+
+// FillPhone asks the user the phone for the pass office of the venue in a new message and saves it.
+//
+// It returns false if the user has cancelled the input or the phone can't be saved
+func (h Handler) FillPhone(c tele.Context, event *entity.Event) bool {
+	h.logger.Infof("(user: %d) fill phone for the pass (event_id=%s)", c.Sender().ID, event.ID)
+
+	phone, ok := h.InputPhone(c, h.layout.Text(c, "input_phone", event), h.layout.Markup(c, "questionnaire:cancel"))
+	if !ok {
+		return false
+	}
+
+	if _, err := h.userService.SetPhone(context.Background(), c.Sender().ID, phone); err != nil {
+		h.logger.Errorf("(user: %d) error while set phone: %v", c.Sender().ID, err)
+		_ = c.Send(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+		return false
+	}
+	return true
+}
+
+// InputPhone asks the phone in a new message with the prompt and the markup to cancel the input,
+// the phone can be sent as a message or shared as a contact.
+// The messages are cleared after the input, on cancel the last one is kept for the pressed button to be handled.
+//
+// It returns false if the user has cancelled the input
+func (h Handler) InputPhone(c tele.Context, prompt string, markup *tele.ReplyMarkup) (string, bool) {
+	inputCollector := collector.New()
+	_ = inputCollector.Send(c, banner.Events.Caption(prompt), markup)
+
+	var phone string
+	for phone == "" {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return "", false
+		case errGet != nil || response.Message == nil:
+			h.logger.Errorf("(user: %d) error while input phone: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c, banner.Events.Caption(h.layout.Text(c, "input_error", prompt)), markup)
+		case response.Message.Contact != nil:
+			phone = response.Message.Contact.PhoneNumber
+			if !strings.HasPrefix(phone, "+") {
+				phone = "+" + phone
+			}
+		case !validator.Phone(response.Message.Text, nil):
+			_ = inputCollector.Send(c, banner.Events.Caption(h.layout.Text(c, "invalid_phone")), markup)
+		default:
+			phone = strings.TrimSpace(response.Message.Text)
+		}
+	}
+	_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+
+	return phone, true
+}
+
+// questionMarkup returns the options of the question, one per row, with the buttons to finish or skip it
+func (h Handler) questionMarkup(c tele.Context, question entity.Question, selected []int) *tele.ReplyMarkup {
+	markup := h.layout.Markup(c, "questionnaire:cancel")
+
+	var rows [][]tele.InlineButton
+	if question.Type.IsChoice() {
+		for i, option := range question.Options {
+			rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "questionnaire:option", struct {
+				Index    int
+				Text     string
+				Selected bool
+			}{
+				Index:    i,
+				Text:     option,
+				Selected: slices.Contains(selected, i),
+			}).Inline()})
+		}
+	}
+	if question.Type == entity.QuestionMulti {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "questionnaire:done").Inline()})
+	}
+	if !question.Required {
+		rows = append(rows, []tele.InlineButton{*h.layout.Button(c, "questionnaire:skip").Inline()})
+	}
+
+	markup.InlineKeyboard = append(rows, markup.InlineKeyboard...)
+	return markup
+}
+
+func isNumber(text string) bool {
+	_, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(text), ",", "."), 64)
+	return err == nil
+}
@@ -36,12 +36,14 @@ func (h Handler) auth(c tele.Context, authCode string) error {
 	data := strings.Split(code.CodeContext, ";")
 	email, fio := data[0], data[1]
 
+	locale, _ := h.layout.Locale(c)
 	newUser := entity.User{
-		ID:    c.Sender().ID,
-		Role:  entity.Student,
-		Email: email,
-		FIO:   fio,
+		ID:           c.Sender().ID,
+		Role:         entity.Student,
+		Email:        email,
+		Localisation: locale,
 	}
+	newUser.SetFIO(fio)
 
 	_, err = h.userService.Create(context.Background(), newUser)
 	if err != nil {
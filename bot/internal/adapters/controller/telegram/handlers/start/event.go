@@ -3,11 +3,14 @@ package start
 import (
 	"context"
 	"errors"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/service"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
 	tele "gopkg.in/telebot.v3"
 	"gorm.io/gorm"
+	"slices"
 	"time"
 )
 
@@ -23,6 +26,18 @@ func (h Handler) eventMenu(c tele.Context, eventID string) error {
 			h.layout.Markup(c, "mainMenu:back"),
 		)
 	}
+	if event.IsDraft {
+		return c.Send(
+			banner.Events.Caption(h.layout.Text(c, "event_not_published")),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+	if event.IsCancelled() {
+		return c.Send(
+			banner.Events.Caption(h.layout.Text(c, "event_cancelled_text", event)),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
 
 	var registered bool
 	_, errGetParticipant := h.eventParticipantService.Get(context.Background(), eventID, c.Sender().ID)
@@ -38,11 +53,41 @@ func (h Handler) eventMenu(c tele.Context, eventID string) error {
 		registered = true
 	}
 
+	var (
+		waitlistPosition int
+		pending          bool
+	)
+	if !registered {
+		waitlistPosition, err = h.waitlistService.GetPosition(context.Background(), eventID, c.Sender().ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			h.logger.Errorf("(user: %d) error while get waitlist position: %v", c.Sender().ID, err)
+			return c.Send(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "mainMenu:back"),
+			)
+		}
+
+		_, err = h.applicationService.Get(context.Background(), eventID, c.Sender().ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			h.logger.Errorf("(user: %d) error while get event application: %v", c.Sender().ID, err)
+			return c.Send(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "mainMenu:back"),
+			)
+		}
+		pending = err == nil
+	}
+
 	endTime := event.EndTime.In(location.Location()).Format("02.01.2006 15:04")
 	if event.EndTime.Year() == 1 {
 		endTime = ""
 	}
 
+	cancellationEnd := event.CancellationEnd.In(location.Location()).Format("02.01.2006 15:04")
+	if event.CancellationEnd.Year() == 1 {
+		cancellationEnd = ""
+	}
+
 	_ = c.Send(
 		banner.Events.Caption(h.layout.Text(c, "event_text", struct {
 			Name                  string
@@ -51,9 +96,11 @@ func (h Handler) eventMenu(c tele.Context, eventID string) error {
 			StartTime             string
 			EndTime               string
 			RegistrationEnd       string
+			CancellationEnd       string
 			MaxParticipants       int
 			AfterRegistrationText string
 			IsRegistered          bool
+			WaitlistPosition      int
 		}{
 			Name:                  event.Name,
 			Description:           event.Description,
@@ -61,19 +108,13 @@ func (h Handler) eventMenu(c tele.Context, eventID string) error {
 			StartTime:             event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
 			EndTime:               endTime,
 			RegistrationEnd:       event.RegistrationEnd.In(location.Location()).Format("02.01.2006 15:04"),
+			CancellationEnd:       cancellationEnd,
 			MaxParticipants:       event.MaxParticipants,
 			AfterRegistrationText: event.AfterRegistrationText,
 			IsRegistered:          registered,
+			WaitlistPosition:      waitlistPosition,
 		})),
-		h.layout.Markup(c, "user:url:event", struct {
-			ID           string
-			IsRegistered bool
-			IsOver       bool
-		}{
-			ID:           eventID,
-			IsRegistered: registered,
-			IsOver:       event.IsOver(0),
-		}))
+		h.eventMarkup(c, event, registered, waitlistPosition > 0, pending))
 	return nil
 }
 
@@ -89,6 +130,18 @@ func (h Handler) eventRegister(c tele.Context) error {
 			h.layout.Markup(c, "mainMenu:back"),
 		)
 	}
+	if event.IsDraft {
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "event_not_published")),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+	if event.IsCancelled() {
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "event_cancelled_text", event)),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
 
 	var registered bool
 	_, errGetParticipant := h.eventParticipantService.Get(context.Background(), eventID, c.Sender().ID)
@@ -104,97 +157,146 @@ func (h Handler) eventRegister(c tele.Context) error {
 		registered = true
 	}
 
-	participantsCount, err := h.eventParticipantService.CountByEventID(context.Background(), eventID)
-	if err != nil {
-		h.logger.Errorf("(user: %d) error while get participants count: %v", c.Sender().ID, err)
-		return c.Edit(
-			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-			h.layout.Markup(c, "mainMenu:back"),
-		)
+	var waitlisted bool
+	_, errGetWaitlist := h.waitlistService.Get(context.Background(), eventID, c.Sender().ID)
+	if errGetWaitlist != nil {
+		if !errors.Is(errGetWaitlist, gorm.ErrRecordNotFound) {
+			h.logger.Errorf("(user: %d) error while get waitlist entry: %v", c.Sender().ID, errGetWaitlist)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", errGetWaitlist.Error())),
+				h.layout.Markup(c, "mainMenu:back"),
+			)
+		}
+	} else {
+		waitlisted = true
 	}
 
-	if c.Callback().Unique == "user_url_event_reg" {
-		if !registered {
-			var user *entity.User
-			user, err = h.userService.Get(context.Background(), c.Sender().ID)
-			if err != nil {
-				h.logger.Errorf("(user: %d) error while get user: %v", c.Sender().ID, err)
-				return c.Edit(
-					banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-					h.layout.Markup(c, "mainMenu:back"),
-				)
-			}
+	if c.Callback().Unique == "user_url_event_wl_leave" && waitlisted {
+		h.logger.Infof("(user: %d) leave event waitlist (event_id=%s)", c.Sender().ID, eventID)
+		err = h.waitlistService.Leave(context.Background(), eventID, c.Sender().ID)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while leave waitlist: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "mainMenu:back"),
+			)
+		}
+		waitlisted = false
+	}
 
-			var roleAllowed bool
-			for _, role := range event.AllowedRoles {
-				if role == string(user.Role) {
-					roleAllowed = true
-				}
+	var pending bool
+	_, errGetApplication := h.applicationService.Get(context.Background(), eventID, c.Sender().ID)
+	if errGetApplication != nil {
+		if !errors.Is(errGetApplication, gorm.ErrRecordNotFound) {
+			h.logger.Errorf("(user: %d) error while get event application: %v", c.Sender().ID, errGetApplication)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", errGetApplication.Error())),
+				h.layout.Markup(c, "mainMenu:back"),
+			)
+		}
+	} else {
+		pending = true
+	}
+
+	if c.Callback().Unique == "url_event_app_withdraw" && pending {
+		h.logger.Infof("(user: %d) withdraw event application (event_id=%s)", c.Sender().ID, eventID)
+		err = h.applicationService.Withdraw(context.Background(), eventID, c.Sender().ID)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while withdraw event application: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "mainMenu:back"),
+			)
+		}
+		pending = false
+	}
+
+	// The event card of the club followers registers right away as well
+	isRegister := c.Callback().Unique == "user_url_event_reg" || c.Callback().Unique == "follow_event_reg"
+	if isRegister && !registered && !waitlisted && !pending {
+		// The phone and the questionnaire are asked only when the user can get on the event or its waitlist
+		_, errCheck := h.registrationService.Check(context.Background(), event, c.Sender().ID)
+		if errors.Is(errCheck, errorz.ErrPhoneRequired) {
+			if !h.questionnaireHandler.FillPhone(c, event) {
+				return nil
 			}
+			_, errCheck = h.registrationService.Check(context.Background(), event, c.Sender().ID)
+		}
 
-			if (event.MaxParticipants == 0 || participantsCount < event.MaxParticipants) && event.RegistrationEnd.After(time.Now().In(location.Location())) && roleAllowed {
-				_, err = h.eventParticipantService.Register(context.Background(), eventID, c.Sender().ID)
-				if err != nil {
-					h.logger.Errorf("(user: %d) error while register to event: %v", c.Sender().ID, err)
-					return c.Edit(
-						banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-						h.layout.Markup(c, "mainMenu:back"),
-					)
-				}
-
-				if participantsCount+1 == event.ExpectedParticipants {
-					errSendWarning := h.notificationService.SendClubWarning(event.ClubID,
-						h.layout.Text(c, "expected_participants_reached_warning", struct {
-							Name              string
-							ParticipantsCount int
-						}{
-							Name:              event.Name,
-							ParticipantsCount: participantsCount + 1,
-						}),
-						h.layout.Markup(c, "core:hide"),
-					)
-					if errSendWarning != nil {
-						h.logger.Errorf("(user: %d) error while send expected participants reached warning: %v", c.Sender().ID, errSendWarning)
-					}
-				}
-
-				if participantsCount+1 == event.MaxParticipants {
-					errSendWarning := h.notificationService.SendClubWarning(event.ClubID,
-						h.layout.Text(c, "max_participants_reached_warning", struct {
-							Name              string
-							ParticipantsCount int
-						}{
-							Name:              event.Name,
-							ParticipantsCount: participantsCount + 1,
-						}),
-						h.layout.Markup(c, "core:hide"),
-					)
-					if errSendWarning != nil {
-						h.logger.Errorf("(user: %d) error while send expected participants reached warning: %v", c.Sender().ID, errSendWarning)
-					}
-				}
-
-				registered = true
-			} else {
-				switch {
-				case event.RegistrationEnd.Before(time.Now().In(location.Location())):
-					return c.Respond(&tele.CallbackResponse{
-						Text:      h.layout.Text(c, "registration_ended"),
-						ShowAlert: true,
-					})
-				case event.MaxParticipants > 0 && participantsCount >= event.MaxParticipants:
-					return c.Respond(&tele.CallbackResponse{
-						Text:      h.layout.Text(c, "max_participants_reached"),
-						ShowAlert: true,
-					})
-				case !roleAllowed:
-					return c.Respond(&tele.CallbackResponse{
-						Text:      h.layout.Text(c, "not_allowed_role"),
-						ShowAlert: true,
-					})
-				}
+		var answers []entity.QuestionAnswer
+		if errCheck == nil && len(event.Questions) > 0 {
+			var filled bool
+			answers, filled = h.questionnaireHandler.Fill(c, event)
+			if !filled {
+				return nil
 			}
 		}
+
+		h.logger.Infof("(user: %d) register to event by url (event_id=%s)", c.Sender().ID, eventID)
+		var outcome service.RegistrationOutcome
+		outcome, err = h.registrationService.Register(context.Background(), event, c.Sender().ID, answers)
+		switch {
+		case errors.Is(err, errorz.ErrEventUnavailable):
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "event_unavailable"),
+				ShowAlert: true,
+			})
+		case errors.Is(err, errorz.ErrRegistrationEnded):
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "registration_ended"),
+				ShowAlert: true,
+			})
+		case errors.Is(err, errorz.ErrRoleNotAllowed):
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "not_allowed_role"),
+				ShowAlert: true,
+			})
+		case errors.Is(err, errorz.ErrNoSeats):
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "max_participants_reached"),
+				ShowAlert: true,
+			})
+		case errors.Is(err, errorz.ErrPhoneRequired):
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "phone_required"),
+				ShowAlert: true,
+			})
+		case err != nil:
+			h.logger.Errorf("(user: %d) error while register to event: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "mainMenu:back"),
+			)
+		}
+
+		switch outcome {
+		case service.RegistrationApplication:
+			pending = true
+			_ = c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "event_application_sent"),
+				ShowAlert: true,
+			})
+		case service.RegistrationWaitlist:
+			waitlisted = true
+			_ = c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "max_participants_reached_waitlist_joined"),
+				ShowAlert: true,
+			})
+		default:
+			registered = true
+		}
+	}
+
+	var waitlistPosition int
+	if waitlisted {
+		waitlistPosition, err = h.waitlistService.GetPosition(context.Background(), eventID, c.Sender().ID)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while get waitlist position: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "mainMenu:back"),
+			)
+		}
 	}
 
 	endTime := event.EndTime.In(location.Location()).Format("02.01.2006 15:04")
@@ -202,6 +304,11 @@ func (h Handler) eventRegister(c tele.Context) error {
 		endTime = ""
 	}
 
+	cancellationEnd := event.CancellationEnd.In(location.Location()).Format("02.01.2006 15:04")
+	if event.CancellationEnd.Year() == 1 {
+		cancellationEnd = ""
+	}
+
 	_ = c.Edit(
 		banner.Events.Caption(h.layout.Text(c, "event_text", struct {
 			Name                  string
@@ -210,9 +317,11 @@ func (h Handler) eventRegister(c tele.Context) error {
 			StartTime             string
 			EndTime               string
 			RegistrationEnd       string
+			CancellationEnd       string
 			MaxParticipants       int
 			AfterRegistrationText string
 			IsRegistered          bool
+			WaitlistPosition      int
 		}{
 			Name:                  event.Name,
 			Description:           event.Description,
@@ -220,22 +329,132 @@ func (h Handler) eventRegister(c tele.Context) error {
 			StartTime:             event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
 			EndTime:               endTime,
 			RegistrationEnd:       event.RegistrationEnd.In(location.Location()).Format("02.01.2006 15:04"),
+			CancellationEnd:       cancellationEnd,
 			MaxParticipants:       event.MaxParticipants,
 			AfterRegistrationText: event.AfterRegistrationText,
 			IsRegistered:          registered,
+			WaitlistPosition:      waitlistPosition,
 		})),
-		h.layout.Markup(c, "user:url:event", struct {
-			ID           string
-			IsRegistered bool
-			IsOver       bool
-		}{
-			ID:           eventID,
-			IsRegistered: registered,
-			IsOver:       event.IsOver(0),
-		}))
+		h.eventMarkup(c, event, registered, waitlistPosition > 0, pending))
 	return nil
 }
 
+func (h Handler) eventMarkup(c tele.Context, event *entity.Event, registered, waitlisted, pending bool) *tele.ReplyMarkup {
+	markup := h.layout.Markup(c, "user:url:event", struct {
+		ID               string
+		IsRegistered     bool
+		IsWaitlisted     bool
+		IsPending        bool
+		RequiresApproval bool
+		IsOver           bool
+	}{
+		ID:               event.ID,
+		IsRegistered:     registered,
+		IsWaitlisted:     waitlisted,
+		IsPending:        pending,
+		RequiresApproval: event.RequiresApproval,
+		IsOver:           event.IsOver(0),
+	})
+
+	if pending {
+		markup.InlineKeyboard = slices.Insert(markup.InlineKeyboard, 1, []tele.InlineButton{*h.layout.Button(c, "user:url:event:application_withdraw", struct {
+			ID string
+		}{
+			ID: event.ID,
+		}).Inline()})
+	}
+
+	if waitlisted {
+		markup.InlineKeyboard = slices.Insert(markup.InlineKeyboard, 1, []tele.InlineButton{*h.layout.Button(c, "user:url:event:waitlist_leave", struct {
+			ID string
+		}{
+			ID: event.ID,
+		}).Inline()})
+	}
+
+	if registered && event.MaxGuests > 0 && event.RegistrationEnd.After(time.Now().In(location.Location())) {
+		markup.InlineKeyboard = slices.Insert(markup.InlineKeyboard, 1, []tele.InlineButton{*h.layout.Button(c, "user:url:event:add_guest", struct {
+			ID string
+		}{
+			ID: event.ID,
+		}).Inline()})
+	}
+
+	if registered && event.IsCancellationAllowed() {
+		markup.InlineKeyboard = slices.Insert(markup.InlineKeyboard, 1, []tele.InlineButton{*h.layout.Button(c, "user:url:event:cancel", struct {
+			ID string
+		}{
+			ID: event.ID,
+		}).Inline()})
+	}
+
+	return markup
+}
+
+func (h Handler) eventCancel(c tele.Context) error {
+	eventID := c.Callback().Data
+	h.logger.Infof("(user: %d) cancel registration request by url (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	return c.Edit(
+		banner.Events.Caption(h.layout.Text(c, "cancel_registration_text", event)),
+		h.layout.Markup(c, "user:url:event:cancel", struct {
+			ID string
+		}{
+			ID: eventID,
+		}),
+	)
+}
+
+func (h Handler) eventCancelAccept(c tele.Context) error {
+	eventID := c.Callback().Data
+	h.logger.Infof("(user: %d) cancel registration by url (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	err = h.registrationService.Cancel(context.Background(), event, c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, errorz.ErrCancellationClosed) {
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "cancellation_closed"),
+				ShowAlert: true,
+			})
+		}
+		h.logger.Errorf("(user: %d) error while cancel registration: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	return c.Edit(
+		banner.Events.Caption(h.layout.Text(c, "registration_canceled", event)),
+		h.layout.Markup(c, "mainMenu:back"),
+	)
+}
+
 func (h Handler) SetupURLEvent(group *tele.Group) {
 	group.Handle(h.layout.Callback("user:url:event:register"), h.eventRegister)
+	group.Handle(h.layout.Callback("follow:event:register"), h.eventRegister)
+	group.Handle(h.layout.Callback("user:url:event:waitlist_leave"), h.eventRegister)
+	group.Handle(h.layout.Callback("user:url:event:application_withdraw"), h.eventRegister)
+	group.Handle(h.layout.Callback("user:url:event:cancel"), h.eventCancel)
+	group.Handle(h.layout.Callback("user:url:event:cancel:accept"), h.eventCancelAccept)
+	group.Handle(h.layout.Callback("user:url:event:cancel:back"), h.eventRegister)
+	group.Handle(h.layout.Callback("user:url:event:guests:back"), h.eventRegister)
 }
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
 	tele "gopkg.in/telebot.v3"
 	"gorm.io/gorm"
 	"strings"
@@ -41,7 +44,17 @@ func (h Handler) userQR(c tele.Context, qrCodeID string) error {
 		)
 	}
 
-	if len(userClubs) == 0 {
+	markup := c.Bot().NewMarkup()
+	scannerRows, err := h.scannerEventsRows(c, markup, qrCodeID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while getting scanner events from db: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	if len(userClubs) == 0 && len(scannerRows) == 0 {
 		h.logger.Infof("(user: %d) user has no clubs", c.Sender().ID)
 		return c.Send(
 			banner.ClubOwner.Caption(h.layout.Text(c, "no_clubs")),
@@ -50,7 +63,6 @@ func (h Handler) userQR(c tele.Context, qrCodeID string) error {
 	}
 
 	var rows []tele.Row
-	markup := c.Bot().NewMarkup()
 	for _, club := range userClubs {
 		callbackID, errSet := h.callbacksStorage.Set(fmt.Sprintf("%s %s", club.ID, qrCodeID), time.Minute*5)
 		if errSet != nil {
@@ -66,6 +78,7 @@ func (h Handler) userQR(c tele.Context, qrCodeID string) error {
 		})))
 	}
 
+	rows = append(rows, scannerRows...)
 	rows = append(
 		rows,
 		markup.Row(*h.layout.Button(c, "core:cancel")),
@@ -114,7 +127,17 @@ func (h Handler) backToClubsList(c tele.Context) error {
 		)
 	}
 
-	if len(userClubs) == 0 {
+	markup := c.Bot().NewMarkup()
+	scannerRows, err := h.scannerEventsRows(c, markup, qrCodeID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while getting scanner events from db: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	if len(userClubs) == 0 && len(scannerRows) == 0 {
 		return c.Edit(
 			banner.ClubOwner.Caption(h.layout.Text(c, "no_clubs")),
 			h.layout.Markup(c, "core:hide"),
@@ -122,7 +145,6 @@ func (h Handler) backToClubsList(c tele.Context) error {
 	}
 
 	var rows []tele.Row
-	markup := c.Bot().NewMarkup()
 	for _, club := range userClubs {
 		callbackID, errSet := h.callbacksStorage.Set(fmt.Sprintf("%s %s", club.ID, qrCodeID), time.Minute*5)
 		if errSet != nil {
@@ -138,6 +160,7 @@ func (h Handler) backToClubsList(c tele.Context) error {
 		})))
 	}
 
+	rows = append(rows, scannerRows...)
 	rows = append(
 		rows,
 		markup.Row(*h.layout.Button(c, "core:cancel")),
@@ -156,6 +179,31 @@ func (h Handler) backToClubsList(c tele.Context) error {
 	)
 }
 
+// scannerEventsRows returns the buttons to activate the user QR code on the events the user is a scanner on
+func (h Handler) scannerEventsRows(c tele.Context, markup *tele.ReplyMarkup, qrCodeID string) ([]tele.Row, error) {
+	events, err := h.scannerService.GetActiveEvents(context.Background(), c.Sender().ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []tele.Row
+	for _, event := range events {
+		callbackID, errSet := h.callbacksStorage.Set(fmt.Sprintf("%s %s", event.ID, qrCodeID), time.Minute*5)
+		if errSet != nil {
+			h.logger.Errorf("(user: %d) error while setting callback: %v", c.Sender().ID, errSet)
+			continue
+		}
+		rows = append(rows, markup.Row(*h.layout.Button(c, "clubOwner:activateQR:event", struct {
+			CallbackID string
+			Name       string
+		}{
+			CallbackID: callbackID,
+			Name:       event.Name,
+		})))
+	}
+	return rows, nil
+}
+
 func (h Handler) qrEventsList(c tele.Context) error {
 	callbackData, err := h.callbacksStorage.Get(c.Callback().Data)
 	if err != nil {
@@ -198,6 +246,9 @@ func (h Handler) qrEventsList(c tele.Context) error {
 	var rows []tele.Row
 	markup := c.Bot().NewMarkup()
 	for _, event := range events {
+		if event.IsCancelled() {
+			continue
+		}
 		callbackID, errSet := h.callbacksStorage.Set(fmt.Sprintf("%s %s", event.ID, qrCodeID), time.Minute*5)
 		if errSet != nil {
 			h.logger.Errorf("(user: %d) error while setting callback: %v", c.Sender().ID, errSet)
@@ -266,10 +317,10 @@ func (h Handler) activateUserQR(c tele.Context) error {
 		)
 	}
 
-	if event.IsOver(time.Hour * 24) {
-		h.logger.Infof("(user: %d) event already started (event_id=%s)", c.Sender().ID, eventID)
+	err = h.checkInService.Check(context.Background(), event, user.ID, c.Sender().ID, entity.CheckInUserQR)
+	if err != nil {
 		return c.Edit(
-			banner.ClubOwner.Caption(h.layout.Text(c, "event_started")),
+			banner.ClubOwner.Caption(h.checkInError(c, event, user.ID, err)),
 			h.layout.Markup(c, "core:hide"),
 		)
 	}
@@ -329,16 +380,55 @@ func (h Handler) activateUserQR(c tele.Context) error {
 	)
 }
 
+// checkInError logs the rejected check-in and returns the text explaining why the check-in is not possible now
+func (h Handler) checkInError(c tele.Context, event *entity.Event, userID int64, err error) string {
+	opens, closes := event.CheckInWindow()
+	window := struct {
+		Name   string
+		Opens  string
+		Closes string
+	}{
+		Name:   event.Name,
+		Opens:  opens.In(location.Location()).Format("02.01.2006 15:04"),
+		Closes: closes.In(location.Location()).Format("02.01.2006 15:04"),
+	}
+
+	switch {
+	case errors.Is(err, errorz.ErrEventUnavailable):
+		h.logger.Warnf("(user: %d) check-in on the unavailable event (event_id=%s, user_id=%d)", c.Sender().ID, event.ID, userID)
+		return h.layout.Text(c, "check_in_event_unavailable", window)
+	case errors.Is(err, errorz.ErrCheckInNotOpened):
+		h.logger.Warnf("(user: %d) check-in before the window (event_id=%s, user_id=%d, opens=%s)", c.Sender().ID, event.ID, userID, window.Opens)
+		return h.layout.Text(c, "check_in_not_opened", window)
+	case errors.Is(err, errorz.ErrCheckInClosed):
+		h.logger.Warnf("(user: %d) check-in after the window (event_id=%s, user_id=%d, closes=%s)", c.Sender().ID, event.ID, userID, window.Closes)
+		return h.layout.Text(c, "check_in_closed", window)
+	default:
+		h.logger.Errorf("(user: %d) error while check event check-in: %v", c.Sender().ID, err)
+		return h.layout.Text(c, "technical_issues", err.Error())
+	}
+}
+
 func (h Handler) SetupUserQR(group *tele.Group) {
 	group.Handle(h.layout.Callback("clubOwner:activateQR:clubs:back"), h.backToClubsList)
 	group.Handle(h.layout.Callback("clubOwner:activateQR:club"), h.qrEventsList)
 	group.Handle(h.layout.Callback("clubOwner:activateQR:event"), h.activateUserQR)
 }
 
-func (h Handler) eventQR(c tele.Context, qrCodeID string) error {
+func (h Handler) eventQR(c tele.Context, token string) error {
 	_ = c.Delete()
 	h.logger.Infof("(user: %d) scan event QR code", c.Sender().ID)
 
+	// The event QR codes are regenerated while they are shown, so the photos of the old codes can not be used later
+	qrCodeID, err := h.qrService.ParseEventQRToken(token)
+	if err != nil {
+		h.logger.Infof("(user: %d) event qr token rejected: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Events.Caption(h.layout.Text(c, "qr_expired")),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
 	event, err := h.eventService.GetByQRCodeID(context.Background(), qrCodeID)
 	if err != nil {
 		h.logger.Infof("(user: %d) event qr expired: %v", c.Sender().ID, err)
@@ -348,10 +438,10 @@ func (h Handler) eventQR(c tele.Context, qrCodeID string) error {
 		)
 	}
 
-	if event.IsOver(time.Hour * 24) {
-		h.logger.Infof("(user: %d) event already started (event_id=%s)", c.Sender().ID, event.ID)
-		return c.Edit(
-			banner.Events.Caption(h.layout.Text(c, "event_started")),
+	err = h.checkInService.Check(context.Background(), event, c.Sender().ID, 0, entity.CheckInEventQR)
+	if err != nil {
+		return c.Send(
+			banner.Events.Caption(h.checkInError(c, event, c.Sender().ID, err)),
 			h.layout.Markup(c, "core:hide"),
 		)
 	}
@@ -0,0 +1,71 @@
+package start
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+)
+
+// joinScanners grants the user the scanner role on the event of the invite link,
+// the scanners can activate the user QR codes on the event until its check-in closes
+func (h Handler) joinScanners(c tele.Context, inviteID string) error {
+	_ = c.Delete()
+	h.logger.Infof("(user: %d) open scanner invite", c.Sender().ID)
+
+	user, err := h.userService.Get(context.Background(), c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Send(
+				banner.Auth.Caption(h.layout.Text(c, "personal_data_agreement_text")),
+				h.layout.Markup(c, "auth:personalData:agreementMenu"),
+			)
+		}
+		h.logger.Errorf("(user: %d) error while getting user from db: %v", c.Sender().ID, err)
+		return c.Send(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+	if user.IsBanned {
+		return c.Send(
+			h.layout.Text(c, "banned"),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	event, err := h.scannerService.Join(context.Background(), inviteID, c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, errorz.ErrInviteExpired) || errors.Is(err, gorm.ErrRecordNotFound) {
+			h.logger.Infof("(user: %d) scanner invite expired: %v", c.Sender().ID, err)
+			return c.Send(
+				banner.ClubOwner.Caption(h.layout.Text(c, "scanner_invite_expired")),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+		h.logger.Errorf("(user: %d) error while joining event scanners: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.ClubOwner.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+	h.logger.Infof("(user: %d) joined event scanners (event_id=%s)", c.Sender().ID, event.ID)
+
+	_, closes := event.CheckInWindow()
+	return c.Send(
+		banner.ClubOwner.Caption(h.layout.Text(c, "scanner_joined", struct {
+			Name      string
+			StartTime string
+			Closes    string
+		}{
+			Name:      event.Name,
+			StartTime: event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+			Closes:    closes.In(location.Location()).Format("02.01.2006 15:04"),
+		})),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
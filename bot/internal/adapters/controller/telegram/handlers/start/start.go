@@ -12,6 +12,7 @@ import (
 
 	"github.com/Badsnus/cu-clubs-bot/bot/cmd/bot"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/controller/telegram/handlers/menu"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/controller/telegram/handlers/questionnaire"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/postgres"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis/codes"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis/emails"
@@ -51,15 +52,37 @@ type eventParticipantService interface {
 	Register(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
 	Get(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
 	Update(ctx context.Context, eventParticipant *entity.EventParticipant) (*entity.EventParticipant, error)
-	CountByEventID(ctx context.Context, eventID string) (int, error)
+}
+
+type waitlistService interface {
+	Get(ctx context.Context, eventID string, userID int64) (*entity.EventWaitlist, error)
+	Leave(ctx context.Context, eventID string, userID int64) error
+	GetPosition(ctx context.Context, eventID string, userID int64) (int, error)
+}
+
+type applicationService interface {
+	Get(ctx context.Context, eventID string, userID int64) (*entity.EventApplication, error)
+	Withdraw(ctx context.Context, eventID string, userID int64) error
+}
+
+type registrationService interface {
+	Check(ctx context.Context, event *entity.Event, userID int64) (service.RegistrationOutcome, error)
+	Register(ctx context.Context, event *entity.Event, userID int64, answers []entity.QuestionAnswer) (service.RegistrationOutcome, error)
+	Cancel(ctx context.Context, event *entity.Event, userID int64) error
 }
 
 type qrService interface {
 	RevokeUserQR(ctx context.Context, userID int64) error
+	ParseEventQRToken(token string) (string, error)
 }
 
-type notificationService interface {
-	SendClubWarning(clubID string, what interface{}, opts ...interface{}) error
+type checkInService interface {
+	Check(ctx context.Context, event *entity.Event, userID, scannedBy int64, method entity.CheckInMethod) error
+}
+
+type scannerService interface {
+	Join(ctx context.Context, inviteID string, userID int64) (*entity.Event, error)
+	GetActiveEvents(ctx context.Context, userID int64) ([]entity.Event, error)
 }
 
 type Handler struct {
@@ -67,12 +90,17 @@ type Handler struct {
 	clubService             clubService
 	eventService            eventService
 	eventParticipantService eventParticipantService
+	waitlistService         waitlistService
+	applicationService      applicationService
+	registrationService     registrationService
 	qrService               qrService
-	notificationService     notificationService
+	checkInService          checkInService
+	scannerService          scannerService
 
 	callbacksStorage callbacks.CallbackStorage
 
-	menuHandler *menu.Handler
+	menuHandler          *menu.Handler
+	questionnaireHandler *questionnaire.Handler
 
 	codesStorage  *codes.Storage
 	emailsStorage *emails.Storage
@@ -100,24 +128,61 @@ func New(b *bot.Bot) *Handler {
 		eventSrvc,
 		viper.GetInt64("bot.qr.channel-id"),
 		viper.GetString("settings.qr.logo-path"),
+		viper.GetString("settings.qr.event-secret"),
+		viper.GetDuration("settings.qr.event-period"),
 	)
 	if err != nil {
 		b.Logger.Fatalf("failed to create qr service: %v", err)
 	}
 
+	eventParticipantSrvc := service.NewEventParticipantService(b.Bot, b.Layout, b.Logger, eventParticipantStorage, nil, nil, nil, nil, nil, "", "")
+	waitlistSrvc := service.NewWaitlistService(
+		b.Bot,
+		b.Layout,
+		b.Logger,
+		postgres.NewEventWaitlistStorage(b.DB),
+		eventStorage,
+		eventParticipantStorage,
+		userStorage,
+		viper.GetDuration("settings.waitlist.offer-ttl"),
+	)
+	applicationSrvc := service.NewApplicationService(
+		b.Bot,
+		b.Layout,
+		b.Logger,
+		postgres.NewEventApplicationStorage(b.DB),
+		eventStorage,
+		eventParticipantStorage,
+		userStorage,
+	)
+	notificationSrvc := service.NewNotifyService(b.Bot, b.Layout, b.Logger, clubOwnerSrvc, eventStorage, notificationStorage, userStorage, nil)
+
 	return &Handler{
 		userService:             userSrvc,
 		clubService:             service.NewClubService(clubStorage),
 		eventService:            eventSrvc,
-		eventParticipantService: service.NewEventParticipantService(b.Bot, b.Layout, b.Logger, eventParticipantStorage, nil, nil, nil, nil, nil, 0),
-		qrService:               qrSrvc,
-		notificationService:     service.NewNotifyService(b.Bot, b.Layout, b.Logger, clubOwnerSrvc, eventStorage, notificationStorage, eventParticipantStorage),
-		callbacksStorage:        b.Redis.Callbacks,
-		menuHandler:             menu.New(b),
-		codesStorage:            b.Redis.Codes,
-		emailsStorage:           b.Redis.Emails,
-		layout:                  b.Layout,
-		logger:                  b.Logger,
+		eventParticipantService: eventParticipantSrvc,
+		waitlistService:         waitlistSrvc,
+		applicationService:      applicationSrvc,
+		registrationService: service.NewRegistrationService(
+			b.Logger,
+			userSrvc,
+			eventParticipantSrvc,
+			waitlistSrvc,
+			applicationSrvc,
+			service.NewQuestionnaireService(b.Layout, postgres.NewEventAnswersStorage(b.DB), userStorage),
+			notificationSrvc,
+			postgres.NewVenueStorage(b.DB),
+		),
+		qrService:            qrSrvc,
+		checkInService:       service.NewCheckInService(postgres.NewCheckInAttemptStorage(b.DB)),
+		callbacksStorage:     b.Redis.Callbacks,
+		menuHandler:          menu.New(b),
+		questionnaireHandler: questionnaire.New(b),
+		codesStorage:         b.Redis.Codes,
+		emailsStorage:        b.Redis.Emails,
+		layout:               b.Layout,
+		logger:               b.Logger,
 	}
 }
 
@@ -166,6 +231,8 @@ func (h Handler) Start(c tele.Context) error {
 		return h.eventQR(c, data)
 	case "event":
 		return h.eventMenu(c, data)
+	case "scanner":
+		return h.joinScanners(c, data)
 	default:
 		return c.Send(
 			h.layout.Text(c, "something_went_wrong"),
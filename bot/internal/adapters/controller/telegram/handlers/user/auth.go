@@ -78,11 +78,13 @@ func (h Handler) externalUserAuth(c tele.Context) error {
 		}
 	}
 
+	locale, _ := h.layout.Locale(c)
 	user := entity.User{
-		ID:   c.Sender().ID,
-		Role: entity.ExternalUser,
-		FIO:  fio,
+		ID:           c.Sender().ID,
+		Role:         entity.ExternalUser,
+		Localisation: locale,
 	}
+	user.SetFIO(fio)
 	_, err := h.userService.Create(context.Background(), user)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while creating new user: %v", c.Sender().ID, err)
@@ -163,11 +165,13 @@ func (h Handler) grantUserAuth(c tele.Context) error {
 		}
 	}
 
+	locale, _ := h.layout.Locale(c)
 	user := entity.User{
-		ID:   c.Sender().ID,
-		Role: entity.GrantUser,
-		FIO:  fio,
+		ID:           c.Sender().ID,
+		Role:         entity.GrantUser,
+		Localisation: locale,
 	}
+	user.SetFIO(fio)
 	_, err = h.userService.Create(context.Background(), user)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while creating new user: %v", c.Sender().ID, err)
@@ -0,0 +1,107 @@
+package user
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h Handler) calendarFeed(c tele.Context) error {
+	page, err := strconv.Atoi(c.Callback().Data)
+	if err != nil {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) edit calendar feed", c.Sender().ID)
+
+	user, err := h.userService.Get(context.Background(), c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while getting user from db: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	return h.editCalendarFeed(c, user.CalendarToken, page)
+}
+
+func (h Handler) calendarFeedIssue(c tele.Context) error {
+	page, err := strconv.Atoi(c.Callback().Data)
+	if err != nil {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) issue calendar feed token", c.Sender().ID)
+
+	token, err := h.calendarService.IssueUserToken(context.Background(), c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while issue calendar feed token: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	return h.editCalendarFeed(c, token, page)
+}
+
+func (h Handler) calendarFeedRevoke(c tele.Context) error {
+	page, err := strconv.Atoi(c.Callback().Data)
+	if err != nil {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) revoke calendar feed token", c.Sender().ID)
+
+	err = h.calendarService.RevokeUserToken(context.Background(), c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while revoke calendar feed token: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	return h.editCalendarFeed(c, "", page)
+}
+
+func (h Handler) editCalendarFeed(c tele.Context, token string, page int) error {
+	var url string
+	if token != "" {
+		url = h.calendarService.UserFeedURL(token)
+	}
+
+	markup := c.Bot().NewMarkup()
+	rows := []tele.Row{
+		markup.Row(*h.layout.Button(c, "user:calendar:issue", struct {
+			Page     int
+			HasToken bool
+		}{
+			Page:     page,
+			HasToken: token != "",
+		})),
+	}
+	if token != "" {
+		rows = append(rows, markup.Row(*h.layout.Button(c, "user:calendar:revoke", struct {
+			Page int
+		}{
+			Page: page,
+		})))
+	}
+	rows = append(rows, markup.Row(*h.layout.Button(c, "user:myEvents:back", struct {
+		Page int
+	}{
+		Page: page,
+	})))
+	markup.Inline(rows...)
+
+	return c.Edit(
+		banner.Events.Caption(h.layout.Text(c, "calendar_feed_text", struct {
+			URL string
+		}{
+			URL: url,
+		})),
+		markup,
+	)
+}
@@ -0,0 +1,245 @@
+package user
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h Handler) clubsList(c tele.Context) error {
+	const clubsOnPage = 5
+	h.logger.Infof("(user: %d) edit clubs catalogue", c.Sender().ID)
+
+	var (
+		p        int
+		prevPage int
+		nextPage int
+		err      error
+		rows     []tele.Row
+		menuRow  tele.Row
+	)
+	if c.Callback().Unique != "mainMenu_clubs" {
+		p, err = strconv.Atoi(c.Callback().Data)
+		if err != nil {
+			return errorz.ErrInvalidCallbackData
+		}
+	}
+
+	clubsCount, err := h.clubService.Count(context.Background())
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get clubs count: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	clubs, err := h.clubService.GetWithPagination(context.Background(), clubsOnPage, p*clubsOnPage, "name ASC")
+	if err != nil {
+		h.logger.Errorf(
+			"(user: %d) error while get clubs (offset=%d, limit=%d): %v",
+			c.Sender().ID,
+			p*clubsOnPage,
+			clubsOnPage,
+			err,
+		)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	followedIDs, err := h.clubFollowerService.GetClubIDsByUserID(context.Background(), c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get followed clubs: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	markup := c.Bot().NewMarkup()
+	for _, club := range clubs {
+		rows = append(rows, markup.Row(*h.layout.Button(c, "user:clubs:club", struct {
+			ID        string
+			Name      string
+			Page      int
+			Following bool
+		}{
+			ID:        club.ID,
+			Name:      club.Name,
+			Page:      p,
+			Following: slices.Contains(followedIDs, club.ID),
+		})))
+	}
+	pagesCount := (int(clubsCount) - 1) / clubsOnPage
+	if p == 0 {
+		prevPage = pagesCount
+	} else {
+		prevPage = p - 1
+	}
+
+	if p >= pagesCount {
+		nextPage = 0
+	} else {
+		nextPage = p + 1
+	}
+
+	menuRow = append(menuRow,
+		*h.layout.Button(c, "user:clubs:prev_page", struct {
+			Page int
+		}{
+			Page: prevPage,
+		}),
+		*h.layout.Button(c, "core:page_counter", struct {
+			Page       int
+			PagesCount int
+		}{
+			Page:       p + 1,
+			PagesCount: pagesCount + 1,
+		}),
+		*h.layout.Button(c, "user:clubs:next_page", struct {
+			Page int
+		}{
+			Page: nextPage,
+		}),
+	)
+
+	rows = append(
+		rows,
+		menuRow,
+		markup.Row(*h.layout.Button(c, "mainMenu:back")),
+	)
+
+	markup.Inline(rows...)
+
+	h.logger.Infof(
+		"(user: %d) clubs catalogue (pages_count=%d, page=%d, clubs_count=%d, next_page=%d, prev_page=%d)",
+		c.Sender().ID,
+		pagesCount,
+		p,
+		clubsCount,
+		nextPage,
+		prevPage,
+	)
+
+	return c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "clubs_catalogue")),
+		markup,
+	)
+}
+
+// club shows the club from the catalogue
+func (h Handler) club(c tele.Context) error {
+	return h.clubView(c, false)
+}
+
+// clubFollow follows or unfollows the club from the catalogue
+func (h Handler) clubFollow(c tele.Context) error {
+	return h.clubView(c, true)
+}
+
+// clubView shows the club from the catalogue, the following of the club is switched before if switchFollow is true
+func (h Handler) clubView(c tele.Context, switchFollow bool) error {
+	data := strings.Split(c.Callback().Data, " ")
+	if len(data) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+
+	clubID := data[0]
+	page := data[1]
+	h.logger.Infof("(user: %d) open catalogue club (club_id=%s, switch_follow=%t)", c.Sender().ID, clubID, switchFollow)
+
+	club, err := h.clubService.Get(context.Background(), clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "user:clubs:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	var following bool
+	if switchFollow {
+		following, err = h.clubFollowerService.Switch(context.Background(), c.Sender().ID, clubID)
+	} else {
+		var followedIDs []string
+		followedIDs, err = h.clubFollowerService.GetClubIDsByUserID(context.Background(), c.Sender().ID)
+		following = slices.Contains(followedIDs, clubID)
+	}
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get club following: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "user:clubs:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+	if switchFollow {
+		h.logger.Infof("(user: %d) club following switched (club_id=%s, following=%t)", c.Sender().ID, clubID, following)
+	}
+
+	viewData := struct {
+		ID          string
+		Page        string
+		Name        string
+		Description string
+		Following   bool
+	}{
+		ID:          club.ID,
+		Page:        page,
+		Name:        club.Name,
+		Description: club.Description,
+		Following:   following,
+	}
+	return c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "club_catalogue_text", viewData)),
+		h.layout.Markup(c, "user:clubs:club", viewData),
+	)
+}
+
+// followSwitch follows or unfollows the club from the event card, only the switch button of the card is changed
+func (h Handler) followSwitch(c tele.Context) error {
+	clubID := c.Callback().Data
+	h.logger.Infof("(user: %d) follow switch (club_id=%s)", c.Sender().ID, clubID)
+
+	following, err := h.clubFollowerService.Switch(context.Background(), c.Sender().ID, clubID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while switching club following: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	switchButton := *h.layout.Button(c, "follow:switch", struct {
+		ClubID    string
+		Following bool
+	}{
+		ClubID:    clubID,
+		Following: following,
+	}).Inline()
+
+	// The buttons of the received message keep only the raw callback data
+	markup := c.Message().ReplyMarkup
+	for i := range markup.InlineKeyboard {
+		for j := range markup.InlineKeyboard[i] {
+			if strings.HasPrefix(markup.InlineKeyboard[i][j].Data, "\f"+switchButton.Unique+"|") {
+				markup.InlineKeyboard[i][j] = switchButton
+			}
+		}
+	}
+	return c.Edit(markup)
+}
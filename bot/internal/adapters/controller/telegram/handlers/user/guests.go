@@ -0,0 +1,275 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/validator"
+	"github.com/nlypage/intele/collector"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+)
+
+// addEventGuest registers a guest without Telegram for the participant of the event.
+// It is opened both from the events list (callback data "<event id> <page>") and from the event url (callback data "<event id>")
+func (h Handler) addEventGuest(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	eventID := callbackData[0]
+
+	var backMarkup *tele.ReplyMarkup
+	switch len(callbackData) {
+	case 1:
+		backMarkup = h.layout.Markup(c, "user:url:event:guests:back", struct {
+			ID string
+		}{
+			ID: eventID,
+		})
+	case 2:
+		backMarkup = h.layout.Markup(c, "user:events:event:guests:back", struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: callbackData[1],
+		})
+	default:
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) add event guest (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	_, err = h.eventParticipantService.Get(context.Background(), eventID, c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "event_guests_not_registered"),
+				ShowAlert: true,
+			})
+		}
+		h.logger.Errorf("(user: %d) error while get participant: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	if event.IsCancelled() || event.RegistrationEnd.Before(time.Now().In(location.Location())) {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "registration_ended"),
+			ShowAlert: true,
+		})
+	}
+
+	guests, err := h.eventGuestService.GetByUser(context.Background(), eventID, c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event guests: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	if len(guests) >= event.MaxGuests {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_guests_limit", event.MaxGuests),
+			ShowAlert: true,
+		})
+	}
+
+	noSeats, err := h.eventSeatsTaken(context.Background(), event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while count event seats: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	if noSeats {
+		return c.Respond(&tele.CallbackResponse{
+			Text:      h.layout.Text(c, "event_guests_no_seats"),
+			ShowAlert: true,
+		})
+	}
+
+	inputCollector := collector.New()
+	_ = c.Edit(
+		banner.Events.Caption(h.layout.Text(c, "input_event_guest_fio", struct {
+			Name      string
+			MaxGuests int
+			Guests    []entity.EventGuest
+		}{
+			Name:      event.Name,
+			MaxGuests: event.MaxGuests,
+			Guests:    guests,
+		})),
+		backMarkup,
+	)
+	inputCollector.Collect(c.Message())
+
+	var (
+		fio  string
+		done bool
+	)
+	for {
+		response, errGet := h.input.Get(context.Background(), c.Sender().ID, 0)
+		if response.Message != nil {
+			inputCollector.Collect(response.Message)
+		}
+		switch {
+		case response.Canceled:
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true, ExcludeLast: true})
+			return nil
+		case errGet != nil:
+			h.logger.Errorf("(user: %d) error while input guest fio: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.Events.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "event_guest_fio_request"))),
+				backMarkup,
+			)
+		case response.Message == nil:
+			h.logger.Errorf("(user: %d) error while input guest fio: %v", c.Sender().ID, errGet)
+			_ = inputCollector.Send(c,
+				banner.Events.Caption(h.layout.Text(c, "input_error", h.layout.Text(c, "event_guest_fio_request"))),
+				backMarkup,
+			)
+		case !validator.Fio(response.Message.Text, nil):
+			_ = inputCollector.Send(c,
+				banner.Events.Caption(h.layout.Text(c, "invalid_user_fio")),
+				backMarkup,
+			)
+		case validator.Fio(response.Message.Text, nil):
+			fio = response.Message.Text
+			_ = inputCollector.Clear(c, collector.ClearOptions{IgnoreErrors: true})
+			done = true
+		}
+		if done {
+			break
+		}
+	}
+
+	var phone string
+	phoneRequired, err := h.registrationService.PhoneRequired(context.Background(), event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while check venue phone requirement: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	if phoneRequired {
+		var ok bool
+		phone, ok = h.questionnaireHandler.InputPhone(c, h.layout.Text(c, "input_event_guest_phone", struct {
+			Name string
+			FIO  string
+		}{
+			Name: event.Name,
+			FIO:  fio,
+		}), backMarkup)
+		if !ok {
+			return nil
+		}
+	}
+
+	// The seats could have been taken while the guest name and phone were typed
+	noSeats, err = h.eventSeatsTaken(context.Background(), event)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while count event seats: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+	if noSeats {
+		return c.Send(
+			banner.Events.Caption(h.layout.Text(c, "event_guests_no_seats")),
+			backMarkup,
+		)
+	}
+
+	_, err = h.eventGuestService.Add(context.Background(), event, c.Sender().ID, fio, phone)
+	if err != nil {
+		if errors.Is(err, errorz.ErrGuestsLimit) {
+			return c.Send(
+				banner.Events.Caption(h.layout.Text(c, "event_guests_limit", event.MaxGuests)),
+				backMarkup,
+			)
+		}
+		h.logger.Errorf("(user: %d) error while add event guest: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	participantsCount, err := h.eventParticipantService.CountByEventID(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get participants count: %v", c.Sender().ID, err)
+	} else if participantsCount == event.MaxParticipants {
+		errSendWarning := h.notificationService.SendClubWarning(event.ClubID,
+			"max_participants_reached_warning", struct {
+				Name              string
+				ParticipantsCount int
+			}{
+				Name:              event.Name,
+				ParticipantsCount: participantsCount,
+			},
+		)
+		if errSendWarning != nil {
+			h.logger.Errorf("(user: %d) error while send max participants reached warning: %v", c.Sender().ID, errSendWarning)
+		}
+	}
+
+	guests, err = h.eventGuestService.GetByUser(context.Background(), eventID, c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event guests: %v", c.Sender().ID, err)
+		return c.Send(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			backMarkup,
+		)
+	}
+
+	return c.Send(
+		banner.Events.Caption(h.layout.Text(c, "event_guest_added", struct {
+			Name      string
+			FIO       string
+			MaxGuests int
+			Guests    []entity.EventGuest
+		}{
+			Name:      event.Name,
+			FIO:       fio,
+			MaxGuests: event.MaxGuests,
+			Guests:    guests,
+		})),
+		backMarkup,
+	)
+}
+
+// eventSeatsTaken reports whether there are no free seats left for a guest,
+// the seats reserved by the waitlist are not available as for the direct registration
+func (h Handler) eventSeatsTaken(ctx context.Context, event *entity.Event) (bool, error) {
+	if event.MaxParticipants == 0 {
+		return false, nil
+	}
+
+	participantsCount, err := h.eventParticipantService.CountByEventID(ctx, event.ID)
+	if err != nil {
+		return false, err
+	}
+	waitlistCount, err := h.waitlistService.CountByEventID(ctx, event.ID)
+	if err != nil {
+		return false, err
+	}
+	return participantsCount+waitlistCount >= event.MaxParticipants, nil
+}
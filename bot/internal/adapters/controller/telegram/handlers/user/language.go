@@ -0,0 +1,69 @@
+package user
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/localisation"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h Handler) language(c tele.Context) error {
+	h.logger.Infof("(user: %d) edit language menu", c.Sender().ID)
+
+	current, _ := h.layout.Locale(c)
+	current = localisation.Resolve(current)
+
+	markup := h.layout.Markup(c, "mainMenu:back")
+	for i := len(localisation.Supported) - 1; i >= 0; i-- {
+		locale := localisation.Supported[i]
+		markup.InlineKeyboard = append(
+			[][]tele.InlineButton{{*h.layout.Button(c, "language:pick", struct {
+				Locale   string
+				Name     string
+				Selected bool
+			}{
+				Locale:   locale,
+				Name:     h.layout.TextLocale(locale, "language_name"),
+				Selected: locale == current,
+			}).Inline()}},
+			markup.InlineKeyboard...,
+		)
+	}
+
+	return c.Edit(
+		banner.Menu.Caption(h.layout.Text(c, "language_text")),
+		markup,
+	)
+}
+
+func (h Handler) languagePick(c tele.Context) error {
+	locale := c.Callback().Data
+	if !localisation.IsSupported(locale) {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) pick language (locale=%s)", c.Sender().ID, locale)
+
+	user, err := h.userService.Get(context.Background(), c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while getting user from db: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	user.Localisation = locale
+	_, err = h.userService.Update(context.Background(), user)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while updating user language: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Menu.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	h.layout.SetLocale(c, locale)
+	return h.menuHandler.EditMenu(c)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/Badsnus/cu-clubs-bot/bot/cmd/bot"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/controller/telegram/handlers/menu"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/controller/telegram/handlers/questionnaire"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/postgres"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis/codes"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis/emails"
@@ -28,6 +29,7 @@ import (
 	"gorm.io/gorm"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -51,9 +53,56 @@ type eventService interface {
 }
 
 type eventParticipantService interface {
-	Register(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
 	Get(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
 	CountByEventID(ctx context.Context, eventID string) (int, error)
+	SwitchReminders(ctx context.Context, eventID string, userID int64) (bool, error)
+}
+
+type waitlistService interface {
+	Get(ctx context.Context, eventID string, userID int64) (*entity.EventWaitlist, error)
+	Leave(ctx context.Context, eventID string, userID int64) error
+	Confirm(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
+	GetPosition(ctx context.Context, eventID string, userID int64) (int, error)
+	CountByEventID(ctx context.Context, eventID string) (int, error)
+}
+
+type applicationService interface {
+	Get(ctx context.Context, eventID string, userID int64) (*entity.EventApplication, error)
+	Withdraw(ctx context.Context, eventID string, userID int64) error
+}
+
+type eventGuestService interface {
+	Add(ctx context.Context, event *entity.Event, userID int64, fio, phone string) (*entity.EventGuest, error)
+	GetByUser(ctx context.Context, eventID string, userID int64) ([]entity.EventGuest, error)
+}
+
+type eventSeriesService interface {
+	Get(ctx context.Context, id string) (*entity.EventSeries, error)
+	GetOccurrences(ctx context.Context, seriesID string) ([]entity.Event, error)
+}
+
+type clubService interface {
+	Get(ctx context.Context, id string) (*entity.Club, error)
+	GetWithPagination(ctx context.Context, limit, offset int, order string) ([]entity.Club, error)
+	Count(ctx context.Context) (int64, error)
+}
+
+type clubFollowerService interface {
+	Switch(ctx context.Context, userID int64, clubID string) (bool, error)
+	GetClubIDsByUserID(ctx context.Context, userID int64) ([]string, error)
+}
+
+type calendarService interface {
+	IssueUserToken(ctx context.Context, userID int64) (string, error)
+	RevokeUserToken(ctx context.Context, userID int64) error
+	UserFeedURL(token string) string
+}
+
+type registrationService interface {
+	Check(ctx context.Context, event *entity.Event, userID int64) (service.RegistrationOutcome, error)
+	Register(ctx context.Context, event *entity.Event, userID int64, answers []entity.QuestionAnswer) (service.RegistrationOutcome, error)
+	Cancel(ctx context.Context, event *entity.Event, userID int64) error
+	PhoneRequired(ctx context.Context, event *entity.Event) (bool, error)
 }
 
 type qrService interface {
@@ -61,17 +110,26 @@ type qrService interface {
 }
 
 type notificationService interface {
-	SendClubWarning(clubID string, what interface{}, opts ...interface{}) error
+	SendClubWarning(clubID string, key string, data interface{}) error
 }
 
 type Handler struct {
 	userService             userService
 	eventService            eventService
 	eventParticipantService eventParticipantService
+	waitlistService         waitlistService
+	applicationService      applicationService
+	registrationService     registrationService
+	eventGuestService       eventGuestService
+	eventSeriesService      eventSeriesService
+	clubService             clubService
+	clubFollowerService     clubFollowerService
+	calendarService         calendarService
 	qrService               qrService
 	notificationService     notificationService
 
-	menuHandler *menu.Handler
+	menuHandler          *menu.Handler
+	questionnaireHandler *questionnaire.Handler
 
 	codesStorage  *codes.Storage
 	emailsStorage *emails.Storage
@@ -87,7 +145,7 @@ func New(b *bot.Bot) *Handler {
 	eventParticipantStorage := postgres.NewEventParticipantStorage(b.DB)
 	clubOwnerStorage := postgres.NewClubOwnerStorage(b.DB)
 
-	eventPartService := service.NewEventParticipantService(nil, nil, nil, eventParticipantStorage, nil, nil, nil, nil, nil, 0)
+	eventPartService := service.NewEventParticipantService(nil, nil, nil, eventParticipantStorage, nil, nil, nil, nil, nil, "", "")
 
 	smtpClient := smtp.NewClient(b.SMTPDialer, viper.GetString("service.smtp.domain"), viper.GetString("service.smtp.email"))
 
@@ -103,32 +161,85 @@ func New(b *bot.Bot) *Handler {
 		nil,
 		viper.GetInt64("bot.qr.channel-id"),
 		viper.GetString("settings.qr.logo-path"),
+		viper.GetString("settings.qr.event-secret"),
+		viper.GetDuration("settings.qr.event-period"),
 	)
 
 	if err != nil {
 		b.Logger.Fatalf("failed to create qr service: %v", err)
 	}
 
+	waitlistSrvc := service.NewWaitlistService(
+		b.Bot,
+		b.Layout,
+		b.Logger,
+		postgres.NewEventWaitlistStorage(b.DB),
+		eventStorage,
+		eventParticipantStorage,
+		userStorage,
+		viper.GetDuration("settings.waitlist.offer-ttl"),
+	)
+	applicationSrvc := service.NewApplicationService(
+		b.Bot,
+		b.Layout,
+		b.Logger,
+		postgres.NewEventApplicationStorage(b.DB),
+		eventStorage,
+		eventParticipantStorage,
+		userStorage,
+	)
+	notificationSrvc := service.NewNotifyService(
+		b.Bot,
+		b.Layout,
+		b.Logger,
+		service.NewClubOwnerService(clubOwnerStorage, userStorage),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
 	return &Handler{
 		userService:             userSrvc,
 		eventService:            service.NewEventService(eventStorage),
 		eventParticipantService: eventPartService,
-		qrService:               qrSrvc,
-		notificationService: service.NewNotifyService(
-			b.Bot,
-			b.Layout,
+		waitlistService:         waitlistSrvc,
+		applicationService:      applicationSrvc,
+		registrationService: service.NewRegistrationService(
 			b.Logger,
-			service.NewClubOwnerService(clubOwnerStorage, userStorage),
-			nil,
+			userSrvc,
+			eventPartService,
+			waitlistSrvc,
+			applicationSrvc,
+			service.NewQuestionnaireService(b.Layout, postgres.NewEventAnswersStorage(b.DB), userStorage),
+			notificationSrvc,
+			postgres.NewVenueStorage(b.DB),
+		),
+		eventGuestService: service.NewEventGuestService(postgres.NewEventGuestStorage(b.DB)),
+		eventSeriesService: service.NewEventSeriesService(
+			b.Logger,
+			postgres.NewEventSeriesStorage(b.DB),
+			eventStorage,
+			viper.GetDuration("settings.series.horizon"),
+		),
+		clubService:         service.NewClubService(postgres.NewClubStorage(b.DB)),
+		clubFollowerService: service.NewClubFollowerService(postgres.NewClubFollowerStorage(b.DB)),
+		calendarService: service.NewCalendarService(
+			userStorage,
+			eventParticipantStorage,
 			nil,
 			nil,
+			viper.GetString("settings.http.public-url"),
 		),
-		menuHandler:   menu.New(b),
-		codesStorage:  b.Redis.Codes,
-		emailsStorage: b.Redis.Emails,
-		layout:        b.Layout,
-		input:         b.Input,
-		logger:        b.Logger,
+		qrService:            qrSrvc,
+		notificationService:  notificationSrvc,
+		menuHandler:          menu.New(b),
+		questionnaireHandler: questionnaire.New(b),
+		codesStorage:         b.Redis.Codes,
+		emailsStorage:        b.Redis.Emails,
+		layout:               b.Layout,
+		input:                b.Input,
+		logger:               b.Logger,
 	}
 }
 
@@ -316,6 +427,26 @@ func (h Handler) event(c tele.Context) error {
 			}),
 		)
 	}
+	if event.IsDraft {
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "event_not_published")),
+			h.layout.Markup(c, "user:events:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+	if event.IsCancelled() {
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "event_cancelled_text", event)),
+			h.layout.Markup(c, "user:events:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
 	var registered bool
 	_, errGetParticipant := h.eventParticipantService.Get(context.Background(), eventID, c.Sender().ID)
 	if errGetParticipant != nil {
@@ -334,109 +465,211 @@ func (h Handler) event(c tele.Context) error {
 		registered = true
 	}
 
-	participantsCount, err := h.eventParticipantService.CountByEventID(context.Background(), eventID)
-	if err != nil {
-		h.logger.Errorf("(user: %d) error while get participants count: %v", c.Sender().ID, err)
-		return c.Edit(
-			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-			h.layout.Markup(c, "user:events:back", struct {
-				Page string
-			}{
-				Page: page,
-			}),
-		)
+	var waitlisted bool
+	_, errGetWaitlist := h.waitlistService.Get(context.Background(), eventID, c.Sender().ID)
+	if errGetWaitlist != nil {
+		if !errors.Is(errGetWaitlist, gorm.ErrRecordNotFound) {
+			h.logger.Errorf("(user: %d) error while get waitlist entry: %v", c.Sender().ID, errGetWaitlist)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", errGetWaitlist.Error())),
+				h.layout.Markup(c, "user:events:back", struct {
+					Page string
+				}{
+					Page: page,
+				}),
+			)
+		}
+	} else {
+		waitlisted = true
 	}
 
-	if c.Callback().Unique == "event_register" {
-		if !registered {
-			var user *entity.User
-			user, err = h.userService.Get(context.Background(), c.Sender().ID)
-			if err != nil {
-				h.logger.Errorf("(user: %d) error while get user: %v", c.Sender().ID, err)
-				return c.Edit(
-					banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-					h.layout.Markup(c, "user:events:back", struct {
-						Page string
-					}{
-						Page: page,
-					}),
-				)
-			}
+	if c.Callback().Unique == "event_waitlist_leave" && waitlisted {
+		h.logger.Infof("(user: %d) leave event waitlist (event_id=%s)", c.Sender().ID, eventID)
+		err = h.waitlistService.Leave(context.Background(), eventID, c.Sender().ID)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while leave waitlist: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "user:events:back", struct {
+					Page string
+				}{
+					Page: page,
+				}),
+			)
+		}
+		waitlisted = false
+	}
+
+	var pending bool
+	_, errGetApplication := h.applicationService.Get(context.Background(), eventID, c.Sender().ID)
+	if errGetApplication != nil {
+		if !errors.Is(errGetApplication, gorm.ErrRecordNotFound) {
+			h.logger.Errorf("(user: %d) error while get event application: %v", c.Sender().ID, errGetApplication)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", errGetApplication.Error())),
+				h.layout.Markup(c, "user:events:back", struct {
+					Page string
+				}{
+					Page: page,
+				}),
+			)
+		}
+	} else {
+		pending = true
+	}
 
-			var roleAllowed bool
-			for _, role := range event.AllowedRoles {
-				if role == string(user.Role) {
-					roleAllowed = true
-				}
+	if c.Callback().Unique == "event_app_withdraw" && pending {
+		h.logger.Infof("(user: %d) withdraw event application (event_id=%s)", c.Sender().ID, eventID)
+		err = h.applicationService.Withdraw(context.Background(), eventID, c.Sender().ID)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while withdraw event application: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "user:events:back", struct {
+					Page string
+				}{
+					Page: page,
+				}),
+			)
+		}
+		pending = false
+	}
+
+	if c.Callback().Unique == "event_register" && !registered && !waitlisted && !pending {
+		// The phone and the questionnaire are asked only when the user can get on the event or its waitlist
+		_, errCheck := h.registrationService.Check(context.Background(), event, c.Sender().ID)
+		if errors.Is(errCheck, errorz.ErrPhoneRequired) {
+			if !h.questionnaireHandler.FillPhone(c, event) {
+				return nil
 			}
+			_, errCheck = h.registrationService.Check(context.Background(), event, c.Sender().ID)
+		}
 
-			if (event.MaxParticipants == 0 || participantsCount < event.MaxParticipants) && event.RegistrationEnd.After(time.Now().In(location.Location())) && roleAllowed {
-				_, err = h.eventParticipantService.Register(context.Background(), eventID, c.Sender().ID)
-				if err != nil {
-					h.logger.Errorf("(user: %d) error while register to event: %v", c.Sender().ID, err)
-					return c.Edit(
-						banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
-						h.layout.Markup(c, "user:events:back", struct {
-							Page string
-						}{
-							Page: page,
-						}),
-					)
-				}
-
-				if participantsCount+1 == event.ExpectedParticipants {
-					errSendWarning := h.notificationService.SendClubWarning(event.ClubID,
-						h.layout.Text(c, "expected_participants_reached_warning", struct {
-							Name              string
-							ParticipantsCount int
-						}{
-							Name:              event.Name,
-							ParticipantsCount: participantsCount + 1,
-						}),
-						h.layout.Markup(c, "core:hide"),
-					)
-					if errSendWarning != nil {
-						h.logger.Errorf("(user: %d) error while send expected participants reached warning: %v", c.Sender().ID, errSendWarning)
-					}
-				}
-
-				if participantsCount+1 == event.MaxParticipants {
-					errSendWarning := h.notificationService.SendClubWarning(event.ClubID,
-						h.layout.Text(c, "max_participants_reached_warning", struct {
-							Name              string
-							ParticipantsCount int
-						}{
-							Name:              event.Name,
-							ParticipantsCount: participantsCount + 1,
-						}),
-						h.layout.Markup(c, "core:hide"),
-					)
-					if errSendWarning != nil {
-						h.logger.Errorf("(user: %d) error while send expected participants reached warning: %v", c.Sender().ID, errSendWarning)
-					}
-				}
-				registered = true
-
-			} else {
-				switch {
-				case event.RegistrationEnd.Before(time.Now().In(location.Location())):
-					return c.Respond(&tele.CallbackResponse{
-						Text:      h.layout.Text(c, "registration_ended"),
-						ShowAlert: true,
-					})
-				case event.MaxParticipants > 0 && participantsCount >= event.MaxParticipants:
-					return c.Respond(&tele.CallbackResponse{
-						Text:      h.layout.Text(c, "max_participants_reached"),
-						ShowAlert: true,
-					})
-				case !roleAllowed:
-					return c.Respond(&tele.CallbackResponse{
-						Text:      h.layout.Text(c, "not_allowed_role"),
-						ShowAlert: true,
-					})
-				}
+		var answers []entity.QuestionAnswer
+		if errCheck == nil && len(event.Questions) > 0 {
+			var filled bool
+			answers, filled = h.questionnaireHandler.Fill(c, event)
+			if !filled {
+				return nil
 			}
 		}
+
+		h.logger.Infof("(user: %d) register to event (event_id=%s)", c.Sender().ID, eventID)
+		var outcome service.RegistrationOutcome
+		outcome, err = h.registrationService.Register(context.Background(), event, c.Sender().ID, answers)
+		switch {
+		case errors.Is(err, errorz.ErrEventUnavailable):
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "event_unavailable"),
+				ShowAlert: true,
+			})
+		case errors.Is(err, errorz.ErrRegistrationEnded):
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "registration_ended"),
+				ShowAlert: true,
+			})
+		case errors.Is(err, errorz.ErrRoleNotAllowed):
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "not_allowed_role"),
+				ShowAlert: true,
+			})
+		case errors.Is(err, errorz.ErrNoSeats):
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "max_participants_reached"),
+				ShowAlert: true,
+			})
+		case errors.Is(err, errorz.ErrPhoneRequired):
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "phone_required"),
+				ShowAlert: true,
+			})
+		case err != nil:
+			h.logger.Errorf("(user: %d) error while register to event: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "user:events:back", struct {
+					Page string
+				}{
+					Page: page,
+				}),
+			)
+		}
+
+		switch outcome {
+		case service.RegistrationApplication:
+			pending = true
+			_ = c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "event_application_sent"),
+				ShowAlert: true,
+			})
+		case service.RegistrationWaitlist:
+			waitlisted = true
+			_ = c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "max_participants_reached_waitlist_joined"),
+				ShowAlert: true,
+			})
+		default:
+			registered = true
+		}
+	}
+
+	var waitlistPosition int
+	if waitlisted {
+		waitlistPosition, err = h.waitlistService.GetPosition(context.Background(), eventID, c.Sender().ID)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while get waitlist position: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "user:events:back", struct {
+					Page string
+				}{
+					Page: page,
+				}),
+			)
+		}
+	}
+
+	markup := h.layout.Markup(c, "user:events:event", struct {
+		ID               string
+		Page             string
+		IsRegistered     bool
+		IsWaitlisted     bool
+		IsPending        bool
+		RequiresApproval bool
+	}{
+		ID:               eventID,
+		Page:             page,
+		IsRegistered:     registered,
+		IsWaitlisted:     waitlisted,
+		IsPending:        pending,
+		RequiresApproval: event.RequiresApproval,
+	})
+	if waitlisted {
+		markup.InlineKeyboard = slices.Insert(markup.InlineKeyboard, 1, []tele.InlineButton{*h.layout.Button(c, "user:events:event:waitlist_leave", struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: page,
+		}).Inline()})
+	}
+	if pending {
+		markup.InlineKeyboard = slices.Insert(markup.InlineKeyboard, 1, []tele.InlineButton{*h.layout.Button(c, "user:events:event:application_withdraw", struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: page,
+		}).Inline()})
+	}
+	if registered && event.MaxGuests > 0 && event.RegistrationEnd.After(time.Now().In(location.Location())) {
+		markup.InlineKeyboard = slices.Insert(markup.InlineKeyboard, 1, []tele.InlineButton{*h.layout.Button(c, "user:events:event:add_guest", struct {
+			ID   string
+			Page string
+		}{
+			ID:   eventID,
+			Page: page,
+		}).Inline()})
 	}
 
 	endTime := event.EndTime.In(location.Location()).Format("02.01.2006 15:04")
@@ -444,6 +677,11 @@ func (h Handler) event(c tele.Context) error {
 		endTime = ""
 	}
 
+	cancellationEnd := event.CancellationEnd.In(location.Location()).Format("02.01.2006 15:04")
+	if event.CancellationEnd.Year() == 1 {
+		cancellationEnd = ""
+	}
+
 	_ = c.Edit(
 		banner.Events.Caption(h.layout.Text(c, "event_text", struct {
 			Name                  string
@@ -452,9 +690,11 @@ func (h Handler) event(c tele.Context) error {
 			StartTime             string
 			EndTime               string
 			RegistrationEnd       string
+			CancellationEnd       string
 			MaxParticipants       int
 			AfterRegistrationText string
 			IsRegistered          bool
+			WaitlistPosition      int
 		}{
 			Name:                  event.Name,
 			Description:           event.Description,
@@ -462,19 +702,13 @@ func (h Handler) event(c tele.Context) error {
 			StartTime:             event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
 			EndTime:               endTime,
 			RegistrationEnd:       event.RegistrationEnd.In(location.Location()).Format("02.01.2006 15:04"),
+			CancellationEnd:       cancellationEnd,
 			MaxParticipants:       event.MaxParticipants,
 			AfterRegistrationText: event.AfterRegistrationText,
 			IsRegistered:          registered,
+			WaitlistPosition:      waitlistPosition,
 		})),
-		h.layout.Markup(c, "user:events:event", struct {
-			ID           string
-			Page         string
-			IsRegistered bool
-		}{
-			ID:           eventID,
-			Page:         page,
-			IsRegistered: registered,
-		}))
+		markup)
 	return nil
 }
 
@@ -491,7 +725,35 @@ func (h Handler) eventExportToICS(c tele.Context) error {
 		)
 	}
 
-	ics, err := calendar.ExportEventToICS(*event)
+	// the whole series is exported if the event is an occurrence of a series that is still active
+	var (
+		series      *entity.EventSeries
+		occurrences []entity.Event
+	)
+	if event.SeriesID != nil {
+		series, err = h.eventSeriesService.Get(context.Background(), *event.SeriesID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			series = nil
+		} else if err != nil {
+			h.logger.Errorf("(user: %d) error while get event series: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+	}
+	if series != nil {
+		occurrences, err = h.eventSeriesService.GetOccurrences(context.Background(), series.ID)
+		if err != nil {
+			h.logger.Errorf("(user: %d) error while get series occurrences: %v", c.Sender().ID, err)
+			return c.Edit(
+				banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+	}
+
+	ics, err := calendar.ExportEventToICS(*event, series, occurrences...)
 	if err != nil {
 		h.logger.Errorf("(user: %d) error while export event to ics: %v", c.Sender().ID, err)
 		return c.Edit(
@@ -574,17 +836,19 @@ func (h Handler) myEvents(c tele.Context) error {
 	markup := c.Bot().NewMarkup()
 	for _, event := range events {
 		rows = append(rows, markup.Row(*h.layout.Button(c, "user:myEvents:event", struct {
-			ID        string
-			Name      string
-			Page      int
-			IsOver    bool
-			IsVisited bool
+			ID          string
+			Name        string
+			Page        int
+			IsOver      bool
+			IsVisited   bool
+			IsCancelled bool
 		}{
-			ID:        event.ID,
-			Name:      event.Name,
-			Page:      p,
-			IsOver:    event.IsOver(0),
-			IsVisited: event.IsVisited,
+			ID:          event.ID,
+			Name:        event.Name,
+			Page:        p,
+			IsOver:      event.IsOver(0),
+			IsVisited:   event.IsVisited,
+			IsCancelled: event.IsCancelled(),
 		})))
 	}
 
@@ -624,6 +888,11 @@ func (h Handler) myEvents(c tele.Context) error {
 	rows = append(
 		rows,
 		menuRow,
+		markup.Row(*h.layout.Button(c, "user:myEvents:calendar", struct {
+			Page int
+		}{
+			Page: p,
+		})),
 		markup.Row(*h.layout.Button(c, "mainMenu:back")),
 	)
 
@@ -682,11 +951,51 @@ func (h Handler) myEvent(c tele.Context) error {
 		)
 	}
 
+	isVisited := eventParticipant.IsVisited()
+	markup := h.layout.Markup(c, "user:myEvents:event", struct {
+		ID   string
+		Page string
+	}{
+		ID:   eventID,
+		Page: page,
+	})
+	if !event.IsOver(0) && !event.IsCancelled() {
+		markup.InlineKeyboard = append(
+			[][]tele.InlineButton{{*h.layout.Button(c, "user:myEvents:event:reminders", struct {
+				ID    string
+				Page  string
+				Muted bool
+			}{
+				ID:    eventID,
+				Page:  page,
+				Muted: eventParticipant.RemindersMuted,
+			}).Inline()}},
+			markup.InlineKeyboard...,
+		)
+	}
+	if !isVisited && !event.IsCancelled() && event.IsCancellationAllowed() {
+		markup.InlineKeyboard = append(
+			[][]tele.InlineButton{{*h.layout.Button(c, "user:myEvents:event:cancel", struct {
+				ID   string
+				Page string
+			}{
+				ID:   eventID,
+				Page: page,
+			}).Inline()}},
+			markup.InlineKeyboard...,
+		)
+	}
+
 	endTime := event.EndTime.In(location.Location()).Format("02.01.2006 15:04")
 	if event.EndTime.Year() == 1 {
 		endTime = ""
 	}
 
+	cancellationEnd := event.CancellationEnd.In(location.Location()).Format("02.01.2006 15:04")
+	if event.CancellationEnd.Year() == 1 {
+		cancellationEnd = ""
+	}
+
 	_ = c.Edit(
 		banner.Events.Caption(h.layout.Text(c, "my_event_text", struct {
 			Name                  string
@@ -695,10 +1004,13 @@ func (h Handler) myEvent(c tele.Context) error {
 			StartTime             string
 			EndTime               string
 			RegistrationEnd       string
+			CancellationEnd       string
 			MaxParticipants       int
 			AfterRegistrationText string
 			IsOver                bool
 			IsVisited             bool
+			IsCancelled           bool
+			CancelReason          string
 		}{
 			Name:                  event.Name,
 			Description:           event.Description,
@@ -706,19 +1018,154 @@ func (h Handler) myEvent(c tele.Context) error {
 			StartTime:             event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
 			EndTime:               endTime,
 			RegistrationEnd:       event.RegistrationEnd.In(location.Location()).Format("02.01.2006 15:04"),
+			CancellationEnd:       cancellationEnd,
 			MaxParticipants:       event.MaxParticipants,
 			AfterRegistrationText: event.AfterRegistrationText,
 			IsOver:                event.IsOver(0),
-			IsVisited:             eventParticipant.IsEventQr || eventParticipant.IsUserQr,
+			IsVisited:             isVisited,
+			IsCancelled:           event.IsCancelled(),
+			CancelReason:          event.CancelReason,
 		})),
-		h.layout.Markup(c, "user:myEvents:event", struct {
+		markup)
+	return nil
+}
+
+func (h Handler) myEventCancel(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	eventID := callbackData[0]
+	page := callbackData[1]
+	h.logger.Infof("(user: %d) cancel registration request (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "user:myEvents:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	return c.Edit(
+		banner.Events.Caption(h.layout.Text(c, "cancel_registration_text", event)),
+		h.layout.Markup(c, "user:myEvents:event:cancel", struct {
 			ID   string
 			Page string
 		}{
 			ID:   eventID,
 			Page: page,
-		}))
-	return nil
+		}),
+	)
+}
+
+func (h Handler) myEventCancelAccept(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	eventID := callbackData[0]
+	page := callbackData[1]
+	h.logger.Infof("(user: %d) cancel registration (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "user:myEvents:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	err = h.registrationService.Cancel(context.Background(), event, c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, errorz.ErrCancellationClosed) {
+			return c.Respond(&tele.CallbackResponse{
+				Text:      h.layout.Text(c, "cancellation_closed"),
+				ShowAlert: true,
+			})
+		}
+		h.logger.Errorf("(user: %d) error while cancel registration: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "user:myEvents:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	return c.Edit(
+		banner.Events.Caption(h.layout.Text(c, "registration_canceled", event)),
+		h.layout.Markup(c, "user:myEvents:back", struct {
+			Page string
+		}{
+			Page: page,
+		}),
+	)
+}
+
+func (h Handler) myEventRemindersSwitch(c tele.Context) error {
+	callbackData := strings.Split(c.Callback().Data, " ")
+	if len(callbackData) != 2 {
+		return errorz.ErrInvalidCallbackData
+	}
+	eventID := callbackData[0]
+	page := callbackData[1]
+	h.logger.Infof("(user: %d) switch event reminders (event_id=%s)", c.Sender().ID, eventID)
+
+	_, err := h.eventParticipantService.SwitchReminders(context.Background(), eventID, c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while switch event reminders: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "user:myEvents:back", struct {
+				Page string
+			}{
+				Page: page,
+			}),
+		)
+	}
+
+	return h.myEvent(c)
+}
+
+func (h Handler) remindersSwitch(c tele.Context) error {
+	eventID := c.Callback().Data
+	if eventID == "" {
+		return errorz.ErrInvalidCallbackData
+	}
+	h.logger.Infof("(user: %d) reminders switch (event_id=%s)", c.Sender().ID, eventID)
+
+	muted, err := h.eventParticipantService.SwitchReminders(context.Background(), eventID, c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while switching event reminders: %v", c.Sender().ID, err)
+		return c.Edit(
+			banner.Events.Caption(h.layout.Text(c, "technical_issues", err.Error())),
+			h.layout.Markup(c, "mainMenu:back"),
+		)
+	}
+
+	return c.Edit(
+		utils.ChangeMessageText(c.Message(), utils.GetMessageText(c.Message())),
+		h.layout.Markup(c, "event_notification_reminder", struct {
+			EventID string
+			Muted   bool
+		}{
+			EventID: eventID,
+			Muted:   muted,
+		}),
+	)
 }
 
 func (h Handler) mailingSwitch(c tele.Context) error {
@@ -756,12 +1203,38 @@ func (h Handler) UserSetup(group *tele.Group) {
 	group.Handle(h.layout.Callback("user:events:event"), h.event)
 	group.Handle(h.layout.Callback("user:myEvents:event:export"), h.eventExportToICS)
 	group.Handle(h.layout.Callback("user:events:event:register"), h.event)
+	group.Handle(h.layout.Callback("user:events:event:waitlist_leave"), h.event)
+	group.Handle(h.layout.Callback("user:events:event:application_withdraw"), h.event)
+	group.Handle(h.layout.Callback("user:events:event:add_guest"), h.addEventGuest)
+	group.Handle(h.layout.Callback("user:url:event:add_guest"), h.addEventGuest)
+	group.Handle(h.layout.Callback("user:events:event:guests:back"), h.event)
+	group.Handle(h.layout.Callback("waitlist:offer:confirm"), h.waitlistConfirm)
+	group.Handle(h.layout.Callback("waitlist:offer:decline"), h.waitlistDecline)
+
+	group.Handle(h.layout.Callback("mainMenu:clubs"), h.clubsList)
+	group.Handle(h.layout.Callback("user:clubs:prev_page"), h.clubsList)
+	group.Handle(h.layout.Callback("user:clubs:next_page"), h.clubsList)
+	group.Handle(h.layout.Callback("user:clubs:back"), h.clubsList)
+	group.Handle(h.layout.Callback("user:clubs:club"), h.club)
+	group.Handle(h.layout.Callback("user:clubs:club:follow"), h.clubFollow)
+	group.Handle(h.layout.Callback("follow:switch"), h.followSwitch)
 
 	group.Handle(h.layout.Callback("mainMenu:my_events"), h.myEvents)
 	group.Handle(h.layout.Callback("user:myEvents:prev_page"), h.myEvents)
 	group.Handle(h.layout.Callback("user:myEvents:next_page"), h.myEvents)
 	group.Handle(h.layout.Callback("user:myEvents:event"), h.myEvent)
 	group.Handle(h.layout.Callback("user:myEvents:back"), h.myEvents)
+	group.Handle(h.layout.Callback("user:myEvents:event:cancel"), h.myEventCancel)
+	group.Handle(h.layout.Callback("user:myEvents:event:cancel:accept"), h.myEventCancelAccept)
+	group.Handle(h.layout.Callback("user:myEvents:event:cancel:back"), h.myEvent)
+	group.Handle(h.layout.Callback("user:myEvents:event:reminders"), h.myEventRemindersSwitch)
+	group.Handle(h.layout.Callback("user:myEvents:calendar"), h.calendarFeed)
+	group.Handle(h.layout.Callback("user:calendar:issue"), h.calendarFeedIssue)
+	group.Handle(h.layout.Callback("user:calendar:revoke"), h.calendarFeedRevoke)
+
+	group.Handle(h.layout.Callback("mainMenu:language"), h.language)
+	group.Handle(h.layout.Callback("language:pick"), h.languagePick)
 
 	group.Handle(h.layout.Callback("mailing:switch"), h.mailingSwitch)
+	group.Handle(h.layout.Callback("reminders:switch"), h.remindersSwitch)
 }
@@ -0,0 +1,72 @@
+package user
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+)
+
+func (h Handler) waitlistConfirm(c tele.Context) error {
+	eventID := c.Callback().Data
+	h.logger.Infof("(user: %d) confirm waitlist offer (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	_, err = h.waitlistService.Confirm(context.Background(), eventID, c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, errorz.ErrOfferExpired) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Edit(
+				h.layout.Text(c, "waitlist_offer_expired", event),
+				h.layout.Markup(c, "core:hide"),
+			)
+		}
+		h.logger.Errorf("(user: %d) error while confirm waitlist offer: %v", c.Sender().ID, err)
+		return c.Edit(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	return c.Edit(
+		h.layout.Text(c, "waitlist_offer_confirmed", event),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
+
+func (h Handler) waitlistDecline(c tele.Context) error {
+	eventID := c.Callback().Data
+	h.logger.Infof("(user: %d) decline waitlist offer (event_id=%s)", c.Sender().ID, eventID)
+
+	event, err := h.eventService.Get(context.Background(), eventID)
+	if err != nil {
+		h.logger.Errorf("(user: %d) error while get event: %v", c.Sender().ID, err)
+		return c.Edit(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	err = h.waitlistService.Leave(context.Background(), eventID, c.Sender().ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		h.logger.Errorf("(user: %d) error while decline waitlist offer: %v", c.Sender().ID, err)
+		return c.Edit(
+			h.layout.Text(c, "technical_issues", err.Error()),
+			h.layout.Markup(c, "core:hide"),
+		)
+	}
+
+	return c.Edit(
+		h.layout.Text(c, "waitlist_offer_declined", event),
+		h.layout.Markup(c, "core:hide"),
+	)
+}
@@ -1,6 +1,8 @@
 package setup
 
 import (
+	"context"
+
 	"github.com/Badsnus/cu-clubs-bot/bot/cmd/bot"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/controller/telegram/handlers/admin"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/controller/telegram/handlers/clubOwner"
@@ -9,7 +11,9 @@ import (
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/controller/telegram/handlers/start"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/controller/telegram/handlers/user"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/postgres"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/service"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/localisation"
 	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger"
 	"github.com/Badsnus/cu-clubs-bot/bot/pkg/smtp"
 	"github.com/spf13/viper"
@@ -18,6 +22,19 @@ import (
 )
 
 func Setup(b *bot.Bot) {
+	var venues []dto.Venue
+	if err := viper.UnmarshalKey("settings.venues", &venues); err != nil {
+		b.Logger.Fatalf("Failed to read venues: %v", err)
+	}
+	// the pass lists used to be configured globally, the venues replaced it
+	if len(venues) == 0 && (viper.IsSet("bot.pass.channel-id") || viper.IsSet("settings.pass-emails")) {
+		b.Logger.Fatalf("bot.pass.channel-id and settings.pass-emails are no longer supported, " +
+			"move them to pass-chat-id and pass-emails of a venue in settings.venues")
+	}
+	if err := service.NewVenueService(postgres.NewVenueStorage(b.DB), postgres.NewEventStorage(b.DB)).Sync(context.Background(), venues); err != nil {
+		b.Logger.Fatalf("Failed to sync venues: %v", err)
+	}
+
 	// Start notification scheduler
 	notifyLogger, err := logger.Named("notify")
 	if err != nil {
@@ -31,6 +48,7 @@ func Setup(b *bot.Bot) {
 		postgres.NewEventStorage(b.DB),
 		postgres.NewNotificationStorage(b.DB),
 		nil,
+		nil,
 	)
 	eventParticipantService := service.NewEventParticipantService(
 		b.Bot,
@@ -41,11 +59,57 @@ func Setup(b *bot.Bot) {
 		postgres.NewUserStorage(b.DB),
 		postgres.NewClubStorage(b.DB),
 		smtp.NewClient(b.SMTPDialer, viper.GetString("service.smtp.domain"), viper.GetString("service.smtp.email")),
-		viper.GetStringSlice("settings.pass-emails"),
-		viper.GetInt64("bot.pass.channel-id"),
+		postgres.NewVenueStorage(b.DB),
+		viper.GetString("bot.pass.schedule"),
+		viper.GetString("bot.pass.locale"),
+	)
+	waitlistService := service.NewWaitlistService(
+		b.Bot,
+		b.Layout,
+		b.Logger,
+		postgres.NewEventWaitlistStorage(b.DB),
+		postgres.NewEventStorage(b.DB),
+		postgres.NewEventParticipantStorage(b.DB),
+		postgres.NewUserStorage(b.DB),
+		viper.GetDuration("settings.waitlist.offer-ttl"),
+	)
+	eventSeriesService := service.NewEventSeriesService(
+		b.Logger,
+		postgres.NewEventSeriesStorage(b.DB),
+		postgres.NewEventStorage(b.DB),
+		viper.GetDuration("settings.series.horizon"),
+	)
+	mailingService := service.NewMailingService(
+		b.Bot,
+		b.Layout,
+		b.Logger,
+		b.Redis.Mailings,
+		viper.GetInt("settings.mailing.rate"),
+	)
+	scheduledMailingService := service.NewScheduledMailingService(
+		b.Bot,
+		b.Logger,
+		postgres.NewScheduledMailingStorage(b.DB),
+		postgres.NewUserStorage(b.DB),
+		mailingService,
+		viper.GetInt64("bot.mailing.channel-id"),
+	)
+	publishService := service.NewPublishService(
+		b.Logger,
+		postgres.NewEventStorage(b.DB),
+		postgres.NewClubStorage(b.DB),
+		postgres.NewUserStorage(b.DB),
+		postgres.NewClubFollowerStorage(b.DB),
+		mailingService,
+		b.Bot.Me.Username,
 	)
 	notifyService.StartNotifyScheduler()
 	eventParticipantService.StartPassScheduler()
+	waitlistService.StartWaitlistScheduler()
+	eventSeriesService.StartSeriesScheduler()
+	mailingService.StartMailingScheduler()
+	scheduledMailingService.StartScheduledMailingScheduler()
+	publishService.StartPublishScheduler()
 
 	// Pre-setup and global middlewares
 	middle := middlewares.New(b)
@@ -60,7 +124,7 @@ func Setup(b *bot.Bot) {
 		b.Use(middleware.Logger())
 	}
 	b.Use(middle.LoadBanners)
-	b.Use(b.Layout.Middleware("ru"))
+	b.Use(b.Layout.Middleware(localisation.Default, middle.Localisation))
 	b.Use(middleware.AutoRespond())
 	b.Handle(tele.OnText, b.Input.MessageHandler())
 	b.Handle(tele.OnMedia, b.Input.MessageHandler())
@@ -69,6 +133,7 @@ func Setup(b *bot.Bot) {
 	b.Handle(b.Layout.Callback("core:hide"), userHandler.Hide)
 	b.Handle(b.Layout.Callback("core:cancel"), userHandler.Hide)
 	b.Handle(b.Layout.Callback("core:back"), userHandler.Hide)
+	b.Handle(b.Layout.Callback("questionnaire:cancel"), userHandler.Hide)
 
 	// Setup handlers
 	//Start
@@ -0,0 +1,139 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/cmd/bot"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/postgres"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/service"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type calendarService interface {
+	GetUserFeed(ctx context.Context, token string) ([]byte, error)
+	GetClubEvents(ctx context.Context, clubID string) (*entity.Club, []entity.Event, error)
+	GetClubFeed(ctx context.Context, clubID string) ([]byte, error)
+}
+
+type Handler struct {
+	calendarService calendarService
+
+	botName string
+	logger  *types.Logger
+}
+
+func New(b *bot.Bot, logger *types.Logger) *Handler {
+	return &Handler{
+		calendarService: service.NewCalendarService(
+			postgres.NewUserStorage(b.DB),
+			postgres.NewEventParticipantStorage(b.DB),
+			postgres.NewClubStorage(b.DB),
+			postgres.NewEventStorage(b.DB),
+			viper.GetString("settings.http.public-url"),
+		),
+
+		botName: b.Bot.Me.Username,
+		logger:  logger,
+	}
+}
+
+// userFeed serves the calendar feed of the user: GET /calendar/<token>.ics
+func (h Handler) userFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/calendar/"), ".ics")
+	if !ok || token == "" || strings.Contains(token, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	feed, err := h.calendarService.GetUserFeed(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		h.logger.Errorf("error while build user calendar feed: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	writeCalendar(w, feed)
+}
+
+// clubEvents serves upcoming public events of the club:
+//   - GET /clubs/<clubID>/events.ics - iCalendar feed
+//   - GET /clubs/<clubID>/events.json - events list
+func (h Handler) clubEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	clubID, file, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/clubs/"), "/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if _, err := uuid.Parse(clubID); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch file {
+	case "events.ics":
+		feed, err := h.calendarService.GetClubFeed(r.Context(), clubID)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		writeCalendar(w, feed)
+	case "events.json":
+		club, events, err := h.calendarService.GetClubEvents(r.Context(), clubID)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if err = json.NewEncoder(w).Encode(dto.NewPublicClubFromEntity(*club, events, h.botName)); err != nil {
+			h.logger.Errorf("error while encode club events: %v", err)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	h.logger.Errorf("error while get club events: %v", err)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+func writeCalendar(w http.ResponseWriter, feed []byte) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = w.Write(feed)
+}
+
+func (h Handler) Setup(mux *http.ServeMux) {
+	mux.HandleFunc("/calendar/", h.userFeed)
+	mux.HandleFunc("/clubs/", h.clubEvents)
+}
@@ -0,0 +1,55 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/cmd/bot"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/controller/web/handlers/calendar"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+	"github.com/spf13/viper"
+)
+
+// Server is the http server that serves calendar feeds
+type Server struct {
+	server *http.Server
+	logger *types.Logger
+}
+
+func NewServer(b *bot.Bot) *Server {
+	httpLogger, err := logger.Named("http")
+	if err != nil {
+		b.Logger.Fatalf("Failed to create http logger: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	calendar.New(b, httpLogger).Setup(mux)
+
+	return &Server{
+		server: &http.Server{
+			Addr:              viper.GetString("settings.http.address"),
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		logger: httpLogger,
+	}
+}
+
+// Start starts the server in the background
+func (s *Server) Start() {
+	s.logger.Infof("Starting http server on %s", s.server.Addr)
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Errorf("http server stopped: %v", err)
+		}
+	}()
+}
+
+// Shutdown gracefully stops the server waiting for active requests to complete
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("Stopping http server")
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type CheckInAttemptStorage struct {
+	db *gorm.DB
+}
+
+func NewCheckInAttemptStorage(db *gorm.DB) *CheckInAttemptStorage {
+	return &CheckInAttemptStorage{
+		db: db,
+	}
+}
+
+func (s *CheckInAttemptStorage) Create(ctx context.Context, attempt *entity.CheckInAttempt) (*entity.CheckInAttempt, error) {
+	err := s.db.WithContext(ctx).Create(&attempt).Error
+	return attempt, err
+}
+
+func (s *CheckInAttemptStorage) CountByEventID(ctx context.Context, eventID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&entity.CheckInAttempt{}).Where("event_id = ?", eventID).Count(&count).Error
+	return count, err
+}
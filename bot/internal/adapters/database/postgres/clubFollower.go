@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type ClubFollowerStorage struct {
+	db *gorm.DB
+}
+
+func NewClubFollowerStorage(db *gorm.DB) *ClubFollowerStorage {
+	return &ClubFollowerStorage{
+		db: db,
+	}
+}
+
+// Switch follows or unfollows the club for the user (returns error and new state)
+func (s *ClubFollowerStorage) Switch(ctx context.Context, userID int64, clubID string) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Where("user_id = ? AND club_id = ?", userID, clubID).
+		Delete(&entity.ClubFollower{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return false, nil
+	}
+
+	err := s.db.WithContext(ctx).
+		Create(&entity.ClubFollower{
+			UserID: userID,
+			ClubID: clubID,
+		}).Error
+	return true, err
+}
+
+// GetClubIDsByUserID returns the ids of the clubs the user follows
+func (s *ClubFollowerStorage) GetClubIDsByUserID(ctx context.Context, userID int64) ([]string, error) {
+	var clubIDs []string
+	err := s.db.WithContext(ctx).
+		Model(&entity.ClubFollower{}).
+		Where("user_id = ?", userID).
+		Pluck("club_id", &clubIDs).Error
+	return clubIDs, err
+}
+
+// GetFollowers returns the users who follow the club
+func (s *ClubFollowerStorage) GetFollowers(ctx context.Context, clubID string) ([]entity.User, error) {
+	var users []entity.User
+	err := s.db.WithContext(ctx).
+		Joins("JOIN club_followers ON club_followers.user_id = users.id").
+		Where("club_followers.club_id = ?", clubID).
+		Find(&users).Error
+	return users, err
+}
+
+func (s *ClubFollowerStorage) CountByClubID(ctx context.Context, clubID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&entity.ClubFollower{}).Where("club_id = ?", clubID).Count(&count).Error
+	return count, err
+}
@@ -39,6 +39,12 @@ func (s *EventStorage) GetByQRCodeID(ctx context.Context, qrCodeID string) (*ent
 	return &event, err
 }
 
+func (s *EventStorage) GetByScannerInviteID(ctx context.Context, inviteID string) (*entity.Event, error) {
+	var event entity.Event
+	err := s.db.WithContext(ctx).Where("scanner_invite_id = ?", inviteID).First(&event).Error
+	return &event, err
+}
+
 func (s *EventStorage) GetMany(ctx context.Context, ids []string) ([]entity.Event, error) {
 	var events []entity.Event
 	err := s.db.WithContext(ctx).Where("id IN ?", ids).Find(&events).Error
@@ -130,6 +136,7 @@ func (s *EventStorage) GetFutureByClubID(
 	var events []entity.Event
 	err := s.db.WithContext(ctx).
 		Where("club_id = ? AND start_time > ?", clubID, time.Now().In(location.Location()).Add(-additionalTime)).
+		Where("is_draft = false").
 		Order(order).
 		Limit(limit).
 		Offset(offset).
@@ -137,15 +144,130 @@ func (s *EventStorage) GetFutureByClubID(
 	return events, err
 }
 
-// GetUpcomingEvents returns all events that start before the given time
+// GetUpcomingEvents returns all published not cancelled events that start before the given time, with preloaded clubs
 func (s *EventStorage) GetUpcomingEvents(ctx context.Context, before time.Time) ([]entity.Event, error) {
 	var events []entity.Event
 	err := s.db.WithContext(ctx).
+		Preload("Club").
 		Where("start_time <= ? AND start_time > ?", before.In(location.Location()), time.Now().In(location.Location())).
+		Where("cancelled_at IS NULL AND is_draft = false").
+		Find(&events).Error
+	return events, err
+}
+
+// MarkPassListSent marks that the pass list with the guests of the events has been sent
+func (s *EventStorage) MarkPassListSent(ctx context.Context, ids []string, sentAt time.Time) error {
+	return s.db.WithContext(ctx).
+		Model(&entity.Event{}).
+		Where("id IN ?", ids).
+		Update("pass_list_sent_at", sentAt.UTC()).Error
+}
+
+// GetByVenueID returns the not cancelled events in the venue that start in [from, to), with preloaded clubs, ordered by the start time
+func (s *EventStorage) GetByVenueID(ctx context.Context, venueID string, from, to time.Time) ([]entity.Event, error) {
+	var events []entity.Event
+	err := s.db.WithContext(ctx).
+		Preload("Club").
+		Where("venue_id = ? AND start_time >= ? AND start_time < ?", venueID, from, to).
+		Where("cancelled_at IS NULL").
+		Order("start_time ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// GetPastByClubID returns the last limit published events of the club that have already started and have not been cancelled, newest first
+func (s *EventStorage) GetPastByClubID(ctx context.Context, clubID string, limit int) ([]entity.Event, error) {
+	var events []entity.Event
+	err := s.db.WithContext(ctx).
+		Where("club_id = ? AND start_time <= ? AND cancelled_at IS NULL AND is_draft = false", clubID, time.Now().In(location.Location())).
+		Order("start_time DESC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// GetFutureBySeriesID returns all occurrences of the series that have not started yet
+func (s *EventStorage) GetFutureBySeriesID(ctx context.Context, seriesID string) ([]entity.Event, error) {
+	var events []entity.Event
+	err := s.db.WithContext(ctx).
+		Where("series_id = ? AND start_time > ?", seriesID, time.Now().In(location.Location())).
+		Order("start_time ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// GetLastSeriesIndex returns the index of the last materialised occurrence of the series
+//
+// Deleted (cancelled) occurrences are taken into account, so they are not materialised again.
+// If the series has no occurrences, it returns -1.
+func (s *EventStorage) GetLastSeriesIndex(ctx context.Context, seriesID string) (int, error) {
+	var index *int
+	err := s.db.WithContext(ctx).
+		Unscoped().
+		Model(&entity.Event{}).
+		Where("series_id = ?", seriesID).
+		Select("MAX(series_index)").
+		Scan(&index).Error
+	if err != nil || index == nil {
+		return -1, err
+	}
+	return *index, nil
+}
+
+// GetAllBySeriesID returns all materialised occurrences of the series including the deleted and cancelled ones
+func (s *EventStorage) GetAllBySeriesID(ctx context.Context, seriesID string) ([]entity.Event, error) {
+	var events []entity.Event
+	err := s.db.WithContext(ctx).
+		Unscoped().
+		Where("series_id = ?", seriesID).
+		Order("series_index ASC").
 		Find(&events).Error
 	return events, err
 }
 
+// GetDueDrafts returns the drafts whose publish time has come, with preloaded clubs
+func (s *EventStorage) GetDueDrafts(ctx context.Context, now time.Time) ([]entity.Event, error) {
+	var events []entity.Event
+	err := s.db.WithContext(ctx).
+		Preload("Club").
+		Where("is_draft = true AND publish_at <= ?", now).
+		Order("publish_at ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// Publish makes the draft visible to the users,
+// gorm.ErrRecordNotFound is returned if the event is not a draft anymore, so the event can't be published twice
+func (s *EventStorage) Publish(ctx context.Context, id string) error {
+	result := s.db.WithContext(ctx).
+		Model(&entity.Event{}).
+		Where("id = ? AND is_draft = true", id).
+		Updates(map[string]interface{}{"is_draft": false, "publish_at": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdatePublication saves the publication settings of the draft,
+// gorm.ErrRecordNotFound is returned if the event is not a draft anymore, so a published event is never turned back into a draft
+func (s *EventStorage) UpdatePublication(ctx context.Context, id string, publishAt *time.Time, announce bool) error {
+	result := s.db.WithContext(ctx).
+		Model(&entity.Event{}).
+		Where("id = ? AND is_draft = true", id).
+		Updates(map[string]interface{}{"publish_at": publishAt, "announce": announce})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 //func (s *EventStorage) CountFutureByClubID(ctx context.Context, clubID string) (int64, error) {
 //	var count int64
 //	err := s.db.WithContext(ctx).
@@ -170,7 +292,7 @@ func (s *EventStorage) Delete(ctx context.Context, id string) error {
 func (s *EventStorage) Count(ctx context.Context, role string) (int64, error) {
 	var count int64
 	query := s.db.WithContext(ctx).Model(&entity.Event{}).
-		Where("registration_end > ?", time.Now()).
+		Where("registration_end > ? AND cancelled_at IS NULL AND is_draft = false", time.Now()).
 		Where("? = ANY(allowed_roles)", role)
 
 	err := query.Count(&count).Error
@@ -197,7 +319,7 @@ func (s *EventStorage) GetWithPagination(ctx context.Context, limit, offset int,
 		Table("events").
 		Select("events.*, CASE WHEN ep.user_id IS NOT NULL THEN true ELSE false END as is_registered").
 		Joins("LEFT JOIN event_participants ep ON events.id = ep.event_id AND ep.user_id = ?", userID).
-		Where("registration_end > ?", time.Now())
+		Where("registration_end > ? AND events.cancelled_at IS NULL AND events.is_draft = false", time.Now())
 
 	if role != "" {
 		query = query.Where("? = ANY(allowed_roles)", role)
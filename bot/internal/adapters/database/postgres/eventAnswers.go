@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventAnswersStorage struct {
+	db *gorm.DB
+}
+
+func NewEventAnswersStorage(db *gorm.DB) *EventAnswersStorage {
+	return &EventAnswersStorage{
+		db: db,
+	}
+}
+
+// Upsert saves the answers of the user, the previous answers to the same event are replaced
+func (s *EventAnswersStorage) Upsert(ctx context.Context, answers *entity.EventAnswers) (*entity.EventAnswers, error) {
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "answers"}),
+	}).Create(&answers).Error
+	return answers, err
+}
+
+func (s *EventAnswersStorage) GetByEventID(ctx context.Context, eventID string) ([]entity.EventAnswers, error) {
+	var answers []entity.EventAnswers
+	err := s.db.WithContext(ctx).Where("event_id = ?", eventID).Find(&answers).Error
+	return answers, err
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type EventApplicationStorage struct {
+	db *gorm.DB
+}
+
+func NewEventApplicationStorage(db *gorm.DB) *EventApplicationStorage {
+	return &EventApplicationStorage{
+		db: db,
+	}
+}
+
+func (s *EventApplicationStorage) Create(ctx context.Context, application *entity.EventApplication) (*entity.EventApplication, error) {
+	err := s.db.WithContext(ctx).Create(&application).Error
+	return application, err
+}
+
+func (s *EventApplicationStorage) Get(ctx context.Context, eventID string, userID int64) (*entity.EventApplication, error) {
+	var application entity.EventApplication
+	err := s.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).First(&application).Error
+	return &application, err
+}
+
+// GetFirst returns the oldest pending application on the event
+func (s *EventApplicationStorage) GetFirst(ctx context.Context, eventID string) (*entity.EventApplication, error) {
+	var application entity.EventApplication
+	err := s.db.WithContext(ctx).Where("event_id = ?", eventID).Order("created_at ASC").First(&application).Error
+	return &application, err
+}
+
+func (s *EventApplicationStorage) Delete(ctx context.Context, eventID string, userID int64) error {
+	err := s.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&entity.EventApplication{}).Error
+	return err
+}
+
+func (s *EventApplicationStorage) CountByEventID(ctx context.Context, eventID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&entity.EventApplication{}).Where("event_id = ?", eventID).Count(&count).Error
+	return count, err
+}
+
+// Approve registers the user on the event and removes the application in a single transaction
+func (s *EventApplicationStorage) Approve(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error) {
+	participant := &entity.EventParticipant{
+		EventID: eventID,
+		UserID:  userID,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&entity.EventApplication{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(&participant).Error
+	})
+	return participant, err
+}
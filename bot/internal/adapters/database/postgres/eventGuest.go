@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type EventGuestStorage struct {
+	db *gorm.DB
+}
+
+func NewEventGuestStorage(db *gorm.DB) *EventGuestStorage {
+	return &EventGuestStorage{
+		db: db,
+	}
+}
+
+func (s *EventGuestStorage) Create(ctx context.Context, guest *entity.EventGuest) (*entity.EventGuest, error) {
+	err := s.db.WithContext(ctx).Create(&guest).Error
+	return guest, err
+}
+
+// GetByUser returns the guests brought to the event by the participant
+func (s *EventGuestStorage) GetByUser(ctx context.Context, eventID string, userID int64) ([]entity.EventGuest, error) {
+	var guests []entity.EventGuest
+	err := s.db.WithContext(ctx).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Order("created_at ASC").
+		Find(&guests).Error
+	return guests, err
+}
+
+func (s *EventGuestStorage) CountByEventID(ctx context.Context, eventID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&entity.EventGuest{}).Where("event_id = ?", eventID).Count(&count).Error
+	return count, err
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
@@ -10,6 +11,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// likeEscaper escapes the wildcards of the LIKE patterns in the search queries
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type EventParticipantStorage struct {
 	db *gorm.DB
 }
@@ -58,9 +62,17 @@ func (s *EventParticipantStorage) Update(ctx context.Context, eventParticipant *
 	return eventParticipant, err
 }
 
+// Delete removes the participant from the event together with the notifications sent to the user about it and the guests
 func (s *EventParticipantStorage) Delete(ctx context.Context, eventID string, userID int64) error {
-	err := s.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&entity.EventParticipant{}).Error
-	return err
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&entity.EventNotification{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&entity.EventGuest{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&entity.EventParticipant{}).Error
+	})
 }
 
 func (s *EventParticipantStorage) GetByEventID(ctx context.Context, eventID string) ([]entity.EventParticipant, error) {
@@ -69,15 +81,84 @@ func (s *EventParticipantStorage) GetByEventID(ctx context.Context, eventID stri
 	return eventParticipants, err
 }
 
+// CountByEventID returns the number of the taken seats of the event, the guests of the participants take seats too
 func (s *EventParticipantStorage) CountByEventID(ctx context.Context, eventID string) (int64, error) {
 	var count int64
-	err := s.db.WithContext(ctx).Model(&entity.EventParticipant{}).Where("event_id = ?", eventID).Count(&count).Error
+	err := s.db.WithContext(ctx).Raw(
+		"SELECT (SELECT COUNT(*) FROM event_participants WHERE event_id = ?) + (SELECT COUNT(*) FROM event_guests WHERE event_id = ?)",
+		eventID, eventID,
+	).Scan(&count).Error
 	return count, err
 }
 
 func (s *EventParticipantStorage) CountVisitedByEventID(ctx context.Context, eventID string) (int64, error) {
 	var count int64
-	err := s.db.WithContext(ctx).Model(&entity.EventParticipant{}).Where("event_id = ? AND (is_event_qr = true OR is_user_qr = true)", eventID).Count(&count).Error
+	err := s.db.WithContext(ctx).Model(&entity.EventParticipant{}).Where("event_id = ? AND (is_event_qr = true OR is_user_qr = true OR is_manual = true)", eventID).Count(&count).Error
+	return count, err
+}
+
+// GetRoster returns the participants of the event ordered by the full name with their visit marks,
+// query filters them by the full name or the username
+func (s *EventParticipantStorage) GetRoster(ctx context.Context, eventID, query string, limit, offset int) ([]dto.EventUser, error) {
+	type userWithVisit struct {
+		entity.User
+		Visited bool
+	}
+
+	var users []userWithVisit
+	err := s.rosterQuery(ctx, eventID, query).
+		Select("users.*, (event_participants.is_user_qr OR event_participants.is_event_qr OR event_participants.is_manual) AS visited").
+		Order("users.fio ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.EventUser, len(users))
+	for i, user := range users {
+		result[i] = dto.NewEventUserFromEntity(user.User, user.Visited)
+	}
+	return result, nil
+}
+
+func (s *EventParticipantStorage) CountRoster(ctx context.Context, eventID, query string) (int64, error) {
+	var count int64
+	err := s.rosterQuery(ctx, eventID, query).Count(&count).Error
+	return count, err
+}
+
+func (s *EventParticipantStorage) rosterQuery(ctx context.Context, eventID, query string) *gorm.DB {
+	db := s.db.WithContext(ctx).
+		Table("event_participants").
+		Joins("JOIN users ON users.id = event_participants.user_id").
+		Where("event_participants.event_id = ?", eventID)
+	if query != "" {
+		pattern := "%" + likeEscaper.Replace(query) + "%"
+		db = db.Where("users.fio ILIKE ? OR users.username ILIKE ?", pattern, pattern)
+	}
+	return db
+}
+
+// SetVisited saves the visit mark of the participant changed by hand together with the record of the change
+func (s *EventParticipantStorage) SetVisited(ctx context.Context, eventParticipant *entity.EventParticipant, changedBy int64) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&eventParticipant).Error; err != nil {
+			return err
+		}
+		return tx.Create(&entity.AttendanceChange{
+			EventID:   eventParticipant.EventID,
+			UserID:    eventParticipant.UserID,
+			ChangedBy: changedBy,
+			Visited:   eventParticipant.IsVisited(),
+		}).Error
+	})
+}
+
+func (s *EventParticipantStorage) CountAttendanceChanges(ctx context.Context, eventID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&entity.AttendanceChange{}).Where("event_id = ?", eventID).Count(&count).Error
 	return count, err
 }
 
@@ -92,6 +173,7 @@ func (s *EventParticipantStorage) GetUserEvents(ctx context.Context, userID int6
 		entity.Event
 		IsUserQr  bool
 		IsEventQr bool
+		IsManual  bool
 	}
 
 	var events []eventWithQR
@@ -111,7 +193,7 @@ func (s *EventParticipantStorage) GetUserEvents(ctx context.Context, userID int6
 	if offset < int(upcomingCount) {
 		if err := s.db.WithContext(ctx).
 			Table("events").
-			Select("events.*, event_participants.is_user_qr, event_participants.is_event_qr").
+			Select("events.*, event_participants.is_user_qr, event_participants.is_event_qr, event_participants.is_manual").
 			Joins("JOIN event_participants ON event_participants.event_id = events.id").
 			Where("event_participants.user_id = ? AND events.start_time > ?", userID, currentTime).
 			Order("events.start_time ASC").
@@ -129,7 +211,7 @@ func (s *EventParticipantStorage) GetUserEvents(ctx context.Context, userID int6
 		var pastEvents []eventWithQR
 		if err := s.db.WithContext(ctx).
 			Table("events").
-			Select("events.*, event_participants.is_user_qr, event_participants.is_event_qr").
+			Select("events.*, event_participants.is_user_qr, event_participants.is_event_qr, event_participants.is_manual").
 			Joins("JOIN event_participants ON event_participants.event_id = events.id").
 			Where("event_participants.user_id = ? AND events.start_time <= ?", userID, currentTime).
 			Order("events.start_time DESC").
@@ -144,12 +226,25 @@ func (s *EventParticipantStorage) GetUserEvents(ctx context.Context, userID int6
 	// Convert to DTOs
 	result := make([]dto.UserEvent, len(events))
 	for i, event := range events {
-		result[i] = dto.NewUserEventFromEntity(event.Event, event.IsUserQr || event.IsEventQr)
+		result[i] = dto.NewUserEventFromEntity(event.Event, event.IsUserQr || event.IsEventQr || event.IsManual)
 	}
 
 	return result, nil
 }
 
+// GetAttendanceByEventIDs returns registrations on the given events with the role of the user and the visit mark
+func (s *EventParticipantStorage) GetAttendanceByEventIDs(ctx context.Context, eventIDs []string) ([]dto.Attendance, error) {
+	var attendance []dto.Attendance
+	err := s.db.WithContext(ctx).
+		Table("event_participants").
+		Select("event_participants.event_id, event_participants.user_id, users.role, "+
+			"(event_participants.is_user_qr OR event_participants.is_event_qr OR event_participants.is_manual) AS visited").
+		Joins("JOIN users ON users.id = event_participants.user_id").
+		Where("event_participants.event_id IN ?", eventIDs).
+		Scan(&attendance).Error
+	return attendance, err
+}
+
 func (s *EventParticipantStorage) CountUserEvents(ctx context.Context, userID int64) (int64, error) {
 	var count int64
 	err := s.db.WithContext(ctx).Model(&entity.EventParticipant{}).
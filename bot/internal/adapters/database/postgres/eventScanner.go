@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventScannerStorage struct {
+	db *gorm.DB
+}
+
+func NewEventScannerStorage(db *gorm.DB) *EventScannerStorage {
+	return &EventScannerStorage{
+		db: db,
+	}
+}
+
+// Create saves the scanner of the event, nothing happens if the user is already a scanner
+func (s *EventScannerStorage) Create(ctx context.Context, scanner *entity.EventScanner) (*entity.EventScanner, error) {
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&scanner).Error
+	return scanner, err
+}
+
+// GetEventsByUserID returns the events the user is a scanner on
+func (s *EventScannerStorage) GetEventsByUserID(ctx context.Context, userID int64) ([]entity.Event, error) {
+	var events []entity.Event
+	err := s.db.WithContext(ctx).
+		Joins("JOIN event_scanners ON event_scanners.event_id = events.id").
+		Where("event_scanners.user_id = ?", userID).
+		Order("events.start_time ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// GetUsersByEventID returns the scanners of the event
+func (s *EventScannerStorage) GetUsersByEventID(ctx context.Context, eventID string) ([]entity.User, error) {
+	var users []entity.User
+	err := s.db.WithContext(ctx).
+		Joins("JOIN event_scanners ON event_scanners.user_id = users.id").
+		Where("event_scanners.event_id = ?", eventID).
+		Order("event_scanners.created_at ASC").
+		Find(&users).Error
+	return users, err
+}
+
+func (s *EventScannerStorage) Delete(ctx context.Context, eventID string, userID int64) error {
+	err := s.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&entity.EventScanner{}).Error
+	return err
+}
+
+func (s *EventScannerStorage) DeleteByEventID(ctx context.Context, eventID string) error {
+	err := s.db.WithContext(ctx).Where("event_id = ?", eventID).Delete(&entity.EventScanner{}).Error
+	return err
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type EventSeriesStorage struct {
+	db *gorm.DB
+}
+
+func NewEventSeriesStorage(db *gorm.DB) *EventSeriesStorage {
+	return &EventSeriesStorage{
+		db: db,
+	}
+}
+
+func (s *EventSeriesStorage) Create(ctx context.Context, series *entity.EventSeries) (*entity.EventSeries, error) {
+	err := s.db.WithContext(ctx).Create(&series).Error
+	return series, err
+}
+
+func (s *EventSeriesStorage) Get(ctx context.Context, id string) (*entity.EventSeries, error) {
+	var series entity.EventSeries
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&series).Error
+	return &series, err
+}
+
+// GetAll returns all series that have not been cancelled
+func (s *EventSeriesStorage) GetAll(ctx context.Context) ([]entity.EventSeries, error) {
+	var series []entity.EventSeries
+	err := s.db.WithContext(ctx).Find(&series).Error
+	return series, err
+}
+
+func (s *EventSeriesStorage) Update(ctx context.Context, series *entity.EventSeries) (*entity.EventSeries, error) {
+	err := s.db.WithContext(ctx).Save(&series).Error
+	return series, err
+}
+
+func (s *EventSeriesStorage) Delete(ctx context.Context, id string) error {
+	err := s.db.WithContext(ctx).Where("id = ?", id).Delete(&entity.EventSeries{}).Error
+	return err
+}
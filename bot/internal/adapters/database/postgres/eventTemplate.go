@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type EventTemplateStorage struct {
+	db *gorm.DB
+}
+
+func NewEventTemplateStorage(db *gorm.DB) *EventTemplateStorage {
+	return &EventTemplateStorage{
+		db: db,
+	}
+}
+
+// Create creates the template, gorm.ErrDuplicatedKey is returned if the club already has the template with the same title
+func (s *EventTemplateStorage) Create(ctx context.Context, template *entity.EventTemplate) (*entity.EventTemplate, error) {
+	err := s.db.WithContext(ctx).Omit("Club").Create(&template).Error
+	return template, err
+}
+
+func (s *EventTemplateStorage) Get(ctx context.Context, id string) (*entity.EventTemplate, error) {
+	var template entity.EventTemplate
+	err := s.db.WithContext(ctx).Preload("Club").Where("id = ?", id).First(&template).Error
+	return &template, err
+}
+
+// GetByClubID returns the templates of the club ordered by the title
+func (s *EventTemplateStorage) GetByClubID(ctx context.Context, clubID string) ([]entity.EventTemplate, error) {
+	var templates []entity.EventTemplate
+	err := s.db.WithContext(ctx).Where("club_id = ?", clubID).Order("title").Find(&templates).Error
+	return templates, err
+}
+
+func (s *EventTemplateStorage) CountByClubID(ctx context.Context, clubID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&entity.EventTemplate{}).Where("club_id = ?", clubID).Count(&count).Error
+	return count, err
+}
+
+// Delete deletes the template, it returns gorm.ErrRecordNotFound if the template has already been deleted
+func (s *EventTemplateStorage) Delete(ctx context.Context, id string) error {
+	result := s.db.WithContext(ctx).Where("id = ?", id).Delete(&entity.EventTemplate{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type EventWaitlistStorage struct {
+	db *gorm.DB
+}
+
+func NewEventWaitlistStorage(db *gorm.DB) *EventWaitlistStorage {
+	return &EventWaitlistStorage{
+		db: db,
+	}
+}
+
+func (s *EventWaitlistStorage) Create(ctx context.Context, entry *entity.EventWaitlist) (*entity.EventWaitlist, error) {
+	err := s.db.WithContext(ctx).Create(&entry).Error
+	return entry, err
+}
+
+func (s *EventWaitlistStorage) Get(ctx context.Context, eventID string, userID int64) (*entity.EventWaitlist, error) {
+	var entry entity.EventWaitlist
+	err := s.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).First(&entry).Error
+	return &entry, err
+}
+
+func (s *EventWaitlistStorage) Update(ctx context.Context, entry *entity.EventWaitlist) (*entity.EventWaitlist, error) {
+	err := s.db.WithContext(ctx).Save(&entry).Error
+	return entry, err
+}
+
+func (s *EventWaitlistStorage) Delete(ctx context.Context, eventID string, userID int64) error {
+	err := s.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&entity.EventWaitlist{}).Error
+	return err
+}
+
+// CountByEventID returns the length of the event queue, including users with an active offer
+func (s *EventWaitlistStorage) CountByEventID(ctx context.Context, eventID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&entity.EventWaitlist{}).Where("event_id = ?", eventID).Count(&count).Error
+	return count, err
+}
+
+// CountOffered returns the number of users on the event queue who have been offered a seat
+func (s *EventWaitlistStorage) CountOffered(ctx context.Context, eventID string) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&entity.EventWaitlist{}).
+		Where("event_id = ? AND offer_expires_at IS NOT NULL", eventID).
+		Count(&count).Error
+	return count, err
+}
+
+// GetPosition returns the 1-based position of the user in the event queue
+func (s *EventWaitlistStorage) GetPosition(ctx context.Context, eventID string, userID int64) (int64, error) {
+	entry, err := s.Get(ctx, eventID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var ahead int64
+	err = s.db.WithContext(ctx).Model(&entity.EventWaitlist{}).
+		Where("event_id = ? AND created_at < ?", eventID, entry.CreatedAt).
+		Count(&ahead).Error
+	return ahead + 1, err
+}
+
+// GetWaiting returns users who have not been offered a seat yet, in queue order
+func (s *EventWaitlistStorage) GetWaiting(ctx context.Context, eventID string, limit int) ([]entity.EventWaitlist, error) {
+	var entries []entity.EventWaitlist
+	err := s.db.WithContext(ctx).
+		Where("event_id = ? AND offer_expires_at IS NULL", eventID).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// GetExpiredOffers returns entries whose confirmation deadline has passed
+func (s *EventWaitlistStorage) GetExpiredOffers(ctx context.Context, now time.Time) ([]entity.EventWaitlist, error) {
+	var entries []entity.EventWaitlist
+	err := s.db.WithContext(ctx).
+		Where("offer_expires_at IS NOT NULL AND offer_expires_at < ?", now).
+		Find(&entries).Error
+	return entries, err
+}
+
+// GetEventIDs returns ids of upcoming events that have a non-empty queue
+func (s *EventWaitlistStorage) GetEventIDs(ctx context.Context) ([]string, error) {
+	var eventIDs []string
+	err := s.db.WithContext(ctx).
+		Model(&entity.EventWaitlist{}).
+		Joins("JOIN events ON events.id = event_waitlists.event_id").
+		Where("events.deleted_at IS NULL AND events.start_time > ?", time.Now()).
+		Distinct().
+		Pluck("event_waitlists.event_id", &eventIDs).Error
+	return eventIDs, err
+}
+
+// Promote registers the user on the event and removes the entry from the queue in a single transaction
+func (s *EventWaitlistStorage) Promote(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error) {
+	participant := &entity.EventParticipant{
+		EventID: eventID,
+		UserID:  userID,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&entity.EventWaitlist{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&participant).Error
+	})
+	return participant, err
+}
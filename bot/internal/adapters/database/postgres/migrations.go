@@ -6,10 +6,22 @@ import "github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 var Migrations = []interface{}{
 	&entity.User{},
 	&entity.Club{},
+	&entity.Venue{},
 	&entity.ClubOwner{},
 	&entity.IgnoreMailing{},
+	&entity.ClubFollower{},
 	&entity.Event{},
+	&entity.EventSeries{},
+	&entity.EventTemplate{},
 	&entity.EventParticipant{},
+	&entity.EventGuest{},
+	&entity.EventApplication{},
+	&entity.EventAnswers{},
+	&entity.CheckInAttempt{},
+	&entity.AttendanceChange{},
+	&entity.EventScanner{},
+	&entity.EventWaitlist{},
 	&entity.EventNotification{},
+	&entity.ScheduledMailing{},
 	&entity.StudentData{},
 }
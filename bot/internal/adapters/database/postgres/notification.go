@@ -21,13 +21,23 @@ func (s *NotificationStorage) Create(ctx context.Context, notification *entity.E
 }
 
 // GetUnnotifiedUsers returns a list of users who have not been notified about an event for a specific notification type
-func (s *NotificationStorage) GetUnnotifiedUsers(ctx context.Context, eventID string, notificationType entity.NotificationType) ([]entity.EventParticipant, error) {
-	var participants []entity.EventParticipant
+//
+// Users who muted the reminders about the event are not returned
+func (s *NotificationStorage) GetUnnotifiedUsers(ctx context.Context, eventID string, notificationType entity.NotificationType) ([]entity.User, error) {
+	var users []entity.User
 
 	err := s.db.WithContext(ctx).
+		Table("event_participants").
+		Select("users.*").
+		Joins("INNER JOIN users ON users.id = event_participants.user_id").
 		Joins("LEFT JOIN event_notifications ON event_notifications.user_id = event_participants.user_id AND event_notifications.event_id = event_participants.event_id AND event_notifications.type = ?", notificationType).
-		Where("event_participants.event_id = ? AND event_notifications.id IS NULL", eventID).
-		Find(&participants).Error
+		Where("event_participants.event_id = ? AND event_notifications.id IS NULL AND NOT event_participants.reminders_muted", eventID).
+		Find(&users).Error
 
-	return participants, err
+	return users, err
+}
+
+// DeleteByEventID removes the sent notifications of the event, so the reminders are sent again
+func (s *NotificationStorage) DeleteByEventID(ctx context.Context, eventID string) error {
+	return s.db.WithContext(ctx).Where("event_id = ?", eventID).Delete(&entity.EventNotification{}).Error
 }
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type ScheduledMailingStorage struct {
+	db *gorm.DB
+}
+
+func NewScheduledMailingStorage(db *gorm.DB) *ScheduledMailingStorage {
+	return &ScheduledMailingStorage{
+		db: db,
+	}
+}
+
+func (s *ScheduledMailingStorage) Create(ctx context.Context, mailing *entity.ScheduledMailing) (*entity.ScheduledMailing, error) {
+	err := s.db.WithContext(ctx).Omit("Club").Create(&mailing).Error
+	return mailing, err
+}
+
+func (s *ScheduledMailingStorage) Get(ctx context.Context, id string) (*entity.ScheduledMailing, error) {
+	var mailing entity.ScheduledMailing
+	err := s.db.WithContext(ctx).Preload("Club").Where("id = ?", id).First(&mailing).Error
+	return &mailing, err
+}
+
+// GetByClubID returns the pending mailings of the club ordered by the send time
+func (s *ScheduledMailingStorage) GetByClubID(ctx context.Context, clubID string) ([]entity.ScheduledMailing, error) {
+	var mailings []entity.ScheduledMailing
+	err := s.db.WithContext(ctx).
+		Where("club_id = ? AND status = ?", clubID, entity.ScheduledMailingPending).
+		Order("send_at").
+		Find(&mailings).Error
+	return mailings, err
+}
+
+// GetDue returns the pending mailings that should be sent at the given time
+func (s *ScheduledMailingStorage) GetDue(ctx context.Context, now time.Time) ([]entity.ScheduledMailing, error) {
+	var mailings []entity.ScheduledMailing
+	err := s.db.WithContext(ctx).
+		Preload("Club").
+		Where("send_at <= ? AND status = ?", now, entity.ScheduledMailingPending).
+		Order("send_at").
+		Find(&mailings).Error
+	return mailings, err
+}
+
+// Update updates the pending mailing, unlike Save it doesn't create the mailing again if it has already been sent,
+// gorm.ErrRecordNotFound is returned instead, as well as if the mailing is being sent
+func (s *ScheduledMailingStorage) Update(ctx context.Context, mailing *entity.ScheduledMailing) (*entity.ScheduledMailing, error) {
+	result := s.db.WithContext(ctx).
+		Model(mailing).
+		Where("status = ?", entity.ScheduledMailingPending).
+		Select("*").
+		Omit("Club", "CreatedAt", "Status").
+		Updates(mailing)
+	if result.Error != nil {
+		return mailing, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return mailing, gorm.ErrRecordNotFound
+	}
+	return mailing, nil
+}
+
+// Delete deletes the pending mailing, it returns gorm.ErrRecordNotFound if the mailing has already been deleted
+// or is being sent
+func (s *ScheduledMailingStorage) Delete(ctx context.Context, id string) error {
+	result := s.db.WithContext(ctx).
+		Where("id = ? AND status = ?", id, entity.ScheduledMailingPending).
+		Delete(&entity.ScheduledMailing{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SetStatus moves the mailing from one status to another,
+// it returns gorm.ErrRecordNotFound if the mailing is not in the from status, so only one caller can claim the mailing
+func (s *ScheduledMailingStorage) SetStatus(ctx context.Context, id string, from, to entity.ScheduledMailingStatus) error {
+	result := s.db.WithContext(ctx).
+		Model(&entity.ScheduledMailing{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteDispatched deletes the mailing claimed by the scheduler after it has been queued
+func (s *ScheduledMailingStorage) DeleteDispatched(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).
+		Where("id = ? AND status = ?", id, entity.ScheduledMailingDispatching).
+		Delete(&entity.ScheduledMailing{}).Error
+}
@@ -37,6 +37,13 @@ func (s *UserStorage) GetByQRCodeID(ctx context.Context, qrCodeID string) (*enti
 	return &user, err
 }
 
+// GetByCalendarToken is a function that gets a user from the database by calendar feed token.
+func (s *UserStorage) GetByCalendarToken(ctx context.Context, token string) (*entity.User, error) {
+	var user entity.User
+	err := s.db.WithContext(ctx).Where("calendar_token = ?", token).First(&user).Error
+	return &user, err
+}
+
 func (s *UserStorage) GetMany(ctx context.Context, ids []int64) ([]entity.User, error) {
 	var users []entity.User
 	err := s.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error
@@ -56,6 +63,7 @@ func (s *UserStorage) GetEventUsers(ctx context.Context, eventID string) ([]dto.
 		entity.User
 		IsUserQr  bool
 		IsEventQr bool
+		IsManual  bool
 	}
 
 	var users []userWithQR
@@ -63,7 +71,7 @@ func (s *UserStorage) GetEventUsers(ctx context.Context, eventID string) ([]dto.
 	err := s.db.
 		WithContext(ctx).
 		Table("event_participants").
-		Select("users.*, event_participants.is_user_qr, event_participants.is_event_qr").
+		Select("users.*, event_participants.is_user_qr, event_participants.is_event_qr, event_participants.is_manual").
 		Joins("inner join users on event_participants.user_id = users.id").
 		Where("event_participants.event_id = ?", eventID).
 		Preload("IgnoreMailing").
@@ -74,7 +82,7 @@ func (s *UserStorage) GetEventUsers(ctx context.Context, eventID string) ([]dto.
 
 	result := make([]dto.EventUser, len(users))
 	for i, user := range users {
-		result[i] = dto.NewEventUserFromEntity(user.User, user.IsUserQr || user.IsEventQr)
+		result[i] = dto.NewEventUserFromEntity(user.User, user.IsUserQr || user.IsEventQr || user.IsManual)
 	}
 
 	return result, nil
@@ -111,20 +119,92 @@ func (s *UserStorage) GetUsersByClubID(ctx context.Context, clubID string) ([]en
 	return users, err
 }
 
-// GetManyUsersByEventIDs is a function that get users that registered on event by event ids without duplicates.
-func (s *UserStorage) GetManyUsersByEventIDs(ctx context.Context, eventIDs []string) ([]entity.User, error) {
+// GetVisitedUsersByClubID is a function that returns all users that visited club event at least once
+func (s *UserStorage) GetVisitedUsersByClubID(ctx context.Context, clubID string) ([]entity.User, error) {
 	var users []entity.User
 
 	err := s.db.
 		WithContext(ctx).
 		Table("event_participants").
-		Select("DISTINCT ON (users.id) users.*").
+		Select("DISTINCT users.*").
 		Joins("inner join users on event_participants.user_id = users.id").
-		Where("event_participants.event_id IN ?", eventIDs).
+		Joins("inner join events on event_participants.event_id = events.id").
+		Where("events.club_id = ? AND (event_participants.is_user_qr OR event_participants.is_event_qr OR event_participants.is_manual)", clubID).
+		Preload("IgnoreMailing").
+		Find(&users).Error
+	return users, err
+}
+
+// GetWaitingUsersByEventID returns the users on the waitlist of the event and the users with pending applications to it
+func (s *UserStorage) GetWaitingUsersByEventID(ctx context.Context, eventID string) ([]entity.User, error) {
+	var users []entity.User
+
+	err := s.db.
+		WithContext(ctx).
+		Where("id IN (?) OR id IN (?)",
+			s.db.Table("event_waitlists").Select("user_id").Where("event_id = ?", eventID),
+			s.db.Table("event_applications").Select("user_id").Where("event_id = ?", eventID),
+		).
+		Preload("IgnoreMailing").
 		Find(&users).Error
 	return users, err
 }
 
+// GetPassGuests returns the participants of the events and the guests they brought with the events and clubs,
+// ordered by the event start and the name. The guests are always external users
+func (s *UserStorage) GetPassGuests(ctx context.Context, eventIDs []string) ([]dto.PassGuest, error) {
+	var guests []dto.PassGuest
+
+	participants := s.db.
+		Table("event_participants").
+		Select(`'' AS guest_id, users.id AS user_id, users.role, users.fio, users.last_name, users.first_name, users.middle_name,
+			users.phone, events.id AS event_id, events.name AS event_name, events.start_time, clubs.name AS club_name`).
+		Joins("inner join users on event_participants.user_id = users.id").
+		Joins("inner join events on event_participants.event_id = events.id").
+		Joins("inner join clubs on events.club_id = clubs.id").
+		Where("event_participants.event_id IN ?", eventIDs)
+
+	eventGuests := s.db.
+		Table("event_guests").
+		Select(`event_guests.id::text AS guest_id, event_guests.user_id, CAST(? AS text) AS role, event_guests.fio,
+			event_guests.last_name, event_guests.first_name, event_guests.middle_name, event_guests.phone,
+			events.id AS event_id, events.name AS event_name, events.start_time, clubs.name AS club_name`, entity.ExternalUser).
+		Joins("inner join events on event_guests.event_id = events.id").
+		Joins("inner join clubs on events.club_id = clubs.id").
+		Where("event_guests.event_id IN ?", eventIDs)
+
+	err := s.db.
+		WithContext(ctx).
+		Raw("SELECT * FROM (? UNION ALL ?) AS pass_guests ORDER BY start_time, fio", participants, eventGuests).
+		Scan(&guests).Error
+	return guests, err
+}
+
+// FillNameParts fills the name parts of the users registered before they were stored separately
+func (s *UserStorage) FillNameParts(ctx context.Context) error {
+	var users []entity.User
+	err := s.db.WithContext(ctx).Where("last_name = '' AND fio <> ''").Find(&users).Error
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		lastName, firstName, middleName, ok := entity.SplitFIO(user.FIO)
+		if !ok {
+			continue
+		}
+		err = s.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"last_name":   lastName,
+			"first_name":  firstName,
+			"middle_name": middleName,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Update is a function that updates a user in the database.
 func (s *UserStorage) Update(ctx context.Context, user *entity.User) (*entity.User, error) {
 	err := s.db.WithContext(ctx).Save(user).Error
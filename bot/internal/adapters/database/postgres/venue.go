@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VenueStorage struct {
+	db *gorm.DB
+}
+
+func NewVenueStorage(db *gorm.DB) *VenueStorage {
+	return &VenueStorage{
+		db: db,
+	}
+}
+
+// Upsert creates the venue or updates the venue with the same name, the soft deleted venue is restored
+func (s *VenueStorage) Upsert(ctx context.Context, venue *entity.Venue) (*entity.Venue, error) {
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"updated_at",
+			"deleted_at",
+			"address",
+			"capacity",
+			"pass_required",
+			"pass_recipients",
+			"pass_lead_days",
+			"pass_deadlines",
+			"conflict_mode",
+			"buffer",
+		}),
+	}).Create(venue).Error
+	return venue, err
+}
+
+func (s *VenueStorage) Get(ctx context.Context, id string) (*entity.Venue, error) {
+	var venue entity.Venue
+	err := s.db.WithContext(ctx).Unscoped().Where("id = ?", id).First(&venue).Error
+	return &venue, err
+}
+
+// GetAll returns the venues ordered by name
+func (s *VenueStorage) GetAll(ctx context.Context) ([]entity.Venue, error) {
+	var venues []entity.Venue
+	err := s.db.WithContext(ctx).Order("name").Find(&venues).Error
+	return venues, err
+}
+
+// GetPassRequired returns the venues that need pass lists
+func (s *VenueStorage) GetPassRequired(ctx context.Context) ([]entity.Venue, error) {
+	var venues []entity.Venue
+	err := s.db.WithContext(ctx).Where("pass_required").Order("name").Find(&venues).Error
+	return venues, err
+}
+
+// DeleteExcept soft deletes the venues whose names are not in the list
+func (s *VenueStorage) DeleteExcept(ctx context.Context, names []string) error {
+	query := s.db.WithContext(ctx)
+	if len(names) > 0 {
+		query = query.Where("name NOT IN ?", names)
+	} else {
+		query = query.Where("1 = 1")
+	}
+	return query.Delete(&entity.Venue{}).Error
+}
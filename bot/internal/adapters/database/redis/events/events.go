@@ -39,6 +39,19 @@ func (s *Storage) Set(userID int64, event entity.Event, expiration time.Duration
 	s.redis.Set(context.Background(), fmt.Sprintf("%d", userID), eventBytes, expiration)
 }
 
+// GetRecurrence returns the recurrence interval (in weeks) of the event draft, 0 means the event is not recurring
+func (s *Storage) GetRecurrence(userID int64) int {
+	interval, err := s.redis.Get(context.Background(), fmt.Sprintf("%d:recurrence", userID)).Int()
+	if err != nil {
+		return 0
+	}
+	return interval
+}
+
+func (s *Storage) SetRecurrence(userID int64, interval int, expiration time.Duration) {
+	s.redis.Set(context.Background(), fmt.Sprintf("%d:recurrence", userID), interval, expiration)
+}
+
 func (s *Storage) Clear(userID int64) {
-	s.redis.Del(context.Background(), fmt.Sprintf("%d", userID))
+	s.redis.Del(context.Background(), fmt.Sprintf("%d", userID), fmt.Sprintf("%d:recurrence", userID))
 }
@@ -0,0 +1,180 @@
+package mailings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// queueKey is the list of delivery jobs of all mailings
+	queueKey = "queue"
+	// processingKey is the list of jobs taken from the queue and not acknowledged yet,
+	// they are returned to the queue on start, so the jobs aren't lost if the bot stops while processing them
+	processingKey = "queue:processing"
+)
+
+// Storage is a queue of mailings
+//
+// Every mailing is stored at "mailing:<id>" with the number of unprocessed recipients at "mailing:<id>:pending",
+// the delivery status of every recipient at "mailing:<id>:statuses" and the counters by status at "mailing:<id>:report"
+type Storage struct {
+	redis *redis.Client
+}
+
+func NewStorage(client *redis.Client) *Storage {
+	return &Storage{
+		redis: client,
+	}
+}
+
+// Push stores the mailing and adds a delivery job for every recipient to the queue,
+// the mailing data is kept for the expiration
+func (s *Storage) Push(mailing dto.Mailing, recipients []dto.MailingRecipient, expiration time.Duration) error {
+	mailingBytes, err := json.Marshal(mailing)
+	if err != nil {
+		return err
+	}
+
+	jobs := make([]interface{}, 0, len(recipients))
+	for _, recipient := range recipients {
+		recipient.MailingID = mailing.ID
+		jobBytes, errMarshal := json.Marshal(recipient)
+		if errMarshal != nil {
+			return errMarshal
+		}
+		jobs = append(jobs, jobBytes)
+	}
+
+	ctx := context.Background()
+	_, err = s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, mailingKey(mailing.ID), mailingBytes, expiration)
+		pipe.Set(ctx, mailingKey(mailing.ID)+":pending", len(recipients), expiration)
+		pipe.HSet(ctx, mailingKey(mailing.ID)+":report", "total", len(recipients))
+		pipe.Expire(ctx, mailingKey(mailing.ID)+":report", expiration)
+		if len(jobs) > 0 {
+			pipe.RPush(ctx, queueKey, jobs...)
+		}
+		return nil
+	})
+	return err
+}
+
+// Pop moves the next delivery job from the queue to the processing list, waiting for it up to the timeout,
+// the job stays there until it is acknowledged by Ack.
+// Returns errorz.ErrQueueEmpty if there are no jobs
+func (s *Storage) Pop(timeout time.Duration) (dto.MailingRecipient, error) {
+	result, err := s.redis.BLMove(context.Background(), queueKey, processingKey, "LEFT", "RIGHT", timeout).Result()
+	if errors.Is(err, redis.Nil) {
+		return dto.MailingRecipient{}, errorz.ErrQueueEmpty
+	}
+	if err != nil {
+		return dto.MailingRecipient{}, err
+	}
+
+	var recipient dto.MailingRecipient
+	if err = json.Unmarshal([]byte(result), &recipient); err != nil {
+		// The job can't be processed anyway, so it isn't kept in the processing list
+		s.redis.LRem(context.Background(), processingKey, 1, result)
+		return dto.MailingRecipient{}, err
+	}
+	return recipient, nil
+}
+
+// Ack removes the processed job from the processing list
+func (s *Storage) Ack(recipient dto.MailingRecipient) error {
+	// The job is encoded the same way as it was pushed, so it matches the stored one
+	jobBytes, err := json.Marshal(recipient)
+	if err != nil {
+		return err
+	}
+	return s.redis.LRem(context.Background(), processingKey, 1, jobBytes).Err()
+}
+
+// Restore returns the jobs left in the processing list to the head of the queue,
+// it should be called before the jobs are popped, as all the jobs in the processing list are considered abandoned
+func (s *Storage) Restore() (int, error) {
+	ctx := context.Background()
+	var restored int
+	for {
+		err := s.redis.LMove(ctx, processingKey, queueKey, "RIGHT", "LEFT").Err()
+		if errors.Is(err, redis.Nil) {
+			return restored, nil
+		}
+		if err != nil {
+			return restored, err
+		}
+		restored++
+	}
+}
+
+func (s *Storage) Get(mailingID string) (dto.Mailing, error) {
+	mailingBytes, err := s.redis.Get(context.Background(), mailingKey(mailingID)).Bytes()
+	if err != nil {
+		return dto.Mailing{}, err
+	}
+
+	var mailing dto.Mailing
+	if err = json.Unmarshal(mailingBytes, &mailing); err != nil {
+		return dto.Mailing{}, err
+	}
+	return mailing, nil
+}
+
+// Update replaces the stored mailing keeping its expiration
+func (s *Storage) Update(mailing dto.Mailing) error {
+	mailingBytes, err := json.Marshal(mailing)
+	if err != nil {
+		return err
+	}
+	return s.redis.Set(context.Background(), mailingKey(mailing.ID), mailingBytes, redis.KeepTTL).Err()
+}
+
+// SetStatus records the delivery status for the recipient and returns the number of recipients left,
+// the statuses are kept for the expiration
+func (s *Storage) SetStatus(mailingID string, userID int64, status dto.MailingStatus, expiration time.Duration) (int64, error) {
+	ctx := context.Background()
+	var pending *redis.IntCmd
+	_, err := s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, mailingKey(mailingID)+":statuses", strconv.FormatInt(userID, 10), string(status))
+		pipe.Expire(ctx, mailingKey(mailingID)+":statuses", expiration)
+		pipe.HIncrBy(ctx, mailingKey(mailingID)+":report", string(status), 1)
+		pipe.Expire(ctx, mailingKey(mailingID)+":report", expiration)
+		pending = pipe.Decr(ctx, mailingKey(mailingID)+":pending")
+		pipe.Expire(ctx, mailingKey(mailingID)+":pending", expiration)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return pending.Val(), nil
+}
+
+func (s *Storage) GetReport(mailingID string) (dto.MailingReport, error) {
+	result, err := s.redis.HGetAll(context.Background(), mailingKey(mailingID)+":report").Result()
+	if err != nil {
+		return dto.MailingReport{}, err
+	}
+
+	count := func(field string) int {
+		value, _ := strconv.Atoi(result[field])
+		return value
+	}
+	return dto.MailingReport{
+		Total:   count("total"),
+		Sent:    count(string(dto.MailingSent)),
+		Blocked: count(string(dto.MailingBlocked)),
+		Failed:  count(string(dto.MailingFailed)),
+	}, nil
+}
+
+func mailingKey(mailingID string) string {
+	return fmt.Sprintf("mailing:%s", mailingID)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis/codes"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis/emails"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis/events"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis/mailings"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/adapters/database/redis/states"
 	"github.com/redis/go-redis/v9"
 )
@@ -17,6 +18,7 @@ type Client struct {
 	Emails    *emails.Storage
 	Events    *events.Storage
 	Callbacks *callbacks.Storage
+	Mailings  *mailings.Storage
 }
 
 type Options struct {
@@ -69,11 +71,21 @@ func New(opts Options) (*Client, error) {
 		return nil, fmt.Errorf("failed to ping callbacks storage: %w", err)
 	}
 
+	mailingsRedis := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", opts.Host, opts.Port),
+		Password: opts.Password,
+		DB:       5,
+	})
+	if err := mailingsRedis.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to ping mailings storage: %w", err)
+	}
+
 	return &Client{
 		States:    states.NewStorage(stateRedis),
 		Codes:     codes.NewStorage(codeRedis),
 		Emails:    emails.NewStorage(emailRedis),
 		Events:    events.NewStorage(eventsRedis),
 		Callbacks: callbacks.NewStorage(callbacksRedis),
+		Mailings:  mailings.NewStorage(mailingsRedis),
 	}, nil
 }
@@ -7,4 +7,18 @@ var (
 	ErrInvalidState        = errors.New("invalid state")
 	ErrInvalidCode         = errors.New("invalid code")
 	ErrForbidden           = errors.New("forbidden")
+	ErrOfferExpired        = errors.New("offer expired")
+	ErrCancellationClosed  = errors.New("cancellation closed")
+	ErrQueueEmpty          = errors.New("queue is empty")
+	ErrGuestsLimit         = errors.New("guests limit reached")
+	ErrNoSeats             = errors.New("no free seats")
+	ErrQRExpired           = errors.New("qr code expired")
+	ErrCheckInNotOpened    = errors.New("check-in is not opened yet")
+	ErrCheckInClosed       = errors.New("check-in is closed")
+	ErrInviteExpired       = errors.New("invite expired")
+	ErrTemplatesLimit      = errors.New("templates limit reached")
+	ErrRegistrationEnded   = errors.New("registration ended")
+	ErrRoleNotAllowed      = errors.New("role not allowed")
+	ErrPhoneRequired       = errors.New("phone required")
+	ErrEventUnavailable    = errors.New("event is cancelled, not published or already started")
 )
@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// Attendance is a registration of the user on the event with the visit mark
+type Attendance struct {
+	EventID string
+	UserID  int64
+	Role    entity.Role
+	Visited bool
+}
+
+// EventAnalytics is the attendance of a single event
+type EventAnalytics struct {
+	EventID    string
+	Name       string
+	StartTime  time.Time
+	Registered int
+	Visited    int
+}
+
+// Conversion returns the registration-to-attendance conversion in percent
+func (e EventAnalytics) Conversion() float64 {
+	return percent(e.Visited, e.Registered)
+}
+
+// RoleAnalytics is the attendance of users with the same role
+type RoleAnalytics struct {
+	Role       entity.Role
+	Registered int
+	NoShows    int
+}
+
+// NoShowRate returns the share of registrations without a visit in percent
+func (r RoleAnalytics) NoShowRate() float64 {
+	return percent(r.NoShows, r.Registered)
+}
+
+// ClubAnalytics is the attendance of the last events of the club
+type ClubAnalytics struct {
+	// Events are sorted from the oldest to the newest
+	Events     []EventAnalytics
+	Roles      []RoleAnalytics
+	Registered int
+	Visited    int
+	// Attendees is the number of unique users who visited at least one event
+	Attendees int
+	// RepeatAttendees is the number of unique users who visited more than one event
+	RepeatAttendees int
+}
+
+// Conversion returns the overall registration-to-attendance conversion in percent
+func (a ClubAnalytics) Conversion() float64 {
+	return percent(a.Visited, a.Registered)
+}
+
+// RepeatRate returns the share of attendees who visited more than one event in percent
+func (a ClubAnalytics) RepeatRate() float64 {
+	return percent(a.RepeatAttendees, a.Attendees)
+}
+
+// Trend returns the difference (in percentage points) between the average conversion
+// of the newer half of the events and the older half
+func (a ClubAnalytics) Trend() float64 {
+	if len(a.Events) < 2 {
+		return 0
+	}
+
+	half := len(a.Events) / 2
+	older, newer := a.Events[:half], a.Events[len(a.Events)-half:]
+	return averageConversion(newer) - averageConversion(older)
+}
+
+func averageConversion(events []EventAnalytics) float64 {
+	var sum float64
+	for _, event := range events {
+		sum += event.Conversion()
+	}
+	return sum / float64(len(events))
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
@@ -0,0 +1,58 @@
+package dto
+
+import "testing"
+
+func TestClubAnalyticsTrend(t *testing.T) {
+	event := func(registered, visited int) EventAnalytics {
+		return EventAnalytics{Registered: registered, Visited: visited}
+	}
+
+	tests := []struct {
+		name   string
+		events []EventAnalytics
+		want   float64
+	}{
+		{name: "no events", want: 0},
+		{name: "single event", events: []EventAnalytics{event(10, 5)}, want: 0},
+		{name: "growing", events: []EventAnalytics{event(10, 5), event(10, 8)}, want: 30},
+		{name: "falling", events: []EventAnalytics{event(10, 8), event(10, 6), event(10, 4), event(10, 2)}, want: -40},
+		{name: "middle event is skipped", events: []EventAnalytics{event(10, 5), event(10, 0), event(10, 5)}, want: 0},
+		{name: "events without registrations", events: []EventAnalytics{event(0, 0), event(4, 2)}, want: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (ClubAnalytics{Events: tt.events}).Trend(); got != tt.want {
+				t.Errorf("Trend() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClubAnalyticsRates(t *testing.T) {
+	tests := []struct {
+		name           string
+		analytics      ClubAnalytics
+		wantConversion float64
+		wantRepeatRate float64
+	}{
+		{name: "empty", analytics: ClubAnalytics{}},
+		{
+			name:           "some visits",
+			analytics:      ClubAnalytics{Registered: 40, Visited: 30, Attendees: 20, RepeatAttendees: 5},
+			wantConversion: 75,
+			wantRepeatRate: 25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.analytics.Conversion(); got != tt.wantConversion {
+				t.Errorf("Conversion() = %v, want %v", got, tt.wantConversion)
+			}
+			if got := tt.analytics.RepeatRate(); got != tt.wantRepeatRate {
+				t.Errorf("RepeatRate() = %v, want %v", got, tt.wantRepeatRate)
+			}
+		})
+	}
+}
@@ -11,4 +11,6 @@ type ClubOwner struct {
 	Role     entity.Role
 	IsBanned bool
 	Warnings bool
+	// Localisation is the language of the owner
+	Localisation string
 }
@@ -0,0 +1,47 @@
+package dto
+
+// MailingStatus is the delivery status of the mailing for a recipient
+type MailingStatus string
+
+const (
+	MailingSent    MailingStatus = "sent"
+	MailingBlocked MailingStatus = "blocked"
+	MailingFailed  MailingStatus = "failed"
+)
+
+// Mailing is a message queued for delivery to many users
+type Mailing struct {
+	ID string
+	// ClubID is set for club mailings, recipients get the button to turn off mailings from the club
+	ClubID string
+	// EventID is set for the event cards sent to the club followers,
+	// recipients get the button to register on the event and the button to unfollow the club
+	EventID string
+	// AuthorID receives the delivery report when all recipients are processed, 0 means no report
+	AuthorID     int64
+	AuthorLocale string
+	// Texts are the message texts by locale
+	Texts map[string]string
+	// MediaType and FileID describe the attached media, see utils.GetMessageMedia
+	MediaType string
+	FileID    string
+	// File is the document uploaded with the first delivery when there is no FileID yet,
+	// the rest recipients get it by the FileID of the upload
+	File     []byte
+	FileName string
+}
+
+// MailingRecipient is a single delivery job of the mailing queue
+type MailingRecipient struct {
+	MailingID string
+	UserID    int64
+	Locale    string
+}
+
+// MailingReport is the number of recipients by delivery status
+type MailingReport struct {
+	Total   int
+	Sent    int
+	Blocked int
+	Failed  int
+}
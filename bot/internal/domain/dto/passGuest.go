@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// PassGuest is a participant of an event that is put into the pass list, one per user and event.
+// The guests brought by the participants have GuestID set and UserID of the participant who brought them
+type PassGuest struct {
+	GuestID    string
+	UserID     int64
+	Role       entity.Role
+	FIO        string
+	LastName   string
+	FirstName  string
+	MiddleName string
+	Phone      string
+
+	EventID   string
+	EventName string
+	StartTime time.Time
+	ClubName  string
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// PublicClub is a club with its upcoming events exposed by the public http api
+type PublicClub struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Events      []PublicEvent `json:"events"`
+}
+
+// PublicEvent is an event exposed by the public http api
+type PublicEvent struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	Location        string     `json:"location"`
+	StartTime       time.Time  `json:"start_time"`
+	EndTime         *time.Time `json:"end_time,omitempty"`
+	RegistrationEnd time.Time  `json:"registration_end"`
+	MaxParticipants int        `json:"max_participants,omitempty"`
+	Link            string     `json:"link"`
+	// Cancelled events are kept in the list, so the consumers can remove them
+	Cancelled    bool   `json:"cancelled,omitempty"`
+	CancelReason string `json:"cancel_reason,omitempty"`
+}
+
+func NewPublicClubFromEntity(club entity.Club, events []entity.Event, botName string) PublicClub {
+	publicEvents := make([]PublicEvent, len(events))
+	for i, event := range events {
+		publicEvents[i] = NewPublicEventFromEntity(event, botName)
+	}
+
+	return PublicClub{
+		ID:          club.ID,
+		Name:        club.Name,
+		Description: club.Description,
+		Events:      publicEvents,
+	}
+}
+
+func NewPublicEventFromEntity(event entity.Event, botName string) PublicEvent {
+	publicEvent := PublicEvent{
+		ID:              event.ID,
+		Name:            event.Name,
+		Description:     event.Description,
+		Location:        event.Location,
+		StartTime:       event.StartTime,
+		RegistrationEnd: event.RegistrationEnd,
+		MaxParticipants: event.MaxParticipants,
+		Link:            event.Link(botName),
+		Cancelled:       event.IsCancelled(),
+		CancelReason:    event.CancelReason,
+	}
+	if !event.EndTime.IsZero() {
+		publicEvent.EndTime = &event.EndTime
+	}
+	return publicEvent
+}
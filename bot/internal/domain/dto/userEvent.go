@@ -9,6 +9,8 @@ import (
 
 type UserEvent struct {
 	ID                    string
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
 	ClubID                string
 	Name                  string
 	Description           string
@@ -20,12 +22,16 @@ type UserEvent struct {
 	MaxParticipants       int
 	ExpectedParticipants  int
 	AllowedRoles          pq.StringArray
+	Sequence              int
+	CancelledAt           *time.Time
 	IsVisited             bool
 }
 
 func NewUserEventFromEntity(event entity.Event, isVisited bool) UserEvent {
 	return UserEvent{
 		ID:                    event.ID,
+		CreatedAt:             event.CreatedAt,
+		UpdatedAt:             event.UpdatedAt,
 		ClubID:                event.ClubID,
 		Name:                  event.Name,
 		Description:           event.Description,
@@ -37,6 +43,8 @@ func NewUserEventFromEntity(event entity.Event, isVisited bool) UserEvent {
 		MaxParticipants:       event.MaxParticipants,
 		ExpectedParticipants:  event.ExpectedParticipants,
 		AllowedRoles:          event.AllowedRoles,
+		Sequence:              event.Sequence,
+		CancelledAt:           event.CancelledAt,
 		IsVisited:             isVisited,
 	}
 }
@@ -44,3 +52,7 @@ func NewUserEventFromEntity(event entity.Event, isVisited bool) UserEvent {
 func (e *UserEvent) IsOver(additionalTime time.Duration) bool {
 	return e.StartTime.Before(time.Now().In(location.Location()).Add(-additionalTime))
 }
+
+func (e *UserEvent) IsCancelled() bool {
+	return e.CancelledAt != nil
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+// Venue is a venue described in the config (settings.venues)
+type Venue struct {
+	Name         string   `mapstructure:"name"`
+	Address      string   `mapstructure:"address"`
+	Capacity     int      `mapstructure:"capacity"`
+	PassRequired bool     `mapstructure:"pass-required"`
+	PassEmails   []string `mapstructure:"pass-emails"`
+	PassChatID   int64    `mapstructure:"pass-chat-id"`
+	// PassRecipients get the pass lists in their own formats, PassEmails and PassChatID get them in the default one
+	PassRecipients []PassRecipient `mapstructure:"pass-recipients"`
+	// PassLeadDays is how many days before the event the pass list is sent at the latest, 1 if not set
+	PassLeadDays *int `mapstructure:"pass-lead-days"`
+	// PassDeadlines are the times (HH:MM) when the pass office takes the lists by the weekday name (monday, tuesday...),
+	// the default deadlines are used if not set
+	PassDeadlines map[string]string `mapstructure:"pass-deadlines"`
+	// ConflictMode is warn or block, warn is used if not set
+	ConflictMode string `mapstructure:"conflict-mode"`
+	// Buffer is the time between the events for setup and teardown, e.g. 30m
+	Buffer time.Duration `mapstructure:"buffer"`
+}
+
+// PassRecipient is a recipient of the pass lists with its own format, either Email or ChatID is set
+type PassRecipient struct {
+	Email  string `mapstructure:"email"`
+	ChatID int64  `mapstructure:"chat-id"`
+	// Format is xlsx, csv or pdf, xlsx is used if not set
+	Format string `mapstructure:"format"`
+	// Columns are the columns of the list (last-name, first-name, middle-name, fio, date, time, event, club),
+	// the last, first and middle names are used if not set
+	Columns []string `mapstructure:"columns"`
+}
@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// CheckInMethod is the way the participant checks in on the event
+type CheckInMethod string
+
+const (
+	CheckInEventQR CheckInMethod = "event_qr"
+	CheckInUserQR  CheckInMethod = "user_qr"
+)
+
+// CheckInRejectReason is why the check-in has been rejected
+type CheckInRejectReason string
+
+const (
+	CheckInEarly CheckInRejectReason = "early"
+	CheckInLate  CheckInRejectReason = "late"
+)
+
+// CheckInAttempt is the check-in attempt outside the check-in window of the event,
+// they are kept so the club owners can spot the abuse of the QR codes
+type CheckInAttempt struct {
+	ID      string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	EventID string `gorm:"not null;type:uuid;index"`
+	UserID  int64  `gorm:"not null"`
+	// ScannedBy is the club owner who has scanned the user QR code, 0 for the event QR code
+	ScannedBy int64
+	Method    CheckInMethod       `gorm:"not null"`
+	Reason    CheckInRejectReason `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// AttendanceChange is the visit mark changed by hand by the club owner,
+// they are kept so the statistics of the visits can be audited
+type AttendanceChange struct {
+	ID        string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	EventID   string `gorm:"not null;type:uuid;index"`
+	UserID    int64  `gorm:"not null"`
+	ChangedBy int64  `gorm:"not null"`
+	// Visited is the visit mark after the change
+	Visited   bool `gorm:"not null"`
+	CreatedAt time.Time
+}
@@ -18,4 +18,13 @@ type Club struct {
 	AllowedRoles pq.StringArray `gorm:"type:text[]"`
 	// QrAllowed - true if group can create qr code that can be scanned by users for event registration
 	QrAllowed bool
+	// Reminders - list of NotificationType reminders sent to participants of the club events
+	Reminders pq.StringArray `gorm:"type:text[];default:'{day,hour}'"`
+}
+
+// ClubFollower is a user who follows the club and receives the cards of its new events
+type ClubFollower struct {
+	UserID    int64  `gorm:"primaryKey"`
+	ClubID    string `gorm:"primaryKey;type:uuid;index"`
+	CreatedAt time.Time
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
 	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"strings"
 	"time"
 )
 
@@ -22,11 +23,44 @@ type Event struct {
 	StartTime             time.Time `gorm:"not null"`
 	EndTime               time.Time
 	RegistrationEnd       time.Time `gorm:"not null"`
+	CancellationEnd       time.Time
 	MaxParticipants       int
 	ExpectedParticipants  int
 	QRCodeID              string
 	QRFileID              string
 	AllowedRoles          pq.StringArray `gorm:"type:text[]"`
+	// Reminders overrides the club reminders for the event, nil means the club reminders are used
+	Reminders   pq.StringArray `gorm:"type:text[]"`
+	Sequence    int            `gorm:"not null;default:0"`
+	SeriesID    *string        `gorm:"type:uuid;uniqueIndex:idx_events_series_occurrence"`
+	SeriesIndex int            `gorm:"uniqueIndex:idx_events_series_occurrence"`
+	// VenueID is the venue of the event, nil if the event is held elsewhere (online, outside the campus)
+	VenueID *string `gorm:"type:uuid;index"`
+	// MaxGuests is how many guests without Telegram each participant can bring, 0 means guests are not allowed
+	MaxGuests int `gorm:"not null;default:0"`
+	// RequiresApproval puts the registrations into the applications queue until the club owner approves them
+	RequiresApproval bool `gorm:"not null;default:false"`
+	// Questions is the questionnaire the users fill in when they register on the event
+	Questions []Question `gorm:"type:jsonb;serializer:json"`
+	// CheckInOpensBefore is how many minutes before the start the participants can check in
+	CheckInOpensBefore int `gorm:"not null;default:30"`
+	// CheckInClosesAfter is how many minutes after the end the participants can still check in
+	CheckInClosesAfter int `gorm:"not null;default:0"`
+	// ScannerInviteID is the secret of the invite link for the door scanners, empty if the link has not been created yet
+	ScannerInviteID string `gorm:"index"`
+	// CancelledAt is when the club owner cancelled the event, nil if the event is not cancelled.
+	// Cancelled events stay visible to the participants, but are not held anymore
+	CancelledAt  *time.Time
+	CancelReason string
+	// IsDraft hides the event from everyone except the club owners until it is published
+	IsDraft bool `gorm:"not null;default:false;index"`
+	// PublishAt is when the draft is published automatically, nil if the draft is published manually
+	PublishAt *time.Time
+	// Announce sends the event announcement to the club users when the draft is published
+	Announce bool `gorm:"not null;default:false"`
+	// PassListSentAt is when the guests of the event were sent to the pass office of the venue,
+	// nil until the pass deadline of the event has been handled
+	PassListSentAt *time.Time
 }
 
 // IsOver checks if the event is over, considering the additional time
@@ -39,6 +73,69 @@ func (e *Event) IsOver(additionalTime time.Duration) bool {
 	return e.StartTime.Before(time.Now().In(location.Location()).Add(-additionalTime))
 }
 
+// IsCancelled checks if the event has been cancelled by the club owner
+func (e *Event) IsCancelled() bool {
+	return e.CancelledAt != nil
+}
+
+// ChangedForParticipants checks if the event differs from its previous version in the fields the participants see
+// in the event card or in their calendars
+func (e *Event) ChangedForParticipants(previous *Event) bool {
+	return e.Name != previous.Name ||
+		e.Description != previous.Description ||
+		e.AfterRegistrationText != previous.AfterRegistrationText ||
+		e.Location != previous.Location ||
+		!equalPtr(e.VenueID, previous.VenueID) ||
+		!e.StartTime.Equal(previous.StartTime) ||
+		!e.EndTime.Equal(previous.EndTime) ||
+		!e.RegistrationEnd.Equal(previous.RegistrationEnd) ||
+		!e.CancellationEnd.Equal(previous.CancellationEnd) ||
+		e.MaxParticipants != previous.MaxParticipants ||
+		e.IsCancelled() != previous.IsCancelled() ||
+		e.CancelReason != previous.CancelReason
+}
+
+// equalPtr checks if both pointers are nil or point to the equal values
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// CheckInWindow returns when the participants can check in on the event.
+// The events without the end time are considered to last for a day, as the QR codes could be activated for a day after the start before
+func (e *Event) CheckInWindow() (time.Time, time.Time) {
+	end := e.EndTime
+	if end.Year() == 1 {
+		end = e.StartTime.Add(24 * time.Hour)
+	}
+	return e.StartTime.Add(-time.Duration(e.CheckInOpensBefore) * time.Minute),
+		end.Add(time.Duration(e.CheckInClosesAfter) * time.Minute)
+}
+
+// GetReminders returns the reminders of the event, falling back to the reminders of the club
+//
+// The club must be preloaded if the event does not override the reminders
+func (e *Event) GetReminders() pq.StringArray {
+	if e.Reminders != nil {
+		return e.Reminders
+	}
+	return e.Club.Reminders
+}
+
+// DefaultEventDuration is the assumed duration of the events without the end time
+const DefaultEventDuration = 2 * time.Hour
+
+// GetEndTime returns the end time of the event, for the events without the end time
+// it is the start time plus DefaultEventDuration
+func (e *Event) GetEndTime() time.Time {
+	if e.EndTime.Year() == 1 {
+		return e.StartTime.Add(DefaultEventDuration)
+	}
+	return e.EndTime
+}
+
 // Link generates a link to the event in the bot
 //
 // The link is in the format https://t.me/<botName>?start=event_<eventID>
@@ -47,3 +144,225 @@ func (e *Event) IsOver(additionalTime time.Duration) bool {
 func (e *Event) Link(botName string) string {
 	return fmt.Sprintf("https://t.me/%s?start=event_%s", botName, e.ID)
 }
+
+// ScannerInviteLink returns the invite link that grants the scanner role on the event
+//
+// The link is in the format https://t.me/<botName>?start=scanner_<scannerInviteID>
+func (e *Event) ScannerInviteLink(botName string) string {
+	return fmt.Sprintf("https://t.me/%s?start=scanner_%s", botName, e.ScannerInviteID)
+}
+
+// IsCancellationAllowed checks if participants can still cancel their registration
+//
+// If the cancellation deadline is not set, cancellation is allowed until the event starts
+func (e *Event) IsCancellationAllowed() bool {
+	cancellationEnd := e.CancellationEnd
+	if cancellationEnd.Year() == 1 {
+		cancellationEnd = e.StartTime
+	}
+	return time.Now().In(location.Location()).Before(cancellationEnd)
+}
+
+type SeriesFrequency string
+
+const (
+	SeriesFrequencyWeekly SeriesFrequency = "WEEKLY"
+)
+
+// EventSeries is a template of a recurring event.
+//
+// StartTime, EndTime, RegistrationEnd and CancellationEnd describe the first occurrence of the series,
+// the following occurrences are shifted by Interval weeks each. Occurrences are materialised
+// into regular events ahead of time, SeriesIndex of an event is the number of its occurrence.
+type EventSeries struct {
+	ID                    string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+	DeletedAt             gorm.DeletedAt
+	ClubID                string `gorm:"not null;type:uuid"`
+	Club                  Club
+	Name                  string `gorm:"not null"`
+	Description           string `gorm:"not null"`
+	AfterRegistrationText string
+	Location              string    `gorm:"not null"`
+	VenueID               *string   `gorm:"type:uuid"`
+	StartTime             time.Time `gorm:"not null"`
+	EndTime               time.Time
+	RegistrationEnd       time.Time `gorm:"not null"`
+	CancellationEnd       time.Time
+	MaxParticipants       int
+	ExpectedParticipants  int
+	AllowedRoles          pq.StringArray  `gorm:"type:text[]"`
+	Reminders             pq.StringArray  `gorm:"type:text[]"`
+	MaxGuests             int             `gorm:"not null;default:0"`
+	RequiresApproval      bool            `gorm:"not null;default:false"`
+	Questions             []Question      `gorm:"type:jsonb;serializer:json"`
+	CheckInOpensBefore    int             `gorm:"not null;default:30"`
+	CheckInClosesAfter    int             `gorm:"not null;default:0"`
+	Frequency             SeriesFrequency `gorm:"not null;default:WEEKLY"`
+	Interval              int             `gorm:"not null;default:1"`
+	Until                 time.Time
+	Sequence              int `gorm:"not null;default:0"`
+}
+
+// NewEventSeries creates a series template from the first occurrence of the series
+func NewEventSeries(event Event, interval int) EventSeries {
+	return EventSeries{
+		ClubID:                event.ClubID,
+		Name:                  event.Name,
+		Description:           event.Description,
+		AfterRegistrationText: event.AfterRegistrationText,
+		Location:              event.Location,
+		VenueID:               event.VenueID,
+		StartTime:             event.StartTime,
+		EndTime:               event.EndTime,
+		RegistrationEnd:       event.RegistrationEnd,
+		CancellationEnd:       event.CancellationEnd,
+		MaxParticipants:       event.MaxParticipants,
+		ExpectedParticipants:  event.ExpectedParticipants,
+		AllowedRoles:          event.AllowedRoles,
+		Reminders:             event.Reminders,
+		MaxGuests:             event.MaxGuests,
+		RequiresApproval:      event.RequiresApproval,
+		Questions:             event.Questions,
+		CheckInOpensBefore:    event.CheckInOpensBefore,
+		CheckInClosesAfter:    event.CheckInClosesAfter,
+		Frequency:             SeriesFrequencyWeekly,
+		Interval:              interval,
+	}
+}
+
+// OccurrenceStart returns the start time of the occurrence with the given index
+//
+// The shift is calculated in the bot location, so the occurrences keep the same local time
+func (s *EventSeries) OccurrenceStart(index int) time.Time {
+	return s.StartTime.In(location.Location()).AddDate(0, 0, 7*s.Interval*index)
+}
+
+// HasOccurrence checks if the occurrence with the given index is not beyond the end of the series
+func (s *EventSeries) HasOccurrence(index int) bool {
+	if s.Until.Year() == 1 {
+		return true
+	}
+	return !s.OccurrenceStart(index).After(s.Until)
+}
+
+// Occurrence builds the event of the occurrence with the given index
+//
+// Optional times (end, cancellation deadline) keep the same offset from the start as in the first occurrence
+func (s *EventSeries) Occurrence(index int) Event {
+	start := s.OccurrenceStart(index)
+	shift := start.Sub(s.StartTime)
+	seriesID := s.ID
+
+	event := Event{
+		ClubID:                s.ClubID,
+		Name:                  s.Name,
+		Description:           s.Description,
+		AfterRegistrationText: s.AfterRegistrationText,
+		Location:              s.Location,
+		VenueID:               s.VenueID,
+		StartTime:             start.UTC(),
+		RegistrationEnd:       s.RegistrationEnd.Add(shift).UTC(),
+		MaxParticipants:       s.MaxParticipants,
+		ExpectedParticipants:  s.ExpectedParticipants,
+		AllowedRoles:          s.AllowedRoles,
+		Reminders:             s.Reminders,
+		MaxGuests:             s.MaxGuests,
+		RequiresApproval:      s.RequiresApproval,
+		Questions:             s.Questions,
+		CheckInOpensBefore:    s.CheckInOpensBefore,
+		CheckInClosesAfter:    s.CheckInClosesAfter,
+		SeriesID:              &seriesID,
+		SeriesIndex:           index,
+	}
+	if s.EndTime.Year() != 1 {
+		event.EndTime = s.EndTime.Add(shift).UTC()
+	}
+	if s.CancellationEnd.Year() != 1 {
+		event.CancellationEnd = s.CancellationEnd.Add(shift).UTC()
+	}
+	return event
+}
+
+// RRule returns the recurrence rule of the series in the iCalendar (RFC 5545) format
+func (s *EventSeries) RRule() string {
+	parts := []string{
+		fmt.Sprintf("FREQ=%s", s.Frequency),
+		fmt.Sprintf("INTERVAL=%d", s.Interval),
+	}
+	if s.Until.Year() != 1 {
+		parts = append(parts, fmt.Sprintf("UNTIL=%s", s.Until.UTC().Format("20060102T150405Z")))
+	}
+	return strings.Join(parts, ";")
+}
+
+// EventTemplate is a named set of the event fields that don't depend on the date of the event.
+//
+// Club owners create events from the templates, the times of the event are asked every time
+type EventTemplate struct {
+	ID        string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClubID    string `gorm:"not null;type:uuid;uniqueIndex:idx_event_templates_club_title"`
+	Club      Club
+	// Title is the name of the template, it is unique within the club
+	Title                 string `gorm:"not null;uniqueIndex:idx_event_templates_club_title"`
+	Name                  string `gorm:"not null"`
+	Description           string `gorm:"not null"`
+	AfterRegistrationText string
+	Location              string  `gorm:"not null"`
+	VenueID               *string `gorm:"type:uuid"`
+	MaxParticipants       int
+	ExpectedParticipants  int
+	AllowedRoles          pq.StringArray `gorm:"type:text[]"`
+	Reminders             pq.StringArray `gorm:"type:text[]"`
+	MaxGuests             int            `gorm:"not null;default:0"`
+	RequiresApproval      bool           `gorm:"not null;default:false"`
+	Questions             []Question     `gorm:"type:jsonb;serializer:json"`
+	CheckInOpensBefore    int            `gorm:"not null;default:30"`
+	CheckInClosesAfter    int            `gorm:"not null;default:0"`
+}
+
+// NewEventTemplate copies the fields of the event that don't depend on its date into the template
+func NewEventTemplate(title string, event Event) EventTemplate {
+	return EventTemplate{
+		ClubID:                event.ClubID,
+		Title:                 title,
+		Name:                  event.Name,
+		Description:           event.Description,
+		AfterRegistrationText: event.AfterRegistrationText,
+		Location:              event.Location,
+		VenueID:               event.VenueID,
+		MaxParticipants:       event.MaxParticipants,
+		ExpectedParticipants:  event.ExpectedParticipants,
+		AllowedRoles:          event.AllowedRoles,
+		Reminders:             event.Reminders,
+		MaxGuests:             event.MaxGuests,
+		RequiresApproval:      event.RequiresApproval,
+		Questions:             event.Questions,
+		CheckInOpensBefore:    event.CheckInOpensBefore,
+		CheckInClosesAfter:    event.CheckInClosesAfter,
+	}
+}
+
+// Event builds the event from the template, the times of the event are left empty
+func (t *EventTemplate) Event() Event {
+	return Event{
+		ClubID:                t.ClubID,
+		Name:                  t.Name,
+		Description:           t.Description,
+		AfterRegistrationText: t.AfterRegistrationText,
+		Location:              t.Location,
+		VenueID:               t.VenueID,
+		MaxParticipants:       t.MaxParticipants,
+		ExpectedParticipants:  t.ExpectedParticipants,
+		AllowedRoles:          t.AllowedRoles,
+		Reminders:             t.Reminders,
+		MaxGuests:             t.MaxGuests,
+		RequiresApproval:      t.RequiresApproval,
+		Questions:             t.Questions,
+		CheckInOpensBefore:    t.CheckInOpensBefore,
+		CheckInClosesAfter:    t.CheckInClosesAfter,
+	}
+}
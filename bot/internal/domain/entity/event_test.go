@@ -0,0 +1,191 @@
+package entity
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestMain(m *testing.M) {
+	viper.Set("settings.timezone", "Europe/Moscow")
+	os.Exit(m.Run())
+}
+
+// seriesStart is Monday 19:00 in Moscow
+var seriesStart = time.Date(2026, 3, 23, 16, 0, 0, 0, time.UTC)
+
+func TestEventSeriesOccurrenceStart(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		index    int
+		want     time.Time
+	}{
+		{name: "first occurrence", interval: 1, index: 0, want: seriesStart},
+		{name: "weekly", interval: 1, index: 3, want: time.Date(2026, 4, 13, 16, 0, 0, 0, time.UTC)},
+		{name: "every two weeks", interval: 2, index: 2, want: time.Date(2026, 4, 20, 16, 0, 0, 0, time.UTC)},
+		{name: "across the year", interval: 1, index: 41, want: time.Date(2027, 1, 4, 16, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := EventSeries{StartTime: seriesStart, Interval: tt.interval}
+			if got := series.OccurrenceStart(tt.index); !got.Equal(tt.want) {
+				t.Errorf("OccurrenceStart(%d) = %v, want %v", tt.index, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventSeriesHasOccurrence(t *testing.T) {
+	tests := []struct {
+		name  string
+		until time.Time
+		index int
+		want  bool
+	}{
+		{name: "no end", index: 100, want: true},
+		{name: "before the end", until: time.Date(2026, 4, 13, 0, 0, 0, 0, time.UTC), index: 2, want: true},
+		{name: "at the end", until: time.Date(2026, 4, 13, 16, 0, 0, 0, time.UTC), index: 3, want: true},
+		{name: "beyond the end", until: time.Date(2026, 4, 13, 0, 0, 0, 0, time.UTC), index: 3, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := EventSeries{StartTime: seriesStart, Interval: 1, Until: tt.until}
+			if got := series.HasOccurrence(tt.index); got != tt.want {
+				t.Errorf("HasOccurrence(%d) = %v, want %v", tt.index, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventSeriesOccurrence(t *testing.T) {
+	week := 7 * 24 * time.Hour
+	tests := []struct {
+		name                string
+		endTime             time.Time
+		cancellationEnd     time.Time
+		index               int
+		wantEndTime         time.Time
+		wantCancellationEnd time.Time
+	}{
+		{
+			name:                "optional times not set",
+			index:               1,
+			wantEndTime:         time.Time{},
+			wantCancellationEnd: time.Time{},
+		},
+		{
+			name:                "optional times keep the offset",
+			endTime:             seriesStart.Add(2 * time.Hour),
+			cancellationEnd:     seriesStart.Add(-3 * time.Hour),
+			index:               2,
+			wantEndTime:         seriesStart.Add(2*week + 2*time.Hour),
+			wantCancellationEnd: seriesStart.Add(2*week - 3*time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := EventSeries{
+				ID:                 "series",
+				StartTime:          seriesStart,
+				EndTime:            tt.endTime,
+				RegistrationEnd:    seriesStart.Add(-24 * time.Hour),
+				CancellationEnd:    tt.cancellationEnd,
+				MaxGuests:          2,
+				RequiresApproval:   true,
+				CheckInOpensBefore: 15,
+				CheckInClosesAfter: 10,
+				Interval:           1,
+			}
+
+			event := series.Occurrence(tt.index)
+			if !event.StartTime.Equal(seriesStart.Add(time.Duration(tt.index) * week)) {
+				t.Errorf("StartTime = %v", event.StartTime)
+			}
+			if !event.RegistrationEnd.Equal(event.StartTime.Add(-24 * time.Hour)) {
+				t.Errorf("RegistrationEnd = %v, want a day before %v", event.RegistrationEnd, event.StartTime)
+			}
+			if !event.EndTime.Equal(tt.wantEndTime) {
+				t.Errorf("EndTime = %v, want %v", event.EndTime, tt.wantEndTime)
+			}
+			if !event.CancellationEnd.Equal(tt.wantCancellationEnd) {
+				t.Errorf("CancellationEnd = %v, want %v", event.CancellationEnd, tt.wantCancellationEnd)
+			}
+			if event.SeriesID == nil || *event.SeriesID != series.ID || event.SeriesIndex != tt.index {
+				t.Errorf("SeriesID = %v, SeriesIndex = %d", event.SeriesID, event.SeriesIndex)
+			}
+			if event.MaxGuests != 2 || !event.RequiresApproval || event.CheckInOpensBefore != 15 || event.CheckInClosesAfter != 10 {
+				t.Errorf("settings of the series are not copied: %+v", event)
+			}
+		})
+	}
+}
+
+func TestEventSeriesRRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		until    time.Time
+		want     string
+	}{
+		{name: "no end", interval: 1, want: "FREQ=WEEKLY;INTERVAL=1"},
+		{
+			name:     "with end",
+			interval: 2,
+			until:    time.Date(2026, 5, 31, 23, 59, 0, 0, time.FixedZone("MSK", 3*60*60)),
+			want:     "FREQ=WEEKLY;INTERVAL=2;UNTIL=20260531T205900Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := EventSeries{Frequency: SeriesFrequencyWeekly, Interval: tt.interval, Until: tt.until}
+			if got := series.RRule(); got != tt.want {
+				t.Errorf("RRule() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventCheckInWindow(t *testing.T) {
+	start := time.Date(2026, 3, 23, 16, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		event     Event
+		wantOpen  time.Time
+		wantClose time.Time
+	}{
+		{
+			name:      "with end time",
+			event:     Event{StartTime: start, EndTime: start.Add(2 * time.Hour), CheckInOpensBefore: 30, CheckInClosesAfter: 15},
+			wantOpen:  start.Add(-30 * time.Minute),
+			wantClose: start.Add(2*time.Hour + 15*time.Minute),
+		},
+		{
+			name:      "without end time",
+			event:     Event{StartTime: start, CheckInOpensBefore: 30},
+			wantOpen:  start.Add(-30 * time.Minute),
+			wantClose: start.Add(24 * time.Hour),
+		},
+		{
+			name:      "opens at the start",
+			event:     Event{StartTime: start, EndTime: start.Add(time.Hour)},
+			wantOpen:  start,
+			wantClose: start.Add(time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, closes := tt.event.CheckInWindow()
+			if !open.Equal(tt.wantOpen) || !closes.Equal(tt.wantClose) {
+				t.Errorf("CheckInWindow() = %v, %v, want %v, %v", open, closes, tt.wantOpen, tt.wantClose)
+			}
+		})
+	}
+}
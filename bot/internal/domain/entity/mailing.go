@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+// MailingAudience is the group of users that receives the club mailing
+type MailingAudience string
+
+const (
+	// MailingAudienceClub - users that registered to the club event at least once
+	MailingAudienceClub MailingAudience = "club"
+	// MailingAudienceVisited - users that visited the club event at least once
+	MailingAudienceVisited MailingAudience = "visited"
+)
+
+var MailingAudiences = []MailingAudience{MailingAudienceClub, MailingAudienceVisited}
+
+func (a MailingAudience) String() string {
+	return string(a)
+}
+
+// ScheduledMailingStatus is the state of the scheduled mailing
+type ScheduledMailingStatus string
+
+const (
+	// ScheduledMailingPending - the mailing waits for its send time and can be edited or cancelled by the club owner
+	ScheduledMailingPending ScheduledMailingStatus = "pending"
+	// ScheduledMailingDispatching - the mailing has been claimed by the scheduler and is being queued
+	ScheduledMailingDispatching ScheduledMailingStatus = "dispatching"
+)
+
+// ScheduledMailing is a club mailing that is sent by the scheduler at SendAt
+type ScheduledMailing struct {
+	ID        string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClubID    string `gorm:"not null;type:uuid;index"`
+	Club      Club
+	// AuthorID - the club owner who receives the delivery report
+	AuthorID     int64 `gorm:"not null"`
+	AuthorLocale string
+	Audience     MailingAudience `gorm:"not null;default:club"`
+	// Text - the text written by the owner, it is put into the club mailing template when sent
+	Text string
+	// MediaType and FileID - the media attached to the mailing, see utils.GetMessageMedia
+	MediaType string
+	FileID    string
+	SendAt    time.Time `gorm:"not null;index"`
+	// Status - only the pending mailings are sent, edited and cancelled, so the scheduler and the owner don't race
+	Status ScheduledMailingStatus `gorm:"not null;default:pending"`
+}
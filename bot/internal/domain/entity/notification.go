@@ -1,14 +1,61 @@
 package entity
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type NotificationType string
 
+// Reminder notification types, the value is stored in EventNotification to deduplicate reminders
 const (
-	NotificationTypeDay  NotificationType = "day"
-	NotificationTypeHour NotificationType = "hour"
+	NotificationType15Minutes NotificationType = "15m"
+	NotificationTypeHour      NotificationType = "hour"
+	NotificationType3Hours    NotificationType = "3h"
+	NotificationTypeDay       NotificationType = "day"
+	NotificationType3Days     NotificationType = "3d"
 )
 
+// ReminderTypes is the list of reminders that can be enabled for a club or an event, ordered by offset
+var ReminderTypes = []NotificationType{
+	NotificationType15Minutes,
+	NotificationTypeHour,
+	NotificationType3Hours,
+	NotificationTypeDay,
+	NotificationType3Days,
+}
+
+var reminderOffsets = map[NotificationType]time.Duration{
+	NotificationType15Minutes: 15 * time.Minute,
+	NotificationTypeHour:      time.Hour,
+	NotificationType3Hours:    3 * time.Hour,
+	NotificationTypeDay:       24 * time.Hour,
+	NotificationType3Days:     72 * time.Hour,
+}
+
+func (t NotificationType) String() string {
+	return string(t)
+}
+
+// IsReminder checks if the notification type is one of ReminderTypes
+func (t NotificationType) IsReminder() bool {
+	return slices.Contains(ReminderTypes, t)
+}
+
+// Offset returns the time before the start of the event at which the reminder is sent
+func (t NotificationType) Offset() time.Duration {
+	return reminderOffsets[t]
+}
+
+// MaxReminderOffset returns the largest offset of ReminderTypes
+func MaxReminderOffset() time.Duration {
+	var maxOffset time.Duration
+	for _, offset := range reminderOffsets {
+		maxOffset = max(maxOffset, offset)
+	}
+	return maxOffset
+}
+
 // EventNotification represents a notification that has been sent to a user
 type EventNotification struct {
 	ID        string           `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+type QuestionType string
+
+const (
+	QuestionText   QuestionType = "text"
+	QuestionNumber QuestionType = "number"
+	QuestionSingle QuestionType = "single"
+	QuestionMulti  QuestionType = "multi"
+)
+
+var QuestionTypes = []QuestionType{QuestionText, QuestionNumber, QuestionSingle, QuestionMulti}
+
+// IsChoice checks if the question is answered by picking the options
+func (t QuestionType) IsChoice() bool {
+	return t == QuestionSingle || t == QuestionMulti
+}
+
+// Question is a question of the registration questionnaire of the event
+type Question struct {
+	Text     string       `json:"text"`
+	Type     QuestionType `json:"type"`
+	Options  []string     `json:"options,omitempty"`
+	Required bool         `json:"required"`
+}
+
+// QuestionAnswer is the answer to a question, the question text is kept so that
+// the answers stay readable after the questionnaire is changed
+type QuestionAnswer struct {
+	Question string   `json:"question"`
+	Values   []string `json:"values"`
+}
+
+// EventAnswers are the answers of the user to the registration questionnaire of the event
+type EventAnswers struct {
+	EventID   string `gorm:"primaryKey;type:uuid"`
+	UserID    int64  `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Answers   []QuestionAnswer `gorm:"type:jsonb;serializer:json"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// EventScanner is the user who can activate the user QR codes on the event without owning the club,
+// the role expires when the check-in window of the event closes
+type EventScanner struct {
+	EventID   string `gorm:"primaryKey;type:uuid"`
+	UserID    int64  `gorm:"primaryKey"`
+	CreatedAt time.Time
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"strings"
 	"time"
 )
 
@@ -29,8 +30,13 @@ type User struct {
 	Role          Role   `gorm:"not null"`
 	Email         string `gorm:"uniqueIndex:idx_users_email,where:email <> ''"`
 	FIO           string `gorm:"not null"`
+	LastName      string `gorm:"not null;default:''"`
+	FirstName     string `gorm:"not null;default:''"`
+	MiddleName    string `gorm:"not null;default:''"`
+	Phone         string `gorm:"not null;default:''"`
 	QRCodeID      string
 	QRFileID      string
+	CalendarToken string          `gorm:"uniqueIndex:idx_users_calendar_token,where:calendar_token <> ''"`
 	IsBanned      bool            `gorm:"default:false"`
 	Clubs         []Club          `gorm:"many2many:club_owners;foreignKey:ID;joinForeignKey:UserID;References:ID;JoinReferences:ClubID"`
 	IgnoreMailing []IgnoreMailing `gorm:"foreignKey:UserID;references:ID"`
@@ -49,6 +55,59 @@ type EventParticipant struct {
 	CreatedAt time.Time
 	IsUserQr  bool
 	IsEventQr bool
+	// IsManual is true if the club owner has marked the visit by hand, for example when the phone of the participant is dead
+	IsManual bool `gorm:"not null;default:false"`
+	// RemindersMuted is true if the user does not want to receive reminders about the event
+	RemindersMuted bool `gorm:"not null;default:false"`
+}
+
+// IsVisited checks if the participant has visited the event, by any of the QR codes or by the mark of the club owner
+func (p *EventParticipant) IsVisited() bool {
+	return p.IsUserQr || p.IsEventQr || p.IsManual
+}
+
+// EventGuest is a guest without Telegram brought to the event by the participant UserID,
+// the guest takes a seat and gets a pass like the participants
+type EventGuest struct {
+	ID         string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	EventID    string `gorm:"not null;type:uuid;index:idx_event_guests_participant"`
+	UserID     int64  `gorm:"not null;index:idx_event_guests_participant"`
+	CreatedAt  time.Time
+	FIO        string `gorm:"not null"`
+	LastName   string `gorm:"not null"`
+	FirstName  string `gorm:"not null"`
+	MiddleName string `gorm:"not null"`
+	Phone      string `gorm:"not null;default:''"`
+}
+
+// SetFIO sets the full name of the guest and its parts
+func (g *EventGuest) SetFIO(fio string) {
+	g.FIO = fio
+	g.LastName, g.FirstName, g.MiddleName, _ = SplitFIO(fio)
+}
+
+// EventWaitlist is a queue entry of a user waiting for a free seat on a full event.
+//
+// OfferExpiresAt is nil while the user is waiting in the queue. When a seat frees up,
+// it is set to the deadline until which the user has to confirm the registration.
+type EventWaitlist struct {
+	EventID        string `gorm:"primaryKey;type:uuid"`
+	UserID         int64  `gorm:"primaryKey"`
+	CreatedAt      time.Time
+	OfferExpiresAt *time.Time
+}
+
+// IsOffered checks if the seat has been offered to the user
+func (w *EventWaitlist) IsOffered() bool {
+	return w.OfferExpiresAt != nil
+}
+
+// EventApplication is a pending registration on the event that requires approval,
+// the user becomes a participant once the club owner approves it
+type EventApplication struct {
+	EventID   string `gorm:"primaryKey;type:uuid"`
+	UserID    int64  `gorm:"primaryKey"`
+	CreatedAt time.Time
 }
 
 type IgnoreMailing struct {
@@ -57,6 +116,24 @@ type IgnoreMailing struct {
 	CreatedAt time.Time
 }
 
+// SetFIO sets the full name of the user and its parts
+func (u *User) SetFIO(fio string) {
+	u.FIO = fio
+	u.LastName, u.FirstName, u.MiddleName, _ = SplitFIO(fio)
+}
+
+// SplitFIO splits the full name into the last, first and middle names,
+// the middle name is empty for two words and takes all the rest words for more than three (e.g. "Али оглы")
+//
+// ok is false if the name has less than two words
+func SplitFIO(fio string) (lastName, firstName, middleName string, ok bool) {
+	parts := strings.Fields(fio)
+	if len(parts) < 2 {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], strings.Join(parts[2:], " "), true
+}
+
 func (u *User) IsMailingAllowed(clubID string) bool {
 	for _, ignoreMailing := range u.IgnoreMailing {
 		if ignoreMailing.ClubID == clubID {
@@ -0,0 +1,28 @@
+package entity
+
+import "testing"
+
+func TestSplitFIO(t *testing.T) {
+	tests := []struct {
+		fio                             string
+		lastName, firstName, middleName string
+		ok                              bool
+	}{
+		{fio: "Иванов Иван Иванович", lastName: "Иванов", firstName: "Иван", middleName: "Иванович", ok: true},
+		{fio: "Иванов Иван", lastName: "Иванов", firstName: "Иван", ok: true},
+		{fio: "Алиев Рашад Али оглы", lastName: "Алиев", firstName: "Рашад", middleName: "Али оглы", ok: true},
+		{fio: "  Иванов   Иван  ", lastName: "Иванов", firstName: "Иван", ok: true},
+		{fio: "Иванов"},
+		{fio: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fio, func(t *testing.T) {
+			lastName, firstName, middleName, ok := SplitFIO(tt.fio)
+			if lastName != tt.lastName || firstName != tt.firstName || middleName != tt.middleName || ok != tt.ok {
+				t.Errorf("SplitFIO(%q) = %q, %q, %q, %v, want %q, %q, %q, %v",
+					tt.fio, lastName, firstName, middleName, ok, tt.lastName, tt.firstName, tt.middleName, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// PassDeadlineLayout is the layout of the pass deadline times
+const PassDeadlineLayout = "15:04"
+
+// DefaultPassDeadlines are the pass deadlines of the venues that don't set their own:
+// on working days the lists are sent at 16:00, on Saturday at 12:00, the pass office doesn't work on Sunday
+var DefaultPassDeadlines = pq.StringArray{"", "16:00", "16:00", "16:00", "16:00", "16:00", "12:00"}
+
+// PassFormat is the file format of the pass list
+type PassFormat string
+
+const (
+	PassFormatXLSX PassFormat = "xlsx"
+	PassFormatCSV  PassFormat = "csv"
+	PassFormatPDF  PassFormat = "pdf"
+)
+
+// PassColumn is a column of the pass list
+type PassColumn string
+
+const (
+	PassColumnLastName   PassColumn = "last-name"
+	PassColumnFirstName  PassColumn = "first-name"
+	PassColumnMiddleName PassColumn = "middle-name"
+	PassColumnFIO        PassColumn = "fio"
+	PassColumnDate       PassColumn = "date"
+	PassColumnTime       PassColumn = "time"
+	PassColumnEvent      PassColumn = "event"
+	PassColumnClub       PassColumn = "club"
+	PassColumnPhone      PassColumn = "phone"
+)
+
+// PassColumns are all the columns the pass list can have
+var PassColumns = []PassColumn{
+	PassColumnLastName,
+	PassColumnFirstName,
+	PassColumnMiddleName,
+	PassColumnFIO,
+	PassColumnDate,
+	PassColumnTime,
+	PassColumnEvent,
+	PassColumnClub,
+	PassColumnPhone,
+}
+
+// DefaultPassColumns are the columns of the pass list if the recipient doesn't set its own
+var DefaultPassColumns = []PassColumn{PassColumnLastName, PassColumnFirstName, PassColumnMiddleName}
+
+// IsEventColumn checks if the column depends on the event, not only on the guest
+func (c PassColumn) IsEventColumn() bool {
+	switch c {
+	case PassColumnDate, PassColumnTime, PassColumnEvent, PassColumnClub:
+		return true
+	}
+	return false
+}
+
+// PassRecipient is where the pass list is sent and in what form, either Email or ChatID is set
+type PassRecipient struct {
+	Email   string       `json:"email,omitempty"`
+	ChatID  int64        `json:"chat_id,omitempty"`
+	Format  PassFormat   `json:"format"`
+	Columns []PassColumn `json:"columns"`
+}
+
+// VenueConflictMode is what happens when the event overlaps another event in the same venue
+type VenueConflictMode string
+
+const (
+	// VenueConflictWarn - the owner is warned about the overlapping events but can create the event
+	VenueConflictWarn VenueConflictMode = "warn"
+	// VenueConflictBlock - the event overlapping another event can't be created
+	VenueConflictBlock VenueConflictMode = "block"
+)
+
+// Venue is a building where the club events are held
+//
+// Venues are described in the config and synced to the database on start,
+// the venues removed from the config are soft deleted so that the events keep their venue
+type Venue struct {
+	ID        string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+	Name      string `gorm:"not null;unique"`
+	Address   string `gorm:"not null"`
+	// Capacity - the maximum number of participants of an event in the venue, 0 means unlimited
+	Capacity int
+	// PassRequired - guests need building passes, the pass lists are sent to PassRecipients
+	// PassLeadDays days before the event at the latest
+	PassRequired   bool
+	PassRecipients []PassRecipient `gorm:"type:jsonb;serializer:json"`
+	PassLeadDays   int             `gorm:"not null;default:1"`
+	// PassDeadlines - the time of day (PassDeadlineLayout) when the pass office takes the lists, indexed by time.Weekday,
+	// an empty value means the office doesn't work on that day
+	PassDeadlines pq.StringArray `gorm:"type:text[]"`
+	// ConflictMode and Buffer - the events in the venue must not overlap, with at least Buffer between them for setup and teardown
+	ConflictMode VenueConflictMode `gorm:"not null;default:warn"`
+	Buffer       time.Duration
+}
+
+// Overlaps checks if the events overlap in the venue, taking the setup and teardown buffer into account
+func (v *Venue) Overlaps(a, b Event) bool {
+	return a.StartTime.Before(b.GetEndTime().Add(v.Buffer)) && b.StartTime.Before(a.GetEndTime().Add(v.Buffer))
+}
+
+// RequiresPhone checks if any pass recipient of the venue asks for the phones of the guests
+func (v *Venue) RequiresPhone() bool {
+	if !v.PassRequired {
+		return false
+	}
+	for _, recipient := range v.PassRecipients {
+		if slices.Contains(recipient.Columns, PassColumnPhone) {
+			return true
+		}
+	}
+	return false
+}
+
+// PassDeadline returns the time when the pass list for the event starting at startTime is collected and sent,
+// it is the last working time of the pass office at least PassLeadDays days before the day of the event
+//
+// ok is false if the pass office doesn't work on any day
+func (v *Venue) PassDeadline(startTime time.Time) (deadline time.Time, ok bool) {
+	start := startTime.In(location.Location())
+	for days := v.PassLeadDays; days < v.PassLeadDays+7; days++ {
+		day := start.AddDate(0, 0, -days)
+		if int(day.Weekday()) >= len(v.PassDeadlines) || v.PassDeadlines[day.Weekday()] == "" {
+			continue
+		}
+
+		t, err := time.Parse(PassDeadlineLayout, v.PassDeadlines[day.Weekday()])
+		if err != nil {
+			continue
+		}
+		deadline = time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, location.Location())
+		// the office may close after the event on the same day
+		if !deadline.Before(start) {
+			continue
+		}
+		return deadline, true
+	}
+	return time.Time{}, false
+}
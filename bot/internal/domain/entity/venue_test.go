@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestVenuePassDeadline(t *testing.T) {
+	msk, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 3, day, hour, 0, 0, 0, msk)
+	}
+
+	tests := []struct {
+		name      string
+		leadDays  int
+		deadlines pq.StringArray
+		start     time.Time
+		want      time.Time
+		wantOK    bool
+	}{
+		{name: "working day before", leadDays: 1, deadlines: DefaultPassDeadlines, start: at(25, 19), want: at(24, 16), wantOK: true},
+		{name: "saturday before monday", leadDays: 1, deadlines: DefaultPassDeadlines, start: at(23, 10), want: at(21, 12), wantOK: true},
+		{name: "saturday before sunday", leadDays: 1, deadlines: DefaultPassDeadlines, start: at(29, 19), want: at(28, 12), wantOK: true},
+		{name: "two days ahead", leadDays: 2, deadlines: DefaultPassDeadlines, start: at(25, 19), want: at(23, 16), wantOK: true},
+		{name: "same day", leadDays: 0, deadlines: DefaultPassDeadlines, start: at(25, 19), want: at(25, 16), wantOK: true},
+		{name: "same day after the start", leadDays: 0, deadlines: DefaultPassDeadlines, start: at(25, 10), want: at(24, 16), wantOK: true},
+		{name: "office never works", leadDays: 1, deadlines: pq.StringArray{"", "", "", "", "", "", ""}, start: at(25, 19)},
+		{name: "no deadlines", leadDays: 1, start: at(25, 19)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			venue := Venue{PassLeadDays: tt.leadDays, PassDeadlines: tt.deadlines}
+			got, ok := venue.PassDeadline(tt.start)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("PassDeadline(%v) = %v, %v, want %v, %v", tt.start, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/xuri/excelize/v2"
+	"gopkg.in/telebot.v3/layout"
+)
+
+type analyticsEventStorage interface {
+	GetPastByClubID(ctx context.Context, clubID string, limit int) ([]entity.Event, error)
+}
+
+type analyticsParticipantStorage interface {
+	GetAttendanceByEventIDs(ctx context.Context, eventIDs []string) ([]dto.Attendance, error)
+}
+
+type AnalyticsService struct {
+	layout *layout.Layout
+
+	eventStorage       analyticsEventStorage
+	participantStorage analyticsParticipantStorage
+}
+
+func NewAnalyticsService(
+	layout *layout.Layout,
+	eventStorage analyticsEventStorage,
+	participantStorage analyticsParticipantStorage,
+) *AnalyticsService {
+	return &AnalyticsService{
+		layout: layout,
+
+		eventStorage:       eventStorage,
+		participantStorage: participantStorage,
+	}
+}
+
+// GetClubAnalytics calculates the attendance of the last eventsCount events of the club that have already started
+func (s *AnalyticsService) GetClubAnalytics(ctx context.Context, clubID string, eventsCount int) (*dto.ClubAnalytics, error) {
+	events, err := s.eventStorage.GetPastByClubID(ctx, clubID, eventsCount)
+	if err != nil {
+		return nil, err
+	}
+	// events are returned newest first, analytics are shown in chronological order
+	slices.Reverse(events)
+
+	eventIDs := make([]string, len(events))
+	for i, event := range events {
+		eventIDs[i] = event.ID
+	}
+
+	var attendance []dto.Attendance
+	if len(eventIDs) > 0 {
+		attendance, err = s.participantStorage.GetAttendanceByEventIDs(ctx, eventIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	analytics := &dto.ClubAnalytics{}
+
+	eventIndexes := make(map[string]int, len(events))
+	for i, event := range events {
+		eventIndexes[event.ID] = i
+		analytics.Events = append(analytics.Events, dto.EventAnalytics{
+			EventID:   event.ID,
+			Name:      event.Name,
+			StartTime: event.StartTime,
+		})
+	}
+
+	roleIndexes := make(map[entity.Role]int)
+	visits := make(map[int64]int)
+	for _, a := range attendance {
+		eventAnalytics := &analytics.Events[eventIndexes[a.EventID]]
+		eventAnalytics.Registered++
+		analytics.Registered++
+
+		roleIndex, ok := roleIndexes[a.Role]
+		if !ok {
+			roleIndex = len(analytics.Roles)
+			roleIndexes[a.Role] = roleIndex
+			analytics.Roles = append(analytics.Roles, dto.RoleAnalytics{Role: a.Role})
+		}
+		analytics.Roles[roleIndex].Registered++
+
+		if a.Visited {
+			eventAnalytics.Visited++
+			analytics.Visited++
+			visits[a.UserID]++
+		} else {
+			analytics.Roles[roleIndex].NoShows++
+		}
+	}
+
+	analytics.Attendees = len(visits)
+	for _, count := range visits {
+		if count > 1 {
+			analytics.RepeatAttendees++
+		}
+	}
+
+	slices.SortFunc(analytics.Roles, func(a, b dto.RoleAnalytics) int {
+		return b.Registered - a.Registered
+	})
+
+	return analytics, nil
+}
+
+// AnalyticsToXLSX generates the report with the club analytics in the given language
+func (s *AnalyticsService) AnalyticsToXLSX(analytics *dto.ClubAnalytics, locale string) (*bytes.Buffer, error) {
+	text := func(key string) string {
+		return s.layout.TextLocale(locale, key)
+	}
+
+	f := excelize.NewFile()
+
+	sheet := text("xlsx_analytics_summary")
+	_ = f.SetSheetName("Sheet1", sheet)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{text("xlsx_analytics_events_count"), len(analytics.Events)})
+	_ = f.SetSheetRow(sheet, "A2", &[]interface{}{text("xlsx_analytics_registered"), analytics.Registered})
+	_ = f.SetSheetRow(sheet, "A3", &[]interface{}{text("xlsx_analytics_visited"), analytics.Visited})
+	_ = f.SetSheetRow(sheet, "A4", &[]interface{}{text("xlsx_analytics_conversion"), round(analytics.Conversion())})
+	_ = f.SetSheetRow(sheet, "A5", &[]interface{}{text("xlsx_analytics_trend"), round(analytics.Trend())})
+	_ = f.SetSheetRow(sheet, "A6", &[]interface{}{text("xlsx_analytics_attendees"), analytics.Attendees})
+	_ = f.SetSheetRow(sheet, "A7", &[]interface{}{text("xlsx_analytics_repeat_attendees"), analytics.RepeatAttendees})
+	_ = f.SetSheetRow(sheet, "A8", &[]interface{}{text("xlsx_analytics_repeat_rate"), round(analytics.RepeatRate())})
+
+	sheet = text("xlsx_analytics_events")
+	_, _ = f.NewSheet(sheet)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{
+		text("xlsx_analytics_event"),
+		text("xlsx_analytics_date"),
+		text("xlsx_analytics_registered"),
+		text("xlsx_analytics_visited"),
+		text("xlsx_analytics_conversion"),
+	})
+	for i, event := range analytics.Events {
+		_ = f.SetSheetRow(sheet, fmt.Sprintf("A%d", i+2), &[]interface{}{
+			event.Name,
+			event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+			event.Registered,
+			event.Visited,
+			round(event.Conversion()),
+		})
+	}
+
+	sheet = text("xlsx_analytics_roles")
+	_, _ = f.NewSheet(sheet)
+	_ = f.SetSheetRow(sheet, "A1", &[]interface{}{
+		text("xlsx_analytics_role"),
+		text("xlsx_analytics_registered"),
+		text("xlsx_analytics_no_shows"),
+		text("xlsx_analytics_no_show_rate"),
+	})
+	for i, role := range analytics.Roles {
+		_ = f.SetSheetRow(sheet, fmt.Sprintf("A%d", i+2), &[]interface{}{
+			text(role.Role.String()),
+			role.Registered,
+			role.NoShows,
+			round(role.NoShowRate()),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
+
+// round rounds the percentage to one decimal place
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/localisation"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+	tele "gopkg.in/telebot.v3"
+	"gopkg.in/telebot.v3/layout"
+)
+
+type ApplicationStorage interface {
+	Create(ctx context.Context, application *entity.EventApplication) (*entity.EventApplication, error)
+	Get(ctx context.Context, eventID string, userID int64) (*entity.EventApplication, error)
+	GetFirst(ctx context.Context, eventID string) (*entity.EventApplication, error)
+	Delete(ctx context.Context, eventID string, userID int64) error
+	CountByEventID(ctx context.Context, eventID string) (int64, error)
+	Approve(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
+}
+
+type applicationEventStorage interface {
+	Get(ctx context.Context, id string) (*entity.Event, error)
+}
+
+type applicationParticipantStorage interface {
+	CountByEventID(ctx context.Context, eventID string) (int64, error)
+}
+
+type applicationUserStorage interface {
+	Get(ctx context.Context, id uint) (*entity.User, error)
+}
+
+type ApplicationService struct {
+	bot    *tele.Bot
+	layout *layout.Layout
+	logger *types.Logger
+
+	storage            ApplicationStorage
+	eventStorage       applicationEventStorage
+	participantStorage applicationParticipantStorage
+	userStorage        applicationUserStorage
+}
+
+func NewApplicationService(
+	bot *tele.Bot,
+	layout *layout.Layout,
+	logger *types.Logger,
+	storage ApplicationStorage,
+	eventStorage applicationEventStorage,
+	participantStorage applicationParticipantStorage,
+	userStorage applicationUserStorage,
+) *ApplicationService {
+	return &ApplicationService{
+		bot:    bot,
+		layout: layout,
+		logger: logger,
+
+		storage:            storage,
+		eventStorage:       eventStorage,
+		participantStorage: participantStorage,
+		userStorage:        userStorage,
+	}
+}
+
+func (s *ApplicationService) Apply(ctx context.Context, eventID string, userID int64) (*entity.EventApplication, error) {
+	return s.storage.Create(ctx, &entity.EventApplication{
+		EventID: eventID,
+		UserID:  userID,
+	})
+}
+
+func (s *ApplicationService) Get(ctx context.Context, eventID string, userID int64) (*entity.EventApplication, error) {
+	return s.storage.Get(ctx, eventID, userID)
+}
+
+// GetNext returns the oldest pending application on the event, the queue is reviewed in the order of submission
+func (s *ApplicationService) GetNext(ctx context.Context, eventID string) (*entity.EventApplication, error) {
+	return s.storage.GetFirst(ctx, eventID)
+}
+
+func (s *ApplicationService) Withdraw(ctx context.Context, eventID string, userID int64) error {
+	return s.storage.Delete(ctx, eventID, userID)
+}
+
+func (s *ApplicationService) CountByEventID(ctx context.Context, eventID string) (int, error) {
+	count, err := s.storage.CountByEventID(ctx, eventID)
+	return int(count), err
+}
+
+// Approve registers the applicant on the event and notifies them.
+//
+// It returns errorz.ErrEventUnavailable if the event has been cancelled, is still a draft or has already started,
+// and errorz.ErrNoSeats if the event has no free seats left.
+func (s *ApplicationService) Approve(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error) {
+	event, err := s.eventStorage.Get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.IsCancelled() || event.IsDraft || event.IsOver(0) {
+		return nil, errorz.ErrEventUnavailable
+	}
+
+	if event.MaxParticipants > 0 {
+		participantsCount, errCount := s.participantStorage.CountByEventID(ctx, eventID)
+		if errCount != nil {
+			return nil, errCount
+		}
+		if int(participantsCount) >= event.MaxParticipants {
+			return nil, errorz.ErrNoSeats
+		}
+	}
+
+	participant, err := s.storage.Approve(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Event application approved (user_id=%d, event_id=%s)", userID, eventID)
+	s.notify(ctx, userID, "event_application_approved", struct {
+		Name                  string
+		AfterRegistrationText string
+	}{
+		Name:                  event.Name,
+		AfterRegistrationText: event.AfterRegistrationText,
+	})
+	return participant, nil
+}
+
+// Reject removes the application and notifies the applicant, the reason is optional
+func (s *ApplicationService) Reject(ctx context.Context, eventID string, userID int64, reason string) error {
+	event, err := s.eventStorage.Get(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.storage.Get(ctx, eventID, userID); err != nil {
+		return err
+	}
+	if err = s.storage.Delete(ctx, eventID, userID); err != nil {
+		return err
+	}
+
+	s.logger.Infof("Event application rejected (user_id=%d, event_id=%s)", userID, eventID)
+	s.notify(ctx, userID, "event_application_rejected", struct {
+		Name   string
+		Reason string
+	}{
+		Name:   event.Name,
+		Reason: reason,
+	})
+	return nil
+}
+
+func (s *ApplicationService) notify(ctx context.Context, userID int64, key string, data interface{}) {
+	chat, err := s.bot.ChatByID(userID)
+	if err != nil {
+		s.logger.Errorf("failed to get chat for user %d: %v", userID, err)
+		return
+	}
+
+	locale := localisation.Default
+	user, err := s.userStorage.Get(ctx, uint(userID))
+	if err != nil {
+		s.logger.Errorf("failed to get user %d: %v", userID, err)
+	} else {
+		locale = localisation.Resolve(user.Localisation)
+	}
+
+	_, err = s.bot.Send(chat,
+		s.layout.TextLocale(locale, key, data),
+		s.layout.MarkupLocale(locale, "core:hide"),
+	)
+	if err != nil {
+		s.logger.Errorf("failed to send %s to user %d: %v", key, userID, err)
+	}
+}
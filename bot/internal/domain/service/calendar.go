@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/calendar"
+	"gorm.io/gorm"
+)
+
+// calendarTokenLength is the length of the calendar feed token (hex characters)
+const calendarTokenLength = 32
+
+// feedEventsLimit is the maximum number of events in the calendar feeds
+const feedEventsLimit = 100
+
+// publicRole is the role of anonymous visitors of the public club calendars,
+// events that are not available for this role are hidden from them
+const publicRole = entity.ExternalUser
+
+type calendarUserStorage interface {
+	Get(ctx context.Context, id uint) (*entity.User, error)
+	GetByCalendarToken(ctx context.Context, token string) (*entity.User, error)
+	Update(ctx context.Context, user *entity.User) (*entity.User, error)
+}
+
+type calendarEventParticipantStorage interface {
+	GetUserEvents(ctx context.Context, userID int64, limit, offset int) ([]dto.UserEvent, error)
+}
+
+type calendarClubStorage interface {
+	Get(ctx context.Context, id string) (*entity.Club, error)
+}
+
+type calendarEventStorage interface {
+	GetFutureByClubID(
+		ctx context.Context,
+		limit, offset int,
+		order string,
+		clubID string,
+		additionalTime time.Duration,
+	) ([]entity.Event, error)
+}
+
+type CalendarService struct {
+	userStorage             calendarUserStorage
+	eventParticipantStorage calendarEventParticipantStorage
+	clubStorage             calendarClubStorage
+	eventStorage            calendarEventStorage
+
+	publicURL string
+}
+
+func NewCalendarService(
+	userStorage calendarUserStorage,
+	eventParticipantStorage calendarEventParticipantStorage,
+	clubStorage calendarClubStorage,
+	eventStorage calendarEventStorage,
+	publicURL string,
+) *CalendarService {
+	return &CalendarService{
+		userStorage:             userStorage,
+		eventParticipantStorage: eventParticipantStorage,
+		clubStorage:             clubStorage,
+		eventStorage:            eventStorage,
+
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// IssueUserToken generates a new calendar feed token for the user.
+//
+// The previous token (if any) stops working.
+func (s *CalendarService) IssueUserToken(ctx context.Context, userID int64) (string, error) {
+	user, err := s.userStorage.Get(ctx, uint(userID))
+	if err != nil {
+		return "", err
+	}
+
+	token, err := generateRandomCode(calendarTokenLength)
+	if err != nil {
+		return "", err
+	}
+
+	user.CalendarToken = token
+	_, err = s.userStorage.Update(ctx, user)
+	return token, err
+}
+
+// RevokeUserToken disables the calendar feed of the user
+func (s *CalendarService) RevokeUserToken(ctx context.Context, userID int64) error {
+	user, err := s.userStorage.Get(ctx, uint(userID))
+	if err != nil {
+		return err
+	}
+
+	user.CalendarToken = ""
+	_, err = s.userStorage.Update(ctx, user)
+	return err
+}
+
+// UserFeedURL returns the url of the user calendar feed for the given token
+func (s *CalendarService) UserFeedURL(token string) string {
+	return fmt.Sprintf("%s/calendar/%s.ics", s.publicURL, token)
+}
+
+// ClubFeedURL returns the url of the public calendar feed of the club
+func (s *CalendarService) ClubFeedURL(clubID string) string {
+	return fmt.Sprintf("%s/clubs/%s/events.ics", s.publicURL, clubID)
+}
+
+// ClubEventsURL returns the url of the public events list of the club
+func (s *CalendarService) ClubEventsURL(clubID string) string {
+	return fmt.Sprintf("%s/clubs/%s/events.json", s.publicURL, clubID)
+}
+
+// GetUserFeed returns the iCalendar feed of events the owner of the token has registered on.
+//
+// If the token is unknown, it returns gorm.ErrRecordNotFound.
+func (s *CalendarService) GetUserFeed(ctx context.Context, token string) ([]byte, error) {
+	if token == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	user, err := s.userStorage.GetByCalendarToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.eventParticipantStorage.GetUserEvents(ctx, user.ID, feedEventsLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return calendar.ExportUserEventsToICS(events)
+}
+
+// GetClubEvents returns the club and its upcoming events that are available to the public
+func (s *CalendarService) GetClubEvents(ctx context.Context, clubID string) (*entity.Club, []entity.Event, error) {
+	club, err := s.clubStorage.Get(ctx, clubID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events, err := s.eventStorage.GetFutureByClubID(ctx, feedEventsLimit, 0, "start_time ASC", clubID, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publicEvents := make([]entity.Event, 0, len(events))
+	for _, event := range events {
+		if slices.Contains(event.AllowedRoles, publicRole.String()) {
+			publicEvents = append(publicEvents, event)
+		}
+	}
+
+	return club, publicEvents, nil
+}
+
+// GetClubFeed returns the iCalendar feed of the club upcoming events that are available to the public
+func (s *CalendarService) GetClubFeed(ctx context.Context, clubID string) ([]byte, error) {
+	club, events, err := s.GetClubEvents(ctx, clubID)
+	if err != nil {
+		return nil, err
+	}
+
+	return calendar.ExportClubEventsToICS(*club, events)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type CheckInAttemptStorage interface {
+	Create(ctx context.Context, attempt *entity.CheckInAttempt) (*entity.CheckInAttempt, error)
+	CountByEventID(ctx context.Context, eventID string) (int64, error)
+}
+
+type CheckInService struct {
+	storage CheckInAttemptStorage
+}
+
+func NewCheckInService(storage CheckInAttemptStorage) *CheckInService {
+	return &CheckInService{
+		storage: storage,
+	}
+}
+
+// Check checks that the user can check in on the event now,
+// returns errorz.ErrCheckInNotOpened or errorz.ErrCheckInClosed and saves the attempt if the check-in window is missed,
+// errorz.ErrEventUnavailable is returned for the cancelled events and drafts
+func (s *CheckInService) Check(ctx context.Context, event *entity.Event, userID, scannedBy int64, method entity.CheckInMethod) error {
+	if event.IsCancelled() || event.IsDraft {
+		return errorz.ErrEventUnavailable
+	}
+
+	opens, closes := event.CheckInWindow()
+	now := time.Now()
+
+	var (
+		reason   entity.CheckInRejectReason
+		errCheck error
+	)
+	switch {
+	case now.Before(opens):
+		reason, errCheck = entity.CheckInEarly, errorz.ErrCheckInNotOpened
+	case now.After(closes):
+		reason, errCheck = entity.CheckInLate, errorz.ErrCheckInClosed
+	default:
+		return nil
+	}
+
+	_, err := s.storage.Create(ctx, &entity.CheckInAttempt{
+		EventID:   event.ID,
+		UserID:    userID,
+		ScannedBy: scannedBy,
+		Method:    method,
+		Reason:    reason,
+	})
+	if err != nil {
+		return err
+	}
+	return errCheck
+}
+
+// CountRejectedByEventID returns how many check-in attempts outside the window have been made on the event
+func (s *CheckInService) CountRejectedByEventID(ctx context.Context, eventID string) (int, error) {
+	count, err := s.storage.CountByEventID(ctx, eventID)
+	return int(count), err
+}
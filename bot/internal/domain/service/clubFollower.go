@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type ClubFollowerStorage interface {
+	Switch(ctx context.Context, userID int64, clubID string) (bool, error)
+	GetClubIDsByUserID(ctx context.Context, userID int64) ([]string, error)
+	GetFollowers(ctx context.Context, clubID string) ([]entity.User, error)
+	CountByClubID(ctx context.Context, clubID string) (int64, error)
+}
+
+type ClubFollowerService struct {
+	storage ClubFollowerStorage
+}
+
+func NewClubFollowerService(storage ClubFollowerStorage) *ClubFollowerService {
+	return &ClubFollowerService{
+		storage: storage,
+	}
+}
+
+// Switch follows or unfollows the club for the user (returns error and new state)
+func (s *ClubFollowerService) Switch(ctx context.Context, userID int64, clubID string) (bool, error) {
+	return s.storage.Switch(ctx, userID, clubID)
+}
+
+// GetClubIDsByUserID returns the ids of the clubs the user follows
+func (s *ClubFollowerService) GetClubIDsByUserID(ctx context.Context, userID int64) ([]string, error) {
+	return s.storage.GetClubIDsByUserID(ctx, userID)
+}
+
+func (s *ClubFollowerService) CountByClubID(ctx context.Context, clubID string) (int64, error) {
+	return s.storage.CountByClubID(ctx, clubID)
+}
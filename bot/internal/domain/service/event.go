@@ -89,10 +89,29 @@ func (s *EventService) GetFutureByClubID(
 //	return s.eventStorage.CountFutureByClubID(ctx, clubID)
 //}
 
+// Update saves the event, the sequence of the event is bumped if the participants see the changes,
+// so all the edits are picked up by the calendar clients the same way
 func (s *EventService) Update(ctx context.Context, event *entity.Event) (*entity.Event, error) {
+	previous, err := s.eventStorage.Get(ctx, event.ID)
+	if err != nil {
+		return event, err
+	}
+	if event.ChangedForParticipants(previous) {
+		event.Sequence = previous.Sequence + 1
+	}
 	return s.eventStorage.Update(ctx, event)
 }
 
+// Cancel marks the event as cancelled with the reason given by the club owner.
+// Unlike Delete, the event is kept, so the participants still see it with the reason
+func (s *EventService) Cancel(ctx context.Context, event *entity.Event, reason string) (*entity.Event, error) {
+	cancelledAt := time.Now().UTC()
+	event.CancelledAt = &cancelledAt
+	event.CancelReason = reason
+	event.QRFileID = ""
+	return s.Update(ctx, event)
+}
+
 func (s *EventService) Delete(ctx context.Context, id string) error {
 	return s.eventStorage.Delete(ctx, id)
 }
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+type EventGuestStorage interface {
+	Create(ctx context.Context, guest *entity.EventGuest) (*entity.EventGuest, error)
+	GetByUser(ctx context.Context, eventID string, userID int64) ([]entity.EventGuest, error)
+	CountByEventID(ctx context.Context, eventID string) (int64, error)
+}
+
+type EventGuestService struct {
+	storage EventGuestStorage
+}
+
+func NewEventGuestService(storage EventGuestStorage) *EventGuestService {
+	return &EventGuestService{
+		storage: storage,
+	}
+}
+
+// Add registers a guest of the participant for the event, the phone is empty unless the venue needs it for the pass,
+// returns errorz.ErrGuestsLimit if the participant has already brought event.MaxGuests guests
+func (s *EventGuestService) Add(ctx context.Context, event *entity.Event, userID int64, fio, phone string) (*entity.EventGuest, error) {
+	guests, err := s.storage.GetByUser(ctx, event.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(guests) >= event.MaxGuests {
+		return nil, errorz.ErrGuestsLimit
+	}
+
+	guest := &entity.EventGuest{
+		EventID: event.ID,
+		UserID:  userID,
+		Phone:   phone,
+	}
+	guest.SetFIO(fio)
+	return s.storage.Create(ctx, guest)
+}
+
+func (s *EventGuestService) GetByUser(ctx context.Context, eventID string, userID int64) ([]entity.EventGuest, error) {
+	return s.storage.GetByUser(ctx, eventID, userID)
+}
+
+func (s *EventGuestService) CountByEventID(ctx context.Context, eventID string) (int, error) {
+	count, err := s.storage.CountByEventID(ctx, eventID)
+	return int(count), err
+}
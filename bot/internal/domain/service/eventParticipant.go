@@ -3,21 +3,24 @@ package service
 import (
 	"bytes"
 	"context"
-	"fmt"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/localisation"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
 	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
 	"github.com/robfig/cron/v3"
-	"github.com/xuri/excelize/v2"
 	tele "gopkg.in/telebot.v3"
 	"gopkg.in/telebot.v3/layout"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 )
 
+// defaultPassSchedule is the cron spec of the pass scheduler if it is not set in the config,
+// it runs at 1, 16, 31 and 46 minutes of every hour
+const defaultPassSchedule = "1/15 * * * *"
+
 type EventParticipantStorage interface {
 	Create(ctx context.Context, eventParticipant *entity.EventParticipant) (*entity.EventParticipant, error)
 	Get(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
@@ -28,24 +31,33 @@ type EventParticipantStorage interface {
 	CountVisitedByEventID(ctx context.Context, eventID string) (int64, error)
 	GetUserEvents(ctx context.Context, userID int64, limit, offset int) ([]dto.UserEvent, error)
 	CountUserEvents(ctx context.Context, userID int64) (int64, error)
+	GetRoster(ctx context.Context, eventID, query string, limit, offset int) ([]dto.EventUser, error)
+	CountRoster(ctx context.Context, eventID, query string) (int64, error)
+	SetVisited(ctx context.Context, eventParticipant *entity.EventParticipant, changedBy int64) error
+	CountAttendanceChanges(ctx context.Context, eventID string) (int64, error)
 }
 
 type eventParticipantEventStorage interface {
 	GetUpcomingEvents(ctx context.Context, before time.Time) ([]entity.Event, error)
+	MarkPassListSent(ctx context.Context, ids []string, sentAt time.Time) error
 }
 
 type userStorage interface {
-	GetManyUsersByEventIDs(ctx context.Context, eventIDs []string) ([]entity.User, error)
+	GetPassGuests(ctx context.Context, eventIDs []string) ([]dto.PassGuest, error)
 }
 
 type eventParticipantSMTPClient interface {
-	Send(to string, body, message string, subject string, file *bytes.Buffer)
+	SendFile(to string, body, message string, subject string, file *bytes.Buffer, fileName string)
 }
 
 type clubStorage interface {
 	GetManyByIDs(ctx context.Context, clubIDs []string) ([]entity.Club, error)
 }
 
+type eventParticipantVenueStorage interface {
+	GetPassRequired(ctx context.Context) ([]entity.Venue, error)
+}
+
 type EventParticipantService struct {
 	bot    *tele.Bot
 	layout *layout.Layout
@@ -56,9 +68,12 @@ type EventParticipantService struct {
 	userStorage                userStorage
 	clubStorage                clubStorage
 	eventParticipantSMTPClient eventParticipantSMTPClient
+	venueStorage               eventParticipantVenueStorage
 
-	passEmails []string
-	passChatID int64
+	// passSchedule is the cron spec of the pass scheduler
+	passSchedule string
+	// passLocale is the language of the pass lists sent to the pass office
+	passLocale string
 }
 
 func NewEventParticipantService(
@@ -70,9 +85,14 @@ func NewEventParticipantService(
 	userStorage userStorage,
 	clubStorage clubStorage,
 	eventParticipantSMTPClient eventParticipantSMTPClient,
-	passEmails []string,
-	passChatID int64,
+	venueStorage eventParticipantVenueStorage,
+	passSchedule string,
+	passLocale string,
 ) *EventParticipantService {
+	if passSchedule == "" {
+		passSchedule = defaultPassSchedule
+	}
+
 	return &EventParticipantService{
 		bot:    bot,
 		layout: layout,
@@ -83,9 +103,10 @@ func NewEventParticipantService(
 		userStorage:                userStorage,
 		clubStorage:                clubStorage,
 		eventParticipantSMTPClient: eventParticipantSMTPClient,
+		venueStorage:               venueStorage,
 
-		passEmails: passEmails,
-		passChatID: passChatID,
+		passSchedule: passSchedule,
+		passLocale:   localisation.Resolve(passLocale),
 	}
 }
 
@@ -108,6 +129,63 @@ func (s *EventParticipantService) Delete(ctx context.Context, eventID string, us
 	return s.storage.Delete(ctx, eventID, userID)
 }
 
+// Unregister cancels the user registration on the event.
+//
+// It returns errorz.ErrCancellationClosed if the cancellation deadline has passed or the user has already visited the event.
+func (s *EventParticipantService) Unregister(ctx context.Context, event *entity.Event, userID int64) error {
+	participant, err := s.storage.Get(ctx, event.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !event.IsCancellationAllowed() || participant.IsVisited() {
+		return errorz.ErrCancellationClosed
+	}
+
+	return s.storage.Delete(ctx, event.ID, userID)
+}
+
+// SwitchVisited marks the participant as visited by hand or removes the visit mark, also the one set by a wrong scan,
+// the change is recorded with the club owner who made it. Returns the new visit mark.
+//
+// It returns errorz.ErrCheckInNotOpened if the participant is marked before the check-in window of the event opens
+func (s *EventParticipantService) SwitchVisited(ctx context.Context, event *entity.Event, userID, changedBy int64) (bool, error) {
+	participant, err := s.storage.Get(ctx, event.ID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if participant.IsVisited() {
+		participant.IsUserQr = false
+		participant.IsEventQr = false
+		participant.IsManual = false
+	} else {
+		if opens, _ := event.CheckInWindow(); time.Now().Before(opens) {
+			return false, errorz.ErrCheckInNotOpened
+		}
+		participant.IsManual = true
+	}
+
+	err = s.storage.SetVisited(ctx, participant, changedBy)
+	return participant.IsVisited(), err
+}
+
+// SwitchReminders mutes or unmutes the reminders about the event for the user and returns the new state
+func (s *EventParticipantService) SwitchReminders(ctx context.Context, eventID string, userID int64) (bool, error) {
+	participant, err := s.storage.Get(ctx, eventID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	participant.RemindersMuted = !participant.RemindersMuted
+	participant, err = s.storage.Update(ctx, participant)
+	if err != nil {
+		return false, err
+	}
+
+	return participant.RemindersMuted, nil
+}
+
 func (s *EventParticipantService) GetByEventID(ctx context.Context, eventID string) ([]entity.EventParticipant, error) {
 	return s.storage.GetByEventID(ctx, eventID)
 }
@@ -122,6 +200,22 @@ func (s *EventParticipantService) CountVisitedByEventID(ctx context.Context, eve
 	return int(count), err
 }
 
+// GetRoster returns the page of the participants of the event, query filters them by the full name or the username
+func (s *EventParticipantService) GetRoster(ctx context.Context, eventID, query string, limit, offset int) ([]dto.EventUser, error) {
+	return s.storage.GetRoster(ctx, eventID, strings.TrimPrefix(strings.TrimSpace(query), "@"), limit, offset)
+}
+
+func (s *EventParticipantService) CountRoster(ctx context.Context, eventID, query string) (int, error) {
+	count, err := s.storage.CountRoster(ctx, eventID, strings.TrimPrefix(strings.TrimSpace(query), "@"))
+	return int(count), err
+}
+
+// CountAttendanceChanges returns how many times the visit marks of the event have been changed by hand
+func (s *EventParticipantService) CountAttendanceChanges(ctx context.Context, eventID string) (int, error) {
+	count, err := s.storage.CountAttendanceChanges(ctx, eventID)
+	return int(count), err
+}
+
 func (s *EventParticipantService) GetUserEvents(ctx context.Context, userID int64, limit, offset int) ([]dto.UserEvent, error) {
 	return s.storage.GetUserEvents(ctx, userID, limit, offset)
 }
@@ -130,19 +224,12 @@ func (s *EventParticipantService) CountUserEvents(ctx context.Context, userID in
 	return s.storage.CountUserEvents(ctx, userID)
 }
 
+// StartPassScheduler starts the scheduler that sends the pass lists to the pass offices of the venues
 func (s *EventParticipantService) StartPassScheduler() {
-	s.logger.Info("Starting pass scheduler")
+	s.logger.Infof("Starting pass scheduler (schedule=%s)", s.passSchedule)
 	go func() {
 		c := cron.New(cron.WithLocation(location.Location()))
-		if _, err := c.AddFunc("1 16 * * 1-5", func() {
-			ctx := context.Background()
-			s.checkAndSend(ctx)
-		}); err != nil {
-			s.logger.Error("Failed to start pass scheduler:", err)
-			return
-		}
-
-		if _, err := c.AddFunc("1 12 * * 6", func() {
+		if _, err := c.AddFunc(s.passSchedule, func() {
 			ctx := context.Background()
 			s.checkAndSend(ctx)
 		}); err != nil {
@@ -155,61 +242,96 @@ func (s *EventParticipantService) StartPassScheduler() {
 	}()
 }
 
+// checkAndSend sends the pass lists for the events whose pass deadline has come and whose guests have not been sent yet,
+// one list per venue. The events are marked in the database, so the deadlines missed while the bot was down are caught up
 func (s *EventParticipantService) checkAndSend(ctx context.Context) {
-	s.logger.Debugf("Checking for events starting in the next 25 hours")
+	s.logger.Debugf("Checking for events with the pass deadline")
 	now := time.Now().In(location.Location())
 
-	events, err := s.eventStorage.GetUpcomingEvents(ctx, now.Add(72*time.Hour))
+	venues, err := s.venueStorage.GetPassRequired(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to get venues: %v", err)
+		return
+	}
+	if len(venues) == 0 {
+		return
+	}
+
+	// the pass deadline is at most a week earlier than the lead days before the event
+	maxLeadDays := 0
+	for _, venue := range venues {
+		maxLeadDays = max(maxLeadDays, venue.PassLeadDays)
+	}
+	events, err := s.eventStorage.GetUpcomingEvents(ctx, now.AddDate(0, 0, maxLeadDays+8))
 	if err != nil {
 		s.logger.Errorf("failed to get upcoming events: %v", err)
 		return
 	}
 
-	var eventIDs []string
-	var clubsIDs []string
-
-	for _, event := range events {
-		eventStartTime := event.StartTime.In(location.Location())
-		weekday := eventStartTime.Weekday()
-
-		// Determine notification time
-		var notificationTime time.Time
-		if weekday == time.Sunday {
-			notificationTime = time.Date(eventStartTime.Year(), eventStartTime.Month(), eventStartTime.Day()-1, 12, 0, 0, 0, location.Location())
-		} else if weekday == time.Monday {
-			notificationTime = time.Date(eventStartTime.Year(), eventStartTime.Month(), eventStartTime.Day()-2, 12, 0, 0, 0, location.Location())
-		} else {
-			notificationTime = time.Date(eventStartTime.Year(), eventStartTime.Month(), eventStartTime.Day(), 0, 0, 0, 0, eventStartTime.Location()).Add(-24 * time.Hour).Add(16 * time.Hour)
+	for _, venue := range venues {
+		var eventIDs []string
+		var clubsIDs []string
+
+		for _, event := range events {
+			if !isVenueEvent(event, venue) {
+				continue
+			}
+
+			deadline, ok := venue.PassDeadline(event.StartTime)
+			if ok && event.PassListSentAt == nil && !deadline.After(now) {
+				eventIDs = append(eventIDs, event.ID)
+				clubsIDs = append(clubsIDs, event.ClubID)
+			}
+		}
+		if len(eventIDs) == 0 {
+			continue
 		}
 
-		// Check if it's valid event for notification and it's time to send the notification
-		if (strings.Contains(event.Location, "Гашека 7")) && !(now.Before(notificationTime) || now.After(notificationTime.Add(1*time.Hour))) {
-			eventIDs = append(eventIDs, event.ID)
-			clubsIDs = append(clubsIDs, event.ClubID)
+		if err = s.sendPassList(ctx, venue, eventIDs, clubsIDs); err != nil {
+			s.logger.Errorf("failed to send pass list of venue %s: %v", venue.Name, err)
+			continue
+		}
+		if err = s.eventStorage.MarkPassListSent(ctx, eventIDs, now); err != nil {
+			s.logger.Errorf("failed to mark pass list sent for events %s: %v", eventIDs, err)
 		}
 	}
+}
 
-	var participants []entity.User
-	participants, err = s.userStorage.GetManyUsersByEventIDs(ctx, eventIDs)
+// isVenueEvent checks if the event is held in the venue,
+// events created before the venues were introduced are matched by the venue name in the location
+func isVenueEvent(event entity.Event, venue entity.Venue) bool {
+	if event.VenueID != nil {
+		return *event.VenueID == venue.ID
+	}
+	return strings.Contains(event.Location, venue.Name)
+}
+
+// sendPassList sends the list of the guests of the events to every pass recipient of the venue in its format,
+// the guests that can't be put into the list are reported along with it.
+// The error is returned only if the list can't be formed, the failed deliveries to the recipients are logged
+func (s *EventParticipantService) sendPassList(ctx context.Context, venue entity.Venue, eventIDs []string, clubsIDs []string) error {
+	guests, err := s.userStorage.GetPassGuests(ctx, eventIDs)
 	if err != nil {
-		s.logger.Errorf("failed to get participants for events %s: %v", eventIDs, err)
-		return
+		return err
 	}
 
-	var participantsWithoutStudents []entity.User
-	for _, participant := range participants {
-		if !(participant.Role == entity.Student) {
-			participantsWithoutStudents = append(participantsWithoutStudents, participant)
+	var included, excluded []dto.PassGuest
+	for _, guest := range guests {
+		switch {
+		case guest.Role == entity.Student:
+		case guest.LastName == "" || guest.FirstName == "":
+			excluded = append(excluded, guest)
+		default:
+			included = append(included, guest)
 		}
 	}
-	if len(participantsWithoutStudents) == 0 {
-		return
+	if len(included) == 0 && len(excluded) == 0 {
+		return nil
 	}
 
-	clubs, err := s.clubStorage.GetManyByIDs(context.Background(), clubsIDs)
+	clubs, err := s.clubStorage.GetManyByIDs(ctx, clubsIDs)
 	if err != nil {
-		s.logger.Errorf("failed to get clubs: %v", err)
-		return
+		return err
 	}
 	clubsName := make([]string, len(clubs))
 	for i, club := range clubs {
@@ -217,60 +339,80 @@ func (s *EventParticipantService) checkAndSend(ctx context.Context) {
 	}
 	clubsNameStr := strings.Join(clubsName, ", ")
 
-	var buf *bytes.Buffer
-	buf, err = participantsToXLSX(participantsWithoutStudents)
-	if err != nil {
-		s.logger.Errorf("failed to form xlsx with participants %s: %v", eventIDs, err)
-		return
-	}
-
-	message := fmt.Sprintf("Внешние гости_%s_%s", clubsNameStr, time.Now().Format("02.01.2006"))
-
-	for _, passEmail := range s.passEmails {
-		s.eventParticipantSMTPClient.Send(passEmail, message, message, message, buf)
-	}
+	message := s.layout.TextLocale(s.passLocale, "pass_email_subject", struct {
+		Clubs string
+		Date  string
+	}{
+		Clubs: clubsNameStr,
+		Date:  time.Now().In(location.Location()).Format("02.01.2006"),
+	})
 
-	chat, errGetChat := s.bot.ChatByID(s.passChatID)
-	if errGetChat != nil {
-		s.logger.Errorf("failed to get chat %d: %v", s.passChatID, errGetChat)
-		return
+	var report string
+	if len(excluded) > 0 {
+		s.logger.Warnf("%d guests can't be put into the pass list of venue %s", len(excluded), venue.Name)
+		report = s.excludedGuestsReport(venue, excluded)
 	}
 
-	file := &tele.Document{
-		File:     tele.FromReader(buf),
-		Caption:  s.layout.TextLocale("ru", "pass_users"),
-		FileName: "users.xlsx",
-	}
-	_, errSend := s.bot.Send(chat, file)
-	if errSend != nil {
-		s.logger.Errorf("failed to send notification to chat %d: %v", s.passChatID, errSend)
-		return
-	}
-}
-
-func participantsToXLSX(users []entity.User) (*bytes.Buffer, error) {
-	f := excelize.NewFile()
+	for _, recipient := range venue.PassRecipients {
+		if len(included) == 0 {
+			// there is nothing to send but the report
+			if recipient.ChatID != 0 {
+				s.sendToPassChat(recipient.ChatID, report)
+			}
+			continue
+		}
 
-	sheet := "Sheet1"
-	_ = f.SetCellValue(sheet, "A1", "Фамилия")
-	_ = f.SetCellValue(sheet, "B1", "Имя")
-	_ = f.SetCellValue(sheet, "C1", "Отчество")
-	for i, user := range users {
-		fio := strings.Split(user.FIO, " ")
-		if len(fio) != 3 {
+		buf, err := s.passList(recipient, included)
+		if err != nil {
+			s.logger.Errorf("failed to form %s pass list with participants %s: %v", recipient.Format, eventIDs, err)
+			continue
+		}
+		fileName := "users." + string(recipient.Format)
+
+		if recipient.Email != "" {
+			body := message
+			if report != "" {
+				body += "\n\n" + report
+			}
+			s.eventParticipantSMTPClient.SendFile(recipient.Email, body, strings.ReplaceAll(body, "\n", "<br>"), message, buf, fileName)
 			continue
 		}
 
-		row := i + 2
-		_ = f.SetCellValue(sheet, "A"+strconv.Itoa(row), fio[0])
-		_ = f.SetCellValue(sheet, "B"+strconv.Itoa(row), fio[1])
-		_ = f.SetCellValue(sheet, "C"+strconv.Itoa(row), fio[2])
+		chat, errGetChat := s.bot.ChatByID(recipient.ChatID)
+		if errGetChat != nil {
+			s.logger.Errorf("failed to get chat %d: %v", recipient.ChatID, errGetChat)
+			continue
+		}
+		file := &tele.Document{
+			File: tele.FromReader(bytes.NewReader(buf.Bytes())),
+			Caption: s.layout.TextLocale(s.passLocale, "pass_users", struct {
+				Venue   string
+				Address string
+			}{
+				Venue:   venue.Name,
+				Address: venue.Address,
+			}),
+			FileName: fileName,
+		}
+		if _, errSend := s.bot.Send(chat, file); errSend != nil {
+			s.logger.Errorf("failed to send pass list to chat %d: %v", recipient.ChatID, errSend)
+			continue
+		}
+		if report != "" {
+			s.sendToPassChat(recipient.ChatID, report)
+		}
 	}
+	return nil
+}
 
-	var buf bytes.Buffer
-	if err := f.Write(&buf); err != nil {
-		return nil, err
+// sendToPassChat sends the text to the chat of the pass recipient
+func (s *EventParticipantService) sendToPassChat(chatID int64, text string) {
+	chat, err := s.bot.ChatByID(chatID)
+	if err != nil {
+		s.logger.Errorf("failed to get chat %d: %v", chatID, err)
+		return
+	}
+	if _, err = s.bot.Send(chat, text); err != nil {
+		s.logger.Errorf("failed to send to chat %d: %v", chatID, err)
 	}
-
-	return &buf, nil
 }
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+)
+
+// defaultSeriesHorizon is how far ahead the occurrences of the series are created if it isn't configured
+const defaultSeriesHorizon = 30 * 24 * time.Hour
+
+type EventSeriesStorage interface {
+	Create(ctx context.Context, series *entity.EventSeries) (*entity.EventSeries, error)
+	Get(ctx context.Context, id string) (*entity.EventSeries, error)
+	GetAll(ctx context.Context) ([]entity.EventSeries, error)
+	Update(ctx context.Context, series *entity.EventSeries) (*entity.EventSeries, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type seriesEventStorage interface {
+	Create(ctx context.Context, event *entity.Event) (*entity.Event, error)
+	Update(ctx context.Context, event *entity.Event) (*entity.Event, error)
+	GetFutureBySeriesID(ctx context.Context, seriesID string) ([]entity.Event, error)
+	GetLastSeriesIndex(ctx context.Context, seriesID string) (int, error)
+	GetAllBySeriesID(ctx context.Context, seriesID string) ([]entity.Event, error)
+}
+
+type EventSeriesService struct {
+	logger *types.Logger
+
+	storage      EventSeriesStorage
+	eventStorage seriesEventStorage
+
+	horizon time.Duration
+}
+
+func NewEventSeriesService(
+	logger *types.Logger,
+	storage EventSeriesStorage,
+	eventStorage seriesEventStorage,
+	horizon time.Duration,
+) *EventSeriesService {
+	if horizon <= 0 {
+		horizon = defaultSeriesHorizon
+	}
+
+	return &EventSeriesService{
+		logger: logger,
+
+		storage:      storage,
+		eventStorage: eventStorage,
+
+		horizon: horizon,
+	}
+}
+
+// Create creates a series with the given event as its first occurrence
+// and materialises the following occurrences within the horizon
+func (s *EventSeriesService) Create(ctx context.Context, event *entity.Event, interval int) (*entity.EventSeries, error) {
+	series := entity.NewEventSeries(*event, interval)
+	if _, err := s.storage.Create(ctx, &series); err != nil {
+		return nil, err
+	}
+
+	event.SeriesID = &series.ID
+	event.SeriesIndex = 0
+	if _, err := s.eventStorage.Create(ctx, event); err != nil {
+		return nil, err
+	}
+
+	return &series, s.materialize(ctx, &series)
+}
+
+func (s *EventSeriesService) Get(ctx context.Context, id string) (*entity.EventSeries, error) {
+	return s.storage.Get(ctx, id)
+}
+
+// GetFutureOccurrences returns materialised occurrences of the series that have not started yet
+func (s *EventSeriesService) GetFutureOccurrences(ctx context.Context, seriesID string) ([]entity.Event, error) {
+	return s.eventStorage.GetFutureBySeriesID(ctx, seriesID)
+}
+
+// GetOccurrences returns all materialised occurrences of the series, the ones cancelled one by one are included,
+// so the series can be exported with its exclusions and edited occurrences
+func (s *EventSeriesService) GetOccurrences(ctx context.Context, seriesID string) ([]entity.Event, error) {
+	return s.eventStorage.GetAllBySeriesID(ctx, seriesID)
+}
+
+// ExcludeOccurrence bumps the sequence of the series after one of its occurrences has been cancelled,
+// so the calendar clients apply the new list of the excluded occurrences
+func (s *EventSeriesService) ExcludeOccurrence(ctx context.Context, seriesID string) (*entity.EventSeries, error) {
+	series, err := s.storage.Get(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	series.Sequence++
+	return s.storage.Update(ctx, series)
+}
+
+// ApplyToSeries copies the settings of the occurrence to the series template
+// and to all other occurrences that have not started yet.
+//
+// It returns the occurrences that have been updated.
+func (s *EventSeriesService) ApplyToSeries(ctx context.Context, event *entity.Event) ([]entity.Event, error) {
+	series, err := s.storage.Get(ctx, *event.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	series.Name = event.Name
+	series.Description = event.Description
+	series.AfterRegistrationText = event.AfterRegistrationText
+	series.Location = event.Location
+	series.VenueID = event.VenueID
+	series.MaxParticipants = event.MaxParticipants
+	series.ExpectedParticipants = event.ExpectedParticipants
+	series.AllowedRoles = event.AllowedRoles
+	series.Reminders = event.Reminders
+	series.MaxGuests = event.MaxGuests
+	series.RequiresApproval = event.RequiresApproval
+	series.Questions = event.Questions
+	series.CheckInOpensBefore = event.CheckInOpensBefore
+	series.CheckInClosesAfter = event.CheckInClosesAfter
+	series.Sequence++
+	if _, err = s.storage.Update(ctx, series); err != nil {
+		return nil, err
+	}
+
+	occurrences, err := s.eventStorage.GetFutureBySeriesID(ctx, series.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []entity.Event
+	for _, occurrence := range occurrences {
+		if occurrence.ID == event.ID {
+			continue
+		}
+		previous := occurrence
+
+		occurrence.Name = series.Name
+		occurrence.Description = series.Description
+		occurrence.AfterRegistrationText = series.AfterRegistrationText
+		occurrence.Location = series.Location
+		occurrence.VenueID = series.VenueID
+		occurrence.MaxParticipants = series.MaxParticipants
+		occurrence.ExpectedParticipants = series.ExpectedParticipants
+		occurrence.AllowedRoles = series.AllowedRoles
+		occurrence.Reminders = series.Reminders
+		occurrence.MaxGuests = series.MaxGuests
+		occurrence.RequiresApproval = series.RequiresApproval
+		occurrence.Questions = series.Questions
+		occurrence.CheckInOpensBefore = series.CheckInOpensBefore
+		occurrence.CheckInClosesAfter = series.CheckInClosesAfter
+		if occurrence.ChangedForParticipants(&previous) {
+			occurrence.Sequence++
+		}
+		if _, err = s.eventStorage.Update(ctx, &occurrence); err != nil {
+			return updated, err
+		}
+		updated = append(updated, occurrence)
+	}
+
+	return updated, nil
+}
+
+// Cancel stops the series, so no more occurrences are created.
+//
+// It returns the occurrences that have not started and haven't been cancelled yet,
+// they are cancelled the same way as the single events, so their participants get the reason.
+func (s *EventSeriesService) Cancel(ctx context.Context, seriesID string) ([]entity.Event, error) {
+	occurrences, err := s.eventStorage.GetFutureBySeriesID(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.storage.Delete(ctx, seriesID); err != nil {
+		return nil, err
+	}
+
+	var active []entity.Event
+	for _, occurrence := range occurrences {
+		if !occurrence.IsCancelled() {
+			active = append(active, occurrence)
+		}
+	}
+	return active, nil
+}
+
+// StartSeriesScheduler starts the scheduler that materialises occurrences of the series ahead of time
+func (s *EventSeriesService) StartSeriesScheduler() {
+	s.logger.Info("Starting series scheduler")
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx := context.Background()
+			s.materializeAll(ctx)
+		}
+	}()
+}
+
+func (s *EventSeriesService) materializeAll(ctx context.Context) {
+	seriesList, err := s.storage.GetAll(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to get event series: %v", err)
+		return
+	}
+
+	for _, series := range seriesList {
+		if err = s.materialize(ctx, &series); err != nil {
+			s.logger.Errorf("failed to materialize event series %s: %v", series.ID, err)
+		}
+	}
+}
+
+// materialize creates occurrences of the series that start within the horizon
+//
+// Occurrences with the registration already closed are skipped
+func (s *EventSeriesService) materialize(ctx context.Context, series *entity.EventSeries) error {
+	lastIndex, err := s.eventStorage.GetLastSeriesIndex(ctx, series.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(location.Location())
+	for index := lastIndex + 1; series.HasOccurrence(index); index++ {
+		occurrence := series.Occurrence(index)
+		if occurrence.StartTime.After(now.Add(s.horizon)) {
+			break
+		}
+		if occurrence.RegistrationEnd.Before(now) {
+			continue
+		}
+
+		s.logger.Infof("Materializing occurrence %d of event series %s", index, series.ID)
+		if _, err = s.eventStorage.Create(ctx, &occurrence); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
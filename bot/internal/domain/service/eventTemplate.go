@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+)
+
+// MaxEventTemplates is how many event templates a club can have
+const MaxEventTemplates = 20
+
+type EventTemplateStorage interface {
+	Create(ctx context.Context, template *entity.EventTemplate) (*entity.EventTemplate, error)
+	Get(ctx context.Context, id string) (*entity.EventTemplate, error)
+	GetByClubID(ctx context.Context, clubID string) ([]entity.EventTemplate, error)
+	CountByClubID(ctx context.Context, clubID string) (int64, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type EventTemplateService struct {
+	storage EventTemplateStorage
+}
+
+func NewEventTemplateService(storage EventTemplateStorage) *EventTemplateService {
+	return &EventTemplateService{
+		storage: storage,
+	}
+}
+
+// Create saves the event as the template of its club,
+// returns errorz.ErrTemplatesLimit if the club already has MaxEventTemplates templates
+// and gorm.ErrDuplicatedKey if the club already has the template with the same title
+func (s *EventTemplateService) Create(ctx context.Context, title string, event *entity.Event) (*entity.EventTemplate, error) {
+	count, err := s.storage.CountByClubID(ctx, event.ClubID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxEventTemplates {
+		return nil, errorz.ErrTemplatesLimit
+	}
+
+	template := entity.NewEventTemplate(title, *event)
+	return s.storage.Create(ctx, &template)
+}
+
+func (s *EventTemplateService) Get(ctx context.Context, id string) (*entity.EventTemplate, error) {
+	return s.storage.Get(ctx, id)
+}
+
+func (s *EventTemplateService) GetByClubID(ctx context.Context, clubID string) ([]entity.EventTemplate, error) {
+	return s.storage.GetByClubID(ctx, clubID)
+}
+
+func (s *EventTemplateService) Delete(ctx context.Context, id string) error {
+	return s.storage.Delete(ctx, id)
+}
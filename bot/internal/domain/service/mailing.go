@@ -0,0 +1,288 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/localisation"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/ratelimit"
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+	"gopkg.in/telebot.v3/layout"
+)
+
+const (
+	// defaultMailingRate is the number of messages sent per second, Telegram allows about 30
+	defaultMailingRate = 25
+	// mailingTTL is how long the mailing and the delivery statuses are kept
+	mailingTTL = 7 * 24 * time.Hour
+	// mailingPopTimeout is how long the scheduler waits for a new job before polling again
+	mailingPopTimeout = 5 * time.Second
+	// mailingMaxAttempts is the number of attempts to send the message when Telegram asks to retry later
+	mailingMaxAttempts = 5
+)
+
+type mailingStorage interface {
+	Push(mailing dto.Mailing, recipients []dto.MailingRecipient, expiration time.Duration) error
+	Pop(timeout time.Duration) (dto.MailingRecipient, error)
+	Ack(recipient dto.MailingRecipient) error
+	Restore() (int, error)
+	Get(mailingID string) (dto.Mailing, error)
+	Update(mailing dto.Mailing) error
+	SetStatus(mailingID string, userID int64, status dto.MailingStatus, expiration time.Duration) (int64, error)
+	GetReport(mailingID string) (dto.MailingReport, error)
+}
+
+type MailingService struct {
+	bot    *tele.Bot
+	layout *layout.Layout
+	logger *types.Logger
+
+	storage mailingStorage
+	limiter *ratelimit.TokenBucket
+}
+
+func NewMailingService(
+	bot *tele.Bot,
+	layout *layout.Layout,
+	logger *types.Logger,
+	storage mailingStorage,
+	rate int,
+) *MailingService {
+	if rate <= 0 {
+		rate = defaultMailingRate
+	}
+
+	return &MailingService{
+		bot:    bot,
+		layout: layout,
+		logger: logger,
+
+		storage: storage,
+		limiter: ratelimit.NewTokenBucket(rate, rate),
+	}
+}
+
+// Texts renders the layout text key with the given data in every supported language
+func (s *MailingService) Texts(key string, data interface{}) map[string]string {
+	texts := make(map[string]string, len(localisation.Supported))
+	for _, locale := range localisation.Supported {
+		texts[locale] = s.layout.TextLocale(locale, key, data)
+	}
+	return texts
+}
+
+// Send queues the mailing for delivery to the users and returns the mailing id
+//
+// The messages are sent by the mailing scheduler, the author of the mailing gets the delivery report when it is done
+func (s *MailingService) Send(mailing dto.Mailing, users []entity.User) (string, error) {
+	mailing.ID = uuid.New().String()
+
+	recipients := make([]dto.MailingRecipient, 0, len(users))
+	for _, user := range users {
+		recipients = append(recipients, dto.MailingRecipient{
+			UserID: user.ID,
+			Locale: localisation.Resolve(user.Localisation),
+		})
+	}
+
+	if err := s.storage.Push(mailing, recipients, mailingTTL); err != nil {
+		return "", err
+	}
+	s.logger.Infof("Mailing queued (mailing_id=%s, club_id=%s, recipients=%d)", mailing.ID, mailing.ClubID, len(recipients))
+
+	if len(recipients) == 0 {
+		s.sendReport(mailing)
+	}
+	return mailing.ID, nil
+}
+
+// StartMailingScheduler starts the scheduler that delivers the queued mailings
+func (s *MailingService) StartMailingScheduler() {
+	s.logger.Info("Starting mailing scheduler")
+
+	// The jobs that were being processed when the bot stopped are delivered first
+	restored, err := s.storage.Restore()
+	if err != nil {
+		s.logger.Errorf("failed to restore mailing jobs: %v", err)
+	} else if restored > 0 {
+		s.logger.Infof("Mailing jobs restored (jobs=%d)", restored)
+	}
+
+	go func() {
+		var mailing dto.Mailing
+		for {
+			recipient, err := s.storage.Pop(mailingPopTimeout)
+			if errors.Is(err, errorz.ErrQueueEmpty) {
+				continue
+			}
+			if err != nil {
+				s.logger.Errorf("failed to get mailing job: %v", err)
+				time.Sleep(mailingPopTimeout)
+				continue
+			}
+
+			if mailing.ID != recipient.MailingID {
+				mailing, err = s.storage.Get(recipient.MailingID)
+				if err != nil {
+					s.logger.Errorf("failed to get mailing %s: %v", recipient.MailingID, err)
+					s.skip(recipient)
+				}
+			}
+			if err == nil {
+				s.process(&mailing, recipient)
+			}
+
+			if err = s.storage.Ack(recipient); err != nil {
+				s.logger.Errorf("failed to ack mailing job (mailing_id=%s, user_id=%d): %v", recipient.MailingID, recipient.UserID, err)
+			}
+		}
+	}()
+}
+
+// process delivers the mailing to the recipient, records the status and sends the report after the last recipient
+func (s *MailingService) process(mailing *dto.Mailing, recipient dto.MailingRecipient) {
+	status := s.deliver(mailing, recipient)
+
+	pending, err := s.storage.SetStatus(mailing.ID, recipient.UserID, status, mailingTTL)
+	if err != nil {
+		s.logger.Errorf("failed to set mailing status (mailing_id=%s, user_id=%d): %v", mailing.ID, recipient.UserID, err)
+		return
+	}
+
+	if pending == 0 {
+		s.logger.Infof("Mailing delivered (mailing_id=%s)", mailing.ID)
+		s.sendReport(*mailing)
+	}
+}
+
+// skip records the recipient of the mailing that can't be read as failed,
+// so the pending counter still reaches zero and the report is sent if the mailing can be read by then
+func (s *MailingService) skip(recipient dto.MailingRecipient) {
+	pending, err := s.storage.SetStatus(recipient.MailingID, recipient.UserID, dto.MailingFailed, mailingTTL)
+	if err != nil {
+		s.logger.Errorf("failed to set mailing status (mailing_id=%s, user_id=%d): %v", recipient.MailingID, recipient.UserID, err)
+		return
+	}
+	if pending != 0 {
+		return
+	}
+
+	mailing, err := s.storage.Get(recipient.MailingID)
+	if err != nil {
+		s.logger.Errorf("failed to get mailing %s for the report: %v", recipient.MailingID, err)
+		return
+	}
+	s.logger.Infof("Mailing delivered (mailing_id=%s)", mailing.ID)
+	s.sendReport(mailing)
+}
+
+// deliver sends the mailing message to the recipient, the document of the mailing is uploaded with the first delivery
+// and its file id is stored for the rest recipients
+//
+// When Telegram responds with the flood error, the limiter is paused for the requested time and the message is sent again
+func (s *MailingService) deliver(mailing *dto.Mailing, recipient dto.MailingRecipient) dto.MailingStatus {
+	text, ok := mailing.Texts[recipient.Locale]
+	if !ok {
+		text = mailing.Texts[localisation.Default]
+	}
+	upload := mailing.FileID == "" && len(mailing.File) > 0
+
+	markup := s.layout.MarkupLocale(recipient.Locale, "core:hide")
+	switch {
+	case mailing.EventID != "":
+		markup = s.layout.MarkupLocale(recipient.Locale, "follow:event", struct {
+			EventID   string
+			ClubID    string
+			Following bool
+		}{
+			EventID:   mailing.EventID,
+			ClubID:    mailing.ClubID,
+			Following: true,
+		})
+	case mailing.ClubID != "":
+		markup = s.layout.MarkupLocale(recipient.Locale, "mailing", struct {
+			ClubID  string
+			Allowed bool
+		}{
+			ClubID:  mailing.ClubID,
+			Allowed: true,
+		})
+	}
+
+	for attempt := 1; ; attempt++ {
+		// The reader of the upload is read by every attempt, so the message is created anew
+		message := utils.NewMediaMessage(mailing.MediaType, mailing.FileID, text)
+		if upload {
+			message = &tele.Document{
+				File:     tele.FromReader(bytes.NewReader(mailing.File)),
+				FileName: mailing.FileName,
+				Caption:  text,
+			}
+		}
+
+		s.limiter.Wait()
+		msg, err := s.bot.Send(tele.ChatID(recipient.UserID), message, markup)
+		if err == nil {
+			if upload && msg.Document != nil {
+				mailing.FileID = msg.Document.FileID
+				mailing.File = nil
+				if errUpdate := s.storage.Update(*mailing); errUpdate != nil {
+					s.logger.Errorf("failed to store uploaded file of mailing %s: %v", mailing.ID, errUpdate)
+				}
+			}
+			return dto.MailingSent
+		}
+
+		var floodErr tele.FloodError
+		switch {
+		case errors.As(err, &floodErr) && attempt < mailingMaxAttempts:
+			retryAfter := time.Duration(floodErr.RetryAfter) * time.Second
+			s.logger.Warnf("flood limit reached while sending mailing %s, retry after %s", mailing.ID, retryAfter)
+			s.limiter.Pause(retryAfter)
+		case isBlockedByRecipient(err):
+			s.logger.Debugf("mailing %s is not delivered to user %d: %v", mailing.ID, recipient.UserID, err)
+			return dto.MailingBlocked
+		default:
+			s.logger.Errorf("failed to send mailing %s to user %d: %v", mailing.ID, recipient.UserID, err)
+			return dto.MailingFailed
+		}
+	}
+}
+
+// sendReport sends the delivery report to the author of the mailing
+func (s *MailingService) sendReport(mailing dto.Mailing) {
+	if mailing.AuthorID == 0 {
+		return
+	}
+
+	report, err := s.storage.GetReport(mailing.ID)
+	if err != nil {
+		s.logger.Errorf("failed to get mailing report (mailing_id=%s): %v", mailing.ID, err)
+		return
+	}
+
+	locale := localisation.Resolve(mailing.AuthorLocale)
+	_, err = s.bot.Send(
+		tele.ChatID(mailing.AuthorID),
+		s.layout.TextLocale(locale, "mailing_report", report),
+		s.layout.MarkupLocale(locale, "core:hide"),
+	)
+	if err != nil {
+		s.logger.Errorf("failed to send mailing report to user %d: %v", mailing.AuthorID, err)
+	}
+}
+
+// isBlockedByRecipient reports whether the message can't be delivered because the user has blocked or never started the bot
+func isBlockedByRecipient(err error) bool {
+	return errors.Is(err, tele.ErrBlockedByUser) ||
+		errors.Is(err, tele.ErrUserIsDeactivated) ||
+		errors.Is(err, tele.ErrNotStartedByUser) ||
+		errors.Is(err, tele.ErrChatNotFound)
+}
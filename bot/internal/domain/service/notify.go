@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/localisation"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
 	"strings"
 	"time"
@@ -25,18 +26,26 @@ type eventStorage interface {
 
 type notificationStorage interface {
 	Create(ctx context.Context, notification *entity.EventNotification) error
-	GetUnnotifiedUsers(ctx context.Context, eventID string, notificationType entity.NotificationType) ([]entity.EventParticipant, error)
+	GetUnnotifiedUsers(ctx context.Context, eventID string, notificationType entity.NotificationType) ([]entity.User, error)
+	DeleteByEventID(ctx context.Context, eventID string) error
 }
 
-type notifyEventParticipantStorage interface {
-	GetByEventID(ctx context.Context, eventID string) ([]entity.EventParticipant, error)
+type notifyUserStorage interface {
+	GetUsersByEventID(ctx context.Context, eventID string) ([]entity.User, error)
+	GetWaitingUsersByEventID(ctx context.Context, eventID string) ([]entity.User, error)
+}
+
+type notifyMailingService interface {
+	Texts(key string, data interface{}) map[string]string
+	Send(mailing dto.Mailing, users []entity.User) (string, error)
 }
 
 type NotifyService struct {
-	clubOwnerService              clubOwnerService
-	eventStorage                  eventStorage
-	notificationStorage           notificationStorage
-	notifyEventParticipantStorage notifyEventParticipantStorage
+	clubOwnerService    clubOwnerService
+	eventStorage        eventStorage
+	notificationStorage notificationStorage
+	notifyUserStorage   notifyUserStorage
+	mailingService      notifyMailingService
 
 	bot    *tele.Bot
 	layout *layout.Layout
@@ -50,16 +59,18 @@ func NewNotifyService(
 	clubOwnerService clubOwnerService,
 	eventStorage eventStorage,
 	notificationStorage notificationStorage,
-	notifyEventParticipantStorage notifyEventParticipantStorage,
+	notifyUserStorage notifyUserStorage,
+	mailingService notifyMailingService,
 ) *NotifyService {
 	return &NotifyService{
-		clubOwnerService:              clubOwnerService,
-		eventStorage:                  eventStorage,
-		notificationStorage:           notificationStorage,
-		notifyEventParticipantStorage: notifyEventParticipantStorage,
-		bot:                           bot,
-		layout:                        layout,
-		logger:                        logger,
+		clubOwnerService:    clubOwnerService,
+		eventStorage:        eventStorage,
+		notificationStorage: notificationStorage,
+		notifyUserStorage:   notifyUserStorage,
+		mailingService:      mailingService,
+		bot:                 bot,
+		layout:              layout,
+		logger:              logger,
 	}
 }
 
@@ -74,6 +85,7 @@ func (s *NotifyService) LogHook(channelID int64, locale string, level zapcore.Le
 	if err != nil {
 		return nil, err
 	}
+	locale = localisation.Resolve(locale)
 	return func(log types.Log) {
 		if log.Level >= level {
 			_, err = s.bot.Send(chat, s.layout.TextLocale(locale, "log", log))
@@ -85,7 +97,9 @@ func (s *NotifyService) LogHook(channelID int64, locale string, level zapcore.Le
 }
 
 // SendClubWarning sends a warning to club owners if they have enabled notifications
-func (s *NotifyService) SendClubWarning(clubID string, what interface{}, opts ...interface{}) error {
+//
+// The warning is rendered from the layout text key with the given data in the language of each owner
+func (s *NotifyService) SendClubWarning(clubID string, key string, data interface{}) error {
 	clubOwners, err := s.clubOwnerService.GetByClubID(context.Background(), clubID)
 	if err != nil {
 		return err
@@ -94,11 +108,15 @@ func (s *NotifyService) SendClubWarning(clubID string, what interface{}, opts ..
 	var errors []error
 	for _, owner := range clubOwners {
 		if owner.Warnings {
+			locale := localisation.Resolve(owner.Localisation)
 			chat, errGetChat := s.bot.ChatByID(owner.UserID)
 			if errGetChat != nil {
 				errors = append(errors, errGetChat)
 			}
-			_, errSend := s.bot.Send(chat, what, opts...)
+			_, errSend := s.bot.Send(chat,
+				s.layout.TextLocale(locale, key, data),
+				s.layout.MarkupLocale(locale, "core:hide"),
+			)
 			if errSend != nil {
 				errors = append(errors, errSend)
 			}
@@ -111,28 +129,54 @@ func (s *NotifyService) SendClubWarning(clubID string, what interface{}, opts ..
 	return nil
 }
 
-func (s *NotifyService) SendEventUpdate(eventID string, what interface{}, opts ...interface{}) error {
-	participants, err := s.notifyEventParticipantStorage.GetByEventID(context.Background(), eventID)
+// SendEventUpdate queues a notification for all participants of the event
+//
+// The notification is rendered from the layout text key with the given data in the language of each participant
+func (s *NotifyService) SendEventUpdate(eventID string, key string, data interface{}) error {
+	participants, err := s.notifyUserStorage.GetUsersByEventID(context.Background(), eventID)
 	if err != nil {
 		return err
 	}
 
-	var errors []error
-	for _, participant := range participants {
-		chat, errGetChat := s.bot.ChatByID(participant.UserID)
-		if errGetChat != nil {
-			errors = append(errors, errGetChat)
-		}
-		_, errSend := s.bot.Send(chat, what, opts...)
-		if errSend != nil {
-			errors = append(errors, errSend)
-		}
+	_, err = s.mailingService.Send(dto.Mailing{
+		Texts: s.mailingService.Texts(key, data),
+	}, participants)
+	return err
+}
+
+// SendEventDocument sends the update to the participants of the event with the document attached,
+// the document is uploaded to Telegram with the first delivery
+func (s *NotifyService) SendEventDocument(eventID string, key string, data interface{}, file []byte, fileName string) error {
+	participants, err := s.notifyUserStorage.GetUsersByEventID(context.Background(), eventID)
+	if err != nil {
+		return err
 	}
 
-	if len(errors) > 0 {
-		return errors[0]
+	_, err = s.mailingService.Send(dto.Mailing{
+		Texts:     s.mailingService.Texts(key, data),
+		MediaType: "document",
+		File:      file,
+		FileName:  fileName,
+	}, participants)
+	return err
+}
+
+// SendEventWaiting queues a notification for the users on the waitlist of the event and with pending applications to it
+func (s *NotifyService) SendEventWaiting(eventID string, key string, data interface{}) error {
+	users, err := s.notifyUserStorage.GetWaitingUsersByEventID(context.Background(), eventID)
+	if err != nil {
+		return err
 	}
-	return nil
+
+	_, err = s.mailingService.Send(dto.Mailing{
+		Texts: s.mailingService.Texts(key, data),
+	}, users)
+	return err
+}
+
+// ResetReminders forgets the reminders sent about the event, they are sent again by the scheduler at the new time of the event
+func (s *NotifyService) ResetReminders(ctx context.Context, eventID string) error {
+	return s.notificationStorage.DeleteByEventID(ctx, eventID)
 }
 
 // StartNotifyScheduler starts the scheduler for sending notifications
@@ -149,15 +193,13 @@ func (s *NotifyService) StartNotifyScheduler() {
 	}()
 }
 
-// checkAndNotify checks for events starting in the next 25 hours (to cover both day and hour notifications)
-//
-// NOTE: localisation is hardcoded for now (ru)
+// checkAndNotify checks for events starting within the largest reminder offset and sends the due reminders
 func (s *NotifyService) checkAndNotify(ctx context.Context) {
-	s.logger.Debugf("Checking for events starting in the next 25 hours")
+	maxOffset := entity.MaxReminderOffset()
+	s.logger.Debugf("Checking for events starting in the next %s", maxOffset)
 	now := time.Now().In(location.Location())
 
-	// Get events starting in the next 25 hours (to cover both day and hour notifications)
-	events, err := s.eventStorage.GetUpcomingEvents(ctx, now.Add(25*time.Hour))
+	events, err := s.eventStorage.GetUpcomingEvents(ctx, now.Add(maxOffset))
 	if err != nil {
 		s.logger.Errorf("failed to get upcoming events: %v", err)
 		return
@@ -167,66 +209,83 @@ func (s *NotifyService) checkAndNotify(ctx context.Context) {
 		timeUntilStart := event.StartTime.Sub(now)
 		s.logger.Debugf("Event %s starts in %s", event.ID, timeUntilStart)
 
-		// Check for day notification (between 23-24 hours before start)
-		if timeUntilStart >= 23*time.Hour && timeUntilStart <= 24*time.Hour {
-			s.logger.Infof("Sending day notification for event (event_id=%s)", event.ID)
-			s.sendNotifications(ctx, event, entity.NotificationTypeDay)
-		}
+		for _, reminder := range event.GetReminders() {
+			notificationType := entity.NotificationType(reminder)
+			if !notificationType.IsReminder() {
+				continue
+			}
 
-		// Check for hour notification (between 55-60 minutes before start)
-		if timeUntilStart >= 55*time.Minute && timeUntilStart <= 60*time.Minute {
-			s.logger.Infof("Sending hour notification for event (event_id=%s)", event.ID)
-			s.sendNotifications(ctx, event, entity.NotificationTypeHour)
+			offset := notificationType.Offset()
+			if timeUntilStart <= offset && timeUntilStart >= offset-reminderWindow(offset) {
+				s.logger.Infof("Sending %s notification for event (event_id=%s)", notificationType, event.ID)
+				s.sendNotifications(ctx, event, notificationType)
+			}
 		}
 	}
 }
 
+// reminderWindow returns how long after the reminder time the reminder is still sent.
+//
+// Users registered after the window has passed don't receive the reminder,
+// so the user registered an hour before the start doesn't get the reminder sent a day before
+func reminderWindow(offset time.Duration) time.Duration {
+	return max(offset/12, 5*time.Minute)
+}
+
 // sendNotifications sends notifications to users that have not been notified
 func (s *NotifyService) sendNotifications(ctx context.Context, event entity.Event, notificationType entity.NotificationType) {
 	// Get users who haven't been notified yet
-	participants, err := s.notificationStorage.GetUnnotifiedUsers(ctx, event.ID, notificationType)
+	users, err := s.notificationStorage.GetUnnotifiedUsers(ctx, event.ID, notificationType)
 	if err != nil {
 		s.logger.Errorf("failed to get unnotified users for event %s: %v", event.ID, err)
 		return
 	}
 
-	for _, participant := range participants {
+	for _, user := range users {
 		s.logger.Infof(
 			"Sending %s notification to user (user_id=%d, event_id=%s, notification_type=%s)",
 			notificationType,
-			participant.UserID,
+			user.ID,
 			event.ID,
 			notificationType,
 		)
 
 		// Send notification
-		chat, errGetChat := s.bot.ChatByID(participant.UserID)
+		locale := localisation.Resolve(user.Localisation)
+		chat, errGetChat := s.bot.ChatByID(user.ID)
 		if errGetChat != nil {
-			s.logger.Errorf("failed to get chat for user %d: %v", participant.UserID, errGetChat)
+			s.logger.Errorf("failed to get chat for user %d: %v", user.ID, errGetChat)
 			continue
 		}
 
-		var messageKey string
-		switch notificationType {
-		case entity.NotificationTypeDay:
-			messageKey = "event_notification_day"
-		case entity.NotificationTypeHour:
-			messageKey = "event_notification_hour"
-		}
-
 		_, errSend := s.bot.Send(chat,
-			s.layout.TextLocale("ru", messageKey, event),
-			s.layout.MarkupLocale("ru", "core:hide"),
+			s.layout.TextLocale(locale, "event_notification_reminder", struct {
+				Name      string
+				Location  string
+				StartTime string
+				In        string
+			}{
+				Name:      event.Name,
+				Location:  event.Location,
+				StartTime: event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+				In:        s.layout.TextLocale(locale, "reminder_in_"+notificationType.String()),
+			}),
+			s.layout.MarkupLocale(locale, "event_notification_reminder", struct {
+				EventID string
+				Muted   bool
+			}{
+				EventID: event.ID,
+			}),
 		)
 		if errSend != nil {
-			s.logger.Errorf("failed to send notification to user %d: %v", participant.UserID, errSend)
+			s.logger.Errorf("failed to send notification to user %d: %v", user.ID, errSend)
 			continue
 		}
 
 		// Record that notification was sent
 		notification := &entity.EventNotification{
 			EventID: event.ID,
-			UserID:  participant.UserID,
+			UserID:  user.ID,
 			Type:    notificationType,
 		}
 
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/pdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// passList forms the pass list of the guests in the format and with the columns of the recipient,
+// a guest is listed once unless the list has the event columns
+func (s *EventParticipantService) passList(recipient entity.PassRecipient, guests []dto.PassGuest) (*bytes.Buffer, error) {
+	perEvent := false
+	header := make([]string, len(recipient.Columns))
+	for i, column := range recipient.Columns {
+		header[i] = s.layout.TextLocale(s.passLocale, "pass_column_"+strings.ReplaceAll(string(column), "-", "_"))
+		perEvent = perEvent || column.IsEventColumn()
+	}
+
+	seen := make(map[string]bool)
+	rows := make([][]string, 0, len(guests))
+	for _, guest := range guests {
+		key := fmt.Sprint(guest.UserID)
+		if guest.GuestID != "" {
+			key = guest.GuestID
+		} else if perEvent {
+			key += " " + guest.EventID
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		row := make([]string, len(recipient.Columns))
+		for i, column := range recipient.Columns {
+			row[i] = passCell(column, guest)
+		}
+		rows = append(rows, row)
+	}
+
+	switch recipient.Format {
+	case entity.PassFormatCSV:
+		return passListToCSV(header, rows)
+	case entity.PassFormatPDF:
+		return pdf.Table(header, rows)
+	default:
+		return passListToXLSX(header, rows)
+	}
+}
+
+func passCell(column entity.PassColumn, guest dto.PassGuest) string {
+	switch column {
+	case entity.PassColumnLastName:
+		return guest.LastName
+	case entity.PassColumnFirstName:
+		return guest.FirstName
+	case entity.PassColumnMiddleName:
+		return guest.MiddleName
+	case entity.PassColumnFIO:
+		return guest.FIO
+	case entity.PassColumnDate:
+		return guest.StartTime.In(location.Location()).Format("02.01.2006")
+	case entity.PassColumnTime:
+		return guest.StartTime.In(location.Location()).Format("15:04")
+	case entity.PassColumnEvent:
+		return guest.EventName
+	case entity.PassColumnClub:
+		return guest.ClubName
+	case entity.PassColumnPhone:
+		return guest.Phone
+	default:
+		return ""
+	}
+}
+
+func passListToXLSX(header []string, rows [][]string) (*bytes.Buffer, error) {
+	f := excelize.NewFile()
+
+	sheet := "Sheet1"
+	for i, row := range append([][]string{header}, rows...) {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(row))
+		for j, value := range row {
+			values[j] = value
+		}
+		_ = f.SetSheetRow(sheet, cell, &values)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
+
+func passListToCSV(header []string, rows [][]string) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	// the BOM makes Excel read the file as UTF-8
+	buf.WriteString("\ufeff")
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
+
+// excludedGuestsReport returns the report about the guests that can't be put into the pass list of the venue
+func (s *EventParticipantService) excludedGuestsReport(venue entity.Venue, guests []dto.PassGuest) string {
+	lines := make([]string, 0, len(guests))
+	for _, guest := range guests {
+		lines = append(lines, s.layout.TextLocale(s.passLocale, "pass_excluded_guest", struct {
+			UserID int64
+			FIO    string
+			Event  string
+			Date   string
+		}{
+			UserID: guest.UserID,
+			FIO:    guest.FIO,
+			Event:  guest.EventName,
+			Date:   guest.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+		}))
+	}
+
+	return s.layout.TextLocale(s.passLocale, "pass_excluded_guests", struct {
+		Venue  string
+		Guests string
+	}{
+		Venue:  venue.Name,
+		Guests: strings.Join(lines, "\n"),
+	})
+}
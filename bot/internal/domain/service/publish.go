@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/banner"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+)
+
+type publishEventStorage interface {
+	GetDueDrafts(ctx context.Context, now time.Time) ([]entity.Event, error)
+	Publish(ctx context.Context, id string) error
+	UpdatePublication(ctx context.Context, id string, publishAt *time.Time, announce bool) error
+}
+
+type publishClubStorage interface {
+	Get(ctx context.Context, id string) (*entity.Club, error)
+}
+
+type publishUserStorage interface {
+	GetUsersByClubID(ctx context.Context, clubID string) ([]entity.User, error)
+}
+
+type publishFollowerStorage interface {
+	GetFollowers(ctx context.Context, clubID string) ([]entity.User, error)
+}
+
+type publishMailingService interface {
+	Texts(key string, data interface{}) map[string]string
+	Send(mailing dto.Mailing, users []entity.User) (string, error)
+}
+
+// PublishService publishes the draft events, sends their cards to the club followers
+// and announces them to the club users
+type PublishService struct {
+	logger *types.Logger
+
+	eventStorage    publishEventStorage
+	clubStorage     publishClubStorage
+	userStorage     publishUserStorage
+	followerStorage publishFollowerStorage
+	mailingService  publishMailingService
+
+	botName string
+}
+
+func NewPublishService(
+	logger *types.Logger,
+	eventStorage publishEventStorage,
+	clubStorage publishClubStorage,
+	userStorage publishUserStorage,
+	followerStorage publishFollowerStorage,
+	mailingService publishMailingService,
+	botName string,
+) *PublishService {
+	return &PublishService{
+		logger: logger,
+
+		eventStorage:    eventStorage,
+		clubStorage:     clubStorage,
+		userStorage:     userStorage,
+		followerStorage: followerStorage,
+		mailingService:  mailingService,
+
+		botName: botName,
+	}
+}
+
+// Publish makes the draft visible to the users, sends its card to the club followers
+// and the announcement if the club owner has turned it on
+//
+// gorm.ErrRecordNotFound is returned if the event has already been published
+func (s *PublishService) Publish(ctx context.Context, event *entity.Event) error {
+	if err := s.eventStorage.Publish(ctx, event.ID); err != nil {
+		return err
+	}
+	event.IsDraft = false
+	event.PublishAt = nil
+	s.logger.Infof("Event published (event_id=%s, club_id=%s)", event.ID, event.ClubID)
+
+	return s.NotifyFollowers(ctx, event)
+}
+
+// UpdatePublication saves the publish time and the announcement setting of the draft, the other fields are left as they are
+//
+// gorm.ErrRecordNotFound is returned if the event has already been published
+func (s *PublishService) UpdatePublication(ctx context.Context, event *entity.Event) error {
+	return s.eventStorage.UpdatePublication(ctx, event.ID, event.PublishAt, event.Announce)
+}
+
+// NotifyFollowers queues the card of the published event for the club followers who are allowed to register on it,
+// the announcement is queued for the rest of the club users if the club owner has turned it on
+func (s *PublishService) NotifyFollowers(ctx context.Context, event *entity.Event) error {
+	club := &event.Club
+	if club.ID == "" {
+		var err error
+		club, err = s.clubStorage.Get(ctx, event.ClubID)
+		if err != nil {
+			return err
+		}
+	}
+
+	followers, err := s.followerStorage.GetFollowers(ctx, event.ClubID)
+	if err != nil {
+		return err
+	}
+
+	followerIDs := make(map[int64]struct{}, len(followers))
+	recipients := make([]entity.User, 0, len(followers))
+	for _, follower := range followers {
+		followerIDs[follower.ID] = struct{}{}
+		if slices.Contains(event.AllowedRoles, follower.Role.String()) {
+			recipients = append(recipients, follower)
+		}
+	}
+
+	if len(recipients) > 0 {
+		mailing := dto.Mailing{
+			ClubID:  event.ClubID,
+			EventID: event.ID,
+			Texts: s.mailingService.Texts("event_card", struct {
+				ClubName        string
+				Name            string
+				Description     string
+				StartTime       string
+				Location        string
+				RegistrationEnd string
+			}{
+				ClubName:        club.Name,
+				Name:            event.Name,
+				Description:     event.Description,
+				StartTime:       event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+				Location:        event.Location,
+				RegistrationEnd: event.RegistrationEnd.In(location.Location()).Format("02.01.2006 15:04"),
+			}),
+		}
+		// The card is edited into the event view on registration, so it is sent with the same banner
+		if banner.Events.FileID != "" {
+			mailing.MediaType = "photo"
+			mailing.FileID = banner.Events.FileID
+		}
+		if _, err = s.mailingService.Send(mailing, recipients); err != nil {
+			return err
+		}
+		s.logger.Infof("Event cards queued (event_id=%s, club_id=%s, recipients=%d)", event.ID, event.ClubID, len(recipients))
+	}
+
+	if !event.Announce {
+		return nil
+	}
+	return s.announce(ctx, event, club, followerIDs)
+}
+
+// StartPublishScheduler starts the scheduler that publishes the drafts when their publish time comes
+func (s *PublishService) StartPublishScheduler() {
+	s.logger.Info("Starting publish scheduler")
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx := context.Background()
+			s.publishDue(ctx)
+		}
+	}()
+}
+
+// publishDue publishes the drafts whose publish time has come
+func (s *PublishService) publishDue(ctx context.Context) {
+	events, err := s.eventStorage.GetDueDrafts(ctx, time.Now())
+	if err != nil {
+		s.logger.Errorf("failed to get due drafts: %v", err)
+		return
+	}
+
+	for i := range events {
+		if err = s.Publish(ctx, &events[i]); err != nil {
+			s.logger.Errorf("failed to publish event (event_id=%s): %v", events[i].ID, err)
+		}
+	}
+}
+
+// announce queues the event announcement for the club users who are allowed to register on the event
+// and haven't turned off the club mailings, the followers are skipped as they get the event card instead
+func (s *PublishService) announce(ctx context.Context, event *entity.Event, club *entity.Club, followerIDs map[int64]struct{}) error {
+	users, err := s.userStorage.GetUsersByClubID(ctx, event.ClubID)
+	if err != nil {
+		return err
+	}
+
+	recipients := make([]entity.User, 0, len(users))
+	for _, user := range users {
+		if _, ok := followerIDs[user.ID]; ok {
+			continue
+		}
+		if user.IsMailingAllowed(event.ClubID) && slices.Contains(event.AllowedRoles, user.Role.String()) {
+			recipients = append(recipients, user)
+		}
+	}
+
+	mailing := dto.Mailing{
+		ClubID: event.ClubID,
+		Texts: s.mailingService.Texts("event_announcement", struct {
+			ClubName  string
+			Name      string
+			StartTime string
+			Location  string
+			Link      string
+		}{
+			ClubName:  club.Name,
+			Name:      event.Name,
+			StartTime: event.StartTime.In(location.Location()).Format("02.01.2006 15:04"),
+			Location:  event.Location,
+			Link:      event.Link(s.botName),
+		}),
+	}
+	if _, err = s.mailingService.Send(mailing, recipients); err != nil {
+		return err
+	}
+	s.logger.Infof("Event announcement queued (event_id=%s, club_id=%s, recipients=%d)", event.ID, event.ClubID, len(recipients))
+	return nil
+}
@@ -3,7 +3,15 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 	qr "github.com/Badsnus/cu-clubs-bot/bot/pkg/qrcode"
 	"github.com/google/uuid"
@@ -27,13 +35,22 @@ type QrService struct {
 	qrChat       *tele.Chat
 	qrCFG        qr.Config
 	botName      string
+
+	eventQRSecret []byte
+	eventQRPeriod time.Duration
 }
 
-func NewQrService(bot *tele.Bot, qrCFG qr.Config, userService qrUserService, eventService qrEventService, qrChatID int64, logoPath string) (*QrService, error) {
+func NewQrService(bot *tele.Bot, qrCFG qr.Config, userService qrUserService, eventService qrEventService, qrChatID int64, logoPath string, eventQRSecret string, eventQRPeriod time.Duration) (*QrService, error) {
 	chat, err := bot.ChatByID(qrChatID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get qr chat: %v", err)
 	}
+	if eventQRSecret == "" {
+		return nil, fmt.Errorf("event qr secret is not set")
+	}
+	if eventQRPeriod <= 0 {
+		return nil, fmt.Errorf("event qr period must be positive")
+	}
 	qrCFG.LogoPath = logoPath
 	return &QrService{
 		userService:  userService,
@@ -42,6 +59,9 @@ func NewQrService(bot *tele.Bot, qrCFG qr.Config, userService qrUserService, eve
 		qrChat:       chat,
 		qrCFG:        qrCFG,
 		botName:      bot.Me.Username,
+
+		eventQRSecret: []byte(eventQRSecret),
+		eventQRPeriod: eventQRPeriod,
 	}, nil
 }
 
@@ -105,46 +125,62 @@ func (s *QrService) RevokeUserQR(ctx context.Context, userID int64) error {
 	return nil
 }
 
-func (s *QrService) GetEventQR(ctx context.Context, eventID string) (qr tele.File, err error) {
+// GetEventQR generates the QR code of the event with a signed link that is valid for two rotation periods,
+// so the code scanned right before the rotation is still accepted
+func (s *QrService) GetEventQR(ctx context.Context, eventID string) ([]byte, error) {
 	event, err := s.eventService.Get(ctx, eventID)
 	if err != nil {
-		return qr, err
+		return nil, err
 	}
-	if event.QRFileID != "" {
-		qr, err = s.bot.FileByID(event.QRFileID)
+	if event.QRCodeID == "" {
+		event.QRCodeID = uuid.New().String()
+		_, err = s.eventService.Update(ctx, event)
 		if err != nil {
-			return qr, err
+			return nil, err
 		}
-		return qr, nil
 	}
 
-	qrCodeID := uuid.New().String()
-	link := fmt.Sprintf("https://t.me/%s?start=eventQR_%s", s.botName, qrCodeID)
+	link := fmt.Sprintf("https://t.me/%s?start=eventQR_%s", s.botName, s.eventQRToken(event.QRCodeID, time.Now().Add(2*s.eventQRPeriod)))
 	cfg := s.qrCFG
 	cfg.Content = link
-	qrData, err := cfg.Generate()
-	if err != nil {
-		return qr, err
+	return cfg.Generate()
+}
+
+// EventQRPeriod returns how often the event QR code should be regenerated
+func (s *QrService) EventQRPeriod() time.Duration {
+	return s.eventQRPeriod
+}
+
+// ParseEventQRToken checks the signature and the expiration of the event QR token and returns the QR code id of the event
+func (s *QrService) ParseEventQRToken(token string) (string, error) {
+	parts := strings.Split(token, "-")
+	if len(parts) != 3 {
+		return "", errorz.ErrQRExpired
 	}
 
-	tempQR := tele.FromReader(bytes.NewReader(qrData))
-	qrMsg, err := s.bot.Send(s.qrChat, &tele.Photo{
-		File: tempQR,
-	})
+	qrCodeID, err := uuid.Parse(parts[0])
 	if err != nil {
-		return qr, err
+		return "", errorz.ErrQRExpired
 	}
-	event.QRFileID = qrMsg.Photo.FileID
-	event.QRCodeID = qrCodeID
-	_, err = s.eventService.Update(ctx, event)
+	expiresAt, err := strconv.ParseInt(parts[1], 36, 64)
 	if err != nil {
-		return qr, err
+		return "", errorz.ErrQRExpired
 	}
-
-	qr, err = s.bot.FileByID(qrMsg.Photo.FileID)
-	if err != nil {
-		return qr, err
+	if !hmac.Equal([]byte(token), []byte(s.eventQRToken(qrCodeID.String(), time.Unix(expiresAt, 0)))) {
+		return "", errorz.ErrQRExpired
 	}
+	if time.Now().After(time.Unix(expiresAt, 0)) {
+		return "", errorz.ErrQRExpired
+	}
+	return qrCodeID.String(), nil
+}
 
-	return qr, err
+// eventQRToken builds the token of the event QR link, it has to fit the 64 characters of the start parameter
+// together with the payload type, so the uuid is written without dashes and the signature is truncated
+func (s *QrService) eventQRToken(qrCodeID string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 36)
+	mac := hmac.New(sha256.New, s.eventQRSecret)
+	mac.Write([]byte(qrCodeID + ":" + expires))
+	signature := hex.EncodeToString(mac.Sum(nil))[:12]
+	return strings.ReplaceAll(qrCodeID, "-", "") + "-" + expires + "-" + signature
 }
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+)
+
+func TestQrServiceParseEventQRToken(t *testing.T) {
+	const qrCodeID = "0b6f3d2a-7c1e-4f5a-9d8b-2e4c6a8f0b1d"
+	s := &QrService{eventQRSecret: []byte("secret")}
+	other := &QrService{eventQRSecret: []byte("another secret")}
+	valid := s.eventQRToken(qrCodeID, time.Now().Add(time.Minute))
+
+	tests := []struct {
+		name    string
+		token   string
+		want    string
+		wantErr error
+	}{
+		{name: "valid", token: valid, want: qrCodeID},
+		{name: "expired", token: s.eventQRToken(qrCodeID, time.Now().Add(-time.Minute)), wantErr: errorz.ErrQRExpired},
+		{name: "signed with another secret", token: other.eventQRToken(qrCodeID, time.Now().Add(time.Minute)), wantErr: errorz.ErrQRExpired},
+		{name: "tampered signature", token: valid[:len(valid)-1] + "x", wantErr: errorz.ErrQRExpired},
+		{name: "prolonged expiration", token: strings.Replace(valid, "-", "-z", 1), wantErr: errorz.ErrQRExpired},
+		{name: "not a token", token: "start", wantErr: errorz.ErrQRExpired},
+		{name: "invalid id", token: "id-" + strings.SplitN(valid, "-", 2)[1], wantErr: errorz.ErrQRExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ParseEventQRToken(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseEventQRToken(%q) error = %v, want %v", tt.token, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseEventQRToken(%q) = %q, want %q", tt.token, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQrServiceEventQRTokenLength(t *testing.T) {
+	token := (&QrService{eventQRSecret: []byte("secret")}).eventQRToken("0b6f3d2a-7c1e-4f5a-9d8b-2e4c6a8f0b1d", time.Now().Add(time.Hour))
+	// the start parameter is limited to 64 characters, together with the "eventQR_" payload type
+	if len(token)+len("eventQR_") > 64 {
+		t.Errorf("eventQRToken() = %q is %d characters long", token, len(token))
+	}
+}
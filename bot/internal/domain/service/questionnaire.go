@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/xuri/excelize/v2"
+	"gopkg.in/telebot.v3/layout"
+)
+
+type EventAnswersStorage interface {
+	Upsert(ctx context.Context, answers *entity.EventAnswers) (*entity.EventAnswers, error)
+	GetByEventID(ctx context.Context, eventID string) ([]entity.EventAnswers, error)
+}
+
+type questionnaireUserStorage interface {
+	GetUsersByEventID(ctx context.Context, eventID string) ([]entity.User, error)
+}
+
+type QuestionnaireService struct {
+	layout *layout.Layout
+
+	storage     EventAnswersStorage
+	userStorage questionnaireUserStorage
+}
+
+func NewQuestionnaireService(layout *layout.Layout, storage EventAnswersStorage, userStorage questionnaireUserStorage) *QuestionnaireService {
+	return &QuestionnaireService{
+		layout: layout,
+
+		storage:     storage,
+		userStorage: userStorage,
+	}
+}
+
+func (s *QuestionnaireService) SaveAnswers(ctx context.Context, eventID string, userID int64, answers []entity.QuestionAnswer) error {
+	_, err := s.storage.Upsert(ctx, &entity.EventAnswers{
+		EventID: eventID,
+		UserID:  userID,
+		Answers: answers,
+	})
+	return err
+}
+
+// AnswersToXLSX generates the table with the participants of the event and their answers to the questionnaire.
+//
+// The columns follow the current questions of the event, the answers to the removed questions are put after them
+func (s *QuestionnaireService) AnswersToXLSX(ctx context.Context, event *entity.Event, locale string) (*bytes.Buffer, error) {
+	users, err := s.userStorage.GetUsersByEventID(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventAnswers, err := s.storage.GetByEventID(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions := make([]string, 0, len(event.Questions))
+	columns := make(map[string]int)
+	addQuestion := func(question string) {
+		if _, ok := columns[question]; !ok {
+			columns[question] = len(questions)
+			questions = append(questions, question)
+		}
+	}
+	for _, question := range event.Questions {
+		addQuestion(question.Text)
+	}
+
+	answersByUser := make(map[int64][]entity.QuestionAnswer, len(eventAnswers))
+	for _, answers := range eventAnswers {
+		answersByUser[answers.UserID] = answers.Answers
+		for _, answer := range answers.Answers {
+			addQuestion(answer.Question)
+		}
+	}
+
+	header := []interface{}{
+		s.layout.TextLocale(locale, "xlsx_answers_fio"),
+		s.layout.TextLocale(locale, "xlsx_answers_username"),
+	}
+	for _, question := range questions {
+		header = append(header, question)
+	}
+
+	f := excelize.NewFile()
+	sheet := "Sheet1"
+	_ = f.SetSheetRow(sheet, "A1", &header)
+	for i, user := range users {
+		row := make([]interface{}, 2+len(questions))
+		row[0] = user.FIO
+		row[1] = user.Username
+		for _, answer := range answersByUser[user.ID] {
+			row[2+columns[answer.Question]] = strings.Join(answer.Values, ", ")
+		}
+
+		cell, errCell := excelize.CoordinatesToCellName(1, i+2)
+		if errCell != nil {
+			return nil, errCell
+		}
+		_ = f.SetSheetRow(sheet, cell, &row)
+	}
+
+	var buf bytes.Buffer
+	if err = f.Write(&buf); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+	"gorm.io/gorm"
+)
+
+// RegistrationOutcome is the way the user gets on the event
+type RegistrationOutcome int
+
+const (
+	// RegistrationParticipant - the user is registered on the event
+	RegistrationParticipant RegistrationOutcome = iota
+	// RegistrationApplication - the application of the user is sent to the club owners
+	RegistrationApplication
+	// RegistrationWaitlist - the user is put on the waitlist of the event
+	RegistrationWaitlist
+)
+
+type registrationUserService interface {
+	Get(ctx context.Context, userID int64) (*entity.User, error)
+}
+
+type registrationParticipantService interface {
+	Register(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
+	Unregister(ctx context.Context, event *entity.Event, userID int64) error
+	CountByEventID(ctx context.Context, eventID string) (int, error)
+}
+
+type registrationWaitlistService interface {
+	Join(ctx context.Context, eventID string, userID int64) (*entity.EventWaitlist, error)
+	CountByEventID(ctx context.Context, eventID string) (int, error)
+	PromoteNext(ctx context.Context, eventID string) error
+}
+
+type registrationApplicationService interface {
+	Apply(ctx context.Context, eventID string, userID int64) (*entity.EventApplication, error)
+}
+
+type registrationQuestionnaireService interface {
+	SaveAnswers(ctx context.Context, eventID string, userID int64, answers []entity.QuestionAnswer) error
+}
+
+type registrationVenueStorage interface {
+	Get(ctx context.Context, id string) (*entity.Venue, error)
+}
+
+type registrationNotifyService interface {
+	SendClubWarning(clubID string, key string, data interface{}) error
+}
+
+// RegistrationService registers the users on the events and cancels their registrations,
+// it is shared by all the handlers that register users
+type RegistrationService struct {
+	logger *types.Logger
+
+	userService          registrationUserService
+	participantService   registrationParticipantService
+	waitlistService      registrationWaitlistService
+	applicationService   registrationApplicationService
+	questionnaireService registrationQuestionnaireService
+	notifyService        registrationNotifyService
+	venueStorage         registrationVenueStorage
+}
+
+func NewRegistrationService(
+	logger *types.Logger,
+	userService registrationUserService,
+	participantService registrationParticipantService,
+	waitlistService registrationWaitlistService,
+	applicationService registrationApplicationService,
+	questionnaireService registrationQuestionnaireService,
+	notifyService registrationNotifyService,
+	venueStorage registrationVenueStorage,
+) *RegistrationService {
+	return &RegistrationService{
+		logger: logger,
+
+		userService:          userService,
+		participantService:   participantService,
+		waitlistService:      waitlistService,
+		applicationService:   applicationService,
+		questionnaireService: questionnaireService,
+		notifyService:        notifyService,
+		venueStorage:         venueStorage,
+	}
+}
+
+// Check returns the way the user would get on the event now without registering them.
+//
+// It returns errorz.ErrEventUnavailable, errorz.ErrRegistrationEnded, errorz.ErrRoleNotAllowed or errorz.ErrNoSeats
+// if the user can't get on the event,
+// and errorz.ErrPhoneRequired if the pass office of the venue needs the phone the user hasn't given yet
+func (s *RegistrationService) Check(ctx context.Context, event *entity.Event, userID int64) (RegistrationOutcome, error) {
+	outcome, _, err := s.check(ctx, event, userID)
+	return outcome, err
+}
+
+// Register registers the user on the event, applies for it if the event requires approval
+// or puts the user on the waitlist if there are no free seats, the answers to the questionnaire are saved with it.
+//
+// It returns the same errors as Check if the user can't get on the event
+func (s *RegistrationService) Register(ctx context.Context, event *entity.Event, userID int64, answers []entity.QuestionAnswer) (RegistrationOutcome, error) {
+	outcome, participantsCount, err := s.check(ctx, event, userID)
+	if err != nil {
+		return outcome, err
+	}
+
+	switch outcome {
+	case RegistrationApplication:
+		if _, err = s.applicationService.Apply(ctx, event.ID, userID); err != nil {
+			return outcome, err
+		}
+	case RegistrationWaitlist:
+		if _, err = s.waitlistService.Join(ctx, event.ID, userID); err != nil {
+			return outcome, err
+		}
+	default:
+		if _, err = s.participantService.Register(ctx, event.ID, userID); err != nil {
+			return outcome, err
+		}
+	}
+
+	if len(answers) > 0 {
+		if err = s.questionnaireService.SaveAnswers(ctx, event.ID, userID, answers); err != nil {
+			s.logger.Errorf("(user: %d) error while save questionnaire answers (event_id=%s): %v", userID, event.ID, err)
+		}
+	}
+
+	switch outcome {
+	case RegistrationApplication:
+		if err = s.notifyService.SendClubWarning(event.ClubID, "event_application_warning", event); err != nil {
+			s.logger.Errorf("(user: %d) error while send event application warning: %v", userID, err)
+		}
+	case RegistrationParticipant:
+		s.warnParticipantsCount(event, participantsCount+1)
+	}
+
+	return outcome, nil
+}
+
+// Cancel cancels the registration of the user, the freed seat is offered to the waitlist
+// and the club owners are warned if the participants count drops below the expected one.
+//
+// It returns errorz.ErrCancellationClosed if the registration can't be cancelled anymore
+func (s *RegistrationService) Cancel(ctx context.Context, event *entity.Event, userID int64) error {
+	// The count includes the guests, they are removed with the user, so the count can drop by more than one
+	countBefore, errBefore := s.participantService.CountByEventID(ctx, event.ID)
+	if errBefore != nil {
+		s.logger.Errorf("(user: %d) error while get participants count (event_id=%s): %v", userID, event.ID, errBefore)
+	}
+
+	if err := s.participantService.Unregister(ctx, event, userID); err != nil {
+		return err
+	}
+
+	if err := s.waitlistService.PromoteNext(ctx, event.ID); err != nil {
+		s.logger.Errorf("(user: %d) error while promote event waitlist (event_id=%s): %v", userID, event.ID, err)
+	}
+
+	if errBefore != nil {
+		return nil
+	}
+	countAfter, err := s.participantService.CountByEventID(ctx, event.ID)
+	if err != nil {
+		s.logger.Errorf("(user: %d) error while get participants count (event_id=%s): %v", userID, event.ID, err)
+		return nil
+	}
+	if countBefore >= event.ExpectedParticipants && countAfter < event.ExpectedParticipants {
+		err = s.notifyService.SendClubWarning(event.ClubID,
+			"expected_participants_dropped_warning", struct {
+				Name              string
+				ParticipantsCount int
+			}{
+				Name:              event.Name,
+				ParticipantsCount: countAfter,
+			},
+		)
+		if err != nil {
+			s.logger.Errorf("(user: %d) error while send expected participants dropped warning: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// PhoneRequired checks if the pass office of the venue of the event needs the phones of the guests
+func (s *RegistrationService) PhoneRequired(ctx context.Context, event *entity.Event) (bool, error) {
+	if event.VenueID == nil {
+		return false, nil
+	}
+
+	venue, err := s.venueStorage.Get(ctx, *event.VenueID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return venue.RequiresPhone(), nil
+}
+
+// check returns the way the user would get on the event and the current participants count
+func (s *RegistrationService) check(ctx context.Context, event *entity.Event, userID int64) (RegistrationOutcome, int, error) {
+	user, err := s.userService.Get(ctx, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if event.IsCancelled() || event.IsDraft {
+		return 0, 0, errorz.ErrEventUnavailable
+	}
+	if !event.RegistrationEnd.After(time.Now()) {
+		return 0, 0, errorz.ErrRegistrationEnded
+	}
+	if !slices.Contains(event.AllowedRoles, string(user.Role)) {
+		return 0, 0, errorz.ErrRoleNotAllowed
+	}
+
+	// The students don't need passes
+	if user.Role != entity.Student && user.Phone == "" {
+		phoneRequired, err := s.PhoneRequired(ctx, event)
+		if err != nil {
+			return 0, 0, err
+		}
+		if phoneRequired {
+			return 0, 0, errorz.ErrPhoneRequired
+		}
+	}
+
+	participantsCount, err := s.participantService.CountByEventID(ctx, event.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if event.RequiresApproval {
+		// Events with approval have no waitlist, the seats are filled by the club owners
+		if event.MaxParticipants > 0 && participantsCount >= event.MaxParticipants {
+			return 0, participantsCount, errorz.ErrNoSeats
+		}
+		return RegistrationApplication, participantsCount, nil
+	}
+
+	waitlistCount, err := s.waitlistService.CountByEventID(ctx, event.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Seats reserved by the waitlist are not available for direct registration
+	if event.MaxParticipants == 0 || participantsCount+waitlistCount < event.MaxParticipants {
+		return RegistrationParticipant, participantsCount, nil
+	}
+	return RegistrationWaitlist, participantsCount, nil
+}
+
+// warnParticipantsCount warns the club owners when the expected or max participants count is reached
+func (s *RegistrationService) warnParticipantsCount(event *entity.Event, participantsCount int) {
+	data := struct {
+		Name              string
+		ParticipantsCount int
+	}{
+		Name:              event.Name,
+		ParticipantsCount: participantsCount,
+	}
+
+	if participantsCount == event.ExpectedParticipants {
+		if err := s.notifyService.SendClubWarning(event.ClubID, "expected_participants_reached_warning", data); err != nil {
+			s.logger.Errorf("error while send expected participants reached warning (event_id=%s): %v", event.ID, err)
+		}
+	}
+	if participantsCount == event.MaxParticipants {
+		if err := s.notifyService.SendClubWarning(event.ClubID, "max_participants_reached_warning", data); err != nil {
+			s.logger.Errorf("error while send max participants reached warning (event_id=%s): %v", event.ID, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type ScannerStorage interface {
+	Create(ctx context.Context, scanner *entity.EventScanner) (*entity.EventScanner, error)
+	GetEventsByUserID(ctx context.Context, userID int64) ([]entity.Event, error)
+	GetUsersByEventID(ctx context.Context, eventID string) ([]entity.User, error)
+	Delete(ctx context.Context, eventID string, userID int64) error
+	DeleteByEventID(ctx context.Context, eventID string) error
+}
+
+type scannerEventStorage interface {
+	GetByScannerInviteID(ctx context.Context, inviteID string) (*entity.Event, error)
+	Update(ctx context.Context, event *entity.Event) (*entity.Event, error)
+}
+
+// ScannerService manages the door scanners of the events, they can only activate the user QR codes
+type ScannerService struct {
+	storage      ScannerStorage
+	eventStorage scannerEventStorage
+}
+
+func NewScannerService(storage ScannerStorage, eventStorage scannerEventStorage) *ScannerService {
+	return &ScannerService{
+		storage:      storage,
+		eventStorage: eventStorage,
+	}
+}
+
+// CreateInvite creates the invite link secret of the event if it does not exist yet
+func (s *ScannerService) CreateInvite(ctx context.Context, event *entity.Event) (*entity.Event, error) {
+	if event.ScannerInviteID != "" {
+		return event, nil
+	}
+	event.ScannerInviteID = uuid.New().String()
+	return s.eventStorage.Update(ctx, event)
+}
+
+// ResetInvite replaces the invite link of the event and removes all its scanners
+func (s *ScannerService) ResetInvite(ctx context.Context, event *entity.Event) (*entity.Event, error) {
+	event.ScannerInviteID = uuid.New().String()
+	event, err := s.eventStorage.Update(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	return event, s.storage.DeleteByEventID(ctx, event.ID)
+}
+
+// Join grants the user the scanner role on the event of the invite,
+// returns errorz.ErrInviteExpired if the invite is unknown, the event is cancelled or the check-in on the event is closed
+func (s *ScannerService) Join(ctx context.Context, inviteID string, userID int64) (*entity.Event, error) {
+	if inviteID == "" {
+		return nil, errorz.ErrInviteExpired
+	}
+	event, err := s.eventStorage.GetByScannerInviteID(ctx, inviteID)
+	if err != nil {
+		return nil, err
+	}
+	if _, closes := event.CheckInWindow(); time.Now().After(closes) || event.IsCancelled() {
+		return event, errorz.ErrInviteExpired
+	}
+
+	_, err = s.storage.Create(ctx, &entity.EventScanner{
+		EventID: event.ID,
+		UserID:  userID,
+	})
+	return event, err
+}
+
+// GetActiveEvents returns the events the user can scan the QR codes on now,
+// the roles on the cancelled events and the events with the closed check-in are removed
+func (s *ScannerService) GetActiveEvents(ctx context.Context, userID int64) ([]entity.Event, error) {
+	events, err := s.storage.GetEventsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]entity.Event, 0, len(events))
+	for _, event := range events {
+		if _, closes := event.CheckInWindow(); time.Now().After(closes) || event.IsCancelled() {
+			if err = s.storage.Delete(ctx, event.ID, userID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		active = append(active, event)
+	}
+	return active, nil
+}
+
+// GetUsersByEventID returns the scanners of the event
+func (s *ScannerService) GetUsersByEventID(ctx context.Context, eventID string) ([]entity.User, error) {
+	return s.storage.GetUsersByEventID(ctx, eventID)
+}
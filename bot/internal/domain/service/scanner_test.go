@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type fakeScannerStorage struct {
+	ScannerStorage
+	created []entity.EventScanner
+}
+
+func (s *fakeScannerStorage) Create(_ context.Context, scanner *entity.EventScanner) (*entity.EventScanner, error) {
+	s.created = append(s.created, *scanner)
+	return scanner, nil
+}
+
+type fakeScannerEventStorage struct {
+	scannerEventStorage
+	event *entity.Event
+}
+
+func (s *fakeScannerEventStorage) GetByScannerInviteID(_ context.Context, inviteID string) (*entity.Event, error) {
+	if s.event == nil || s.event.ScannerInviteID != inviteID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return s.event, nil
+}
+
+func TestScannerServiceJoin(t *testing.T) {
+	now := time.Now()
+	cancelledAt := now
+
+	tests := []struct {
+		name     string
+		inviteID string
+		event    *entity.Event
+		wantErr  error
+	}{
+		{
+			name:     "upcoming event",
+			inviteID: "invite",
+			event:    &entity.Event{ID: "event", ScannerInviteID: "invite", StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour)},
+		},
+		{
+			name:     "check-in still open",
+			inviteID: "invite",
+			event:    &entity.Event{ID: "event", ScannerInviteID: "invite", StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour), CheckInClosesAfter: 90},
+		},
+		{
+			name:     "check-in closed",
+			inviteID: "invite",
+			event:    &entity.Event{ID: "event", ScannerInviteID: "invite", StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour)},
+			wantErr:  errorz.ErrInviteExpired,
+		},
+		{
+			name:     "cancelled event",
+			inviteID: "invite",
+			event:    &entity.Event{ID: "event", ScannerInviteID: "invite", StartTime: now.Add(time.Hour), CancelledAt: &cancelledAt},
+			wantErr:  errorz.ErrInviteExpired,
+		},
+		{
+			name:    "empty invite",
+			event:   &entity.Event{ID: "event"},
+			wantErr: errorz.ErrInviteExpired,
+		},
+		{
+			name:     "reset invite",
+			inviteID: "invite",
+			event:    &entity.Event{ID: "event", ScannerInviteID: "new invite", StartTime: now.Add(time.Hour)},
+			wantErr:  gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &fakeScannerStorage{}
+			s := NewScannerService(storage, &fakeScannerEventStorage{event: tt.event})
+
+			_, err := s.Join(context.Background(), tt.inviteID, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Join() error = %v, want %v", err, tt.wantErr)
+			}
+			if granted := len(storage.created) == 1; granted != (tt.wantErr == nil) {
+				t.Errorf("scanner role granted = %v, want %v", granted, tt.wantErr == nil)
+			}
+		})
+	}
+}
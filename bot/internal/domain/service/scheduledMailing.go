@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/localisation"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+	tele "gopkg.in/telebot.v3"
+	"gorm.io/gorm"
+)
+
+type ScheduledMailingStorage interface {
+	Create(ctx context.Context, mailing *entity.ScheduledMailing) (*entity.ScheduledMailing, error)
+	Get(ctx context.Context, id string) (*entity.ScheduledMailing, error)
+	GetByClubID(ctx context.Context, clubID string) ([]entity.ScheduledMailing, error)
+	GetDue(ctx context.Context, now time.Time) ([]entity.ScheduledMailing, error)
+	Update(ctx context.Context, mailing *entity.ScheduledMailing) (*entity.ScheduledMailing, error)
+	Delete(ctx context.Context, id string) error
+	SetStatus(ctx context.Context, id string, from, to entity.ScheduledMailingStatus) error
+	DeleteDispatched(ctx context.Context, id string) error
+}
+
+type scheduledMailingUserStorage interface {
+	GetUsersByClubID(ctx context.Context, clubID string) ([]entity.User, error)
+	GetVisitedUsersByClubID(ctx context.Context, clubID string) ([]entity.User, error)
+}
+
+type scheduledMailingSender interface {
+	Texts(key string, data interface{}) map[string]string
+	Send(mailing dto.Mailing, users []entity.User) (string, error)
+}
+
+type ScheduledMailingService struct {
+	bot    *tele.Bot
+	logger *types.Logger
+
+	storage        ScheduledMailingStorage
+	userStorage    scheduledMailingUserStorage
+	mailingService scheduledMailingSender
+
+	mailingChannelID int64
+}
+
+func NewScheduledMailingService(
+	bot *tele.Bot,
+	logger *types.Logger,
+	storage ScheduledMailingStorage,
+	userStorage scheduledMailingUserStorage,
+	mailingService scheduledMailingSender,
+	mailingChannelID int64,
+) *ScheduledMailingService {
+	return &ScheduledMailingService{
+		bot:    bot,
+		logger: logger,
+
+		storage:        storage,
+		userStorage:    userStorage,
+		mailingService: mailingService,
+
+		mailingChannelID: mailingChannelID,
+	}
+}
+
+func (s *ScheduledMailingService) Create(ctx context.Context, mailing *entity.ScheduledMailing) (*entity.ScheduledMailing, error) {
+	return s.storage.Create(ctx, mailing)
+}
+
+func (s *ScheduledMailingService) Get(ctx context.Context, id string) (*entity.ScheduledMailing, error) {
+	return s.storage.Get(ctx, id)
+}
+
+func (s *ScheduledMailingService) GetByClubID(ctx context.Context, clubID string) ([]entity.ScheduledMailing, error) {
+	return s.storage.GetByClubID(ctx, clubID)
+}
+
+func (s *ScheduledMailingService) Update(ctx context.Context, mailing *entity.ScheduledMailing) (*entity.ScheduledMailing, error) {
+	return s.storage.Update(ctx, mailing)
+}
+
+func (s *ScheduledMailingService) Delete(ctx context.Context, id string) error {
+	return s.storage.Delete(ctx, id)
+}
+
+// StartScheduledMailingScheduler starts the scheduler that queues the club mailings when their time comes
+func (s *ScheduledMailingService) StartScheduledMailingScheduler() {
+	s.logger.Info("Starting scheduled mailing scheduler")
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx := context.Background()
+			s.dispatchDue(ctx)
+		}
+	}()
+}
+
+// dispatchDue queues the mailings whose send time has come
+func (s *ScheduledMailingService) dispatchDue(ctx context.Context) {
+	mailings, err := s.storage.GetDue(ctx, time.Now())
+	if err != nil {
+		s.logger.Errorf("failed to get due scheduled mailings: %v", err)
+		return
+	}
+
+	for _, mailing := range mailings {
+		// The mailing is claimed first, so it can't be sent twice or edited and cancelled by the owner while it is sent
+		err = s.storage.SetStatus(ctx, mailing.ID, entity.ScheduledMailingPending, entity.ScheduledMailingDispatching)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			s.logger.Errorf("failed to claim scheduled mailing %s: %v", mailing.ID, err)
+			continue
+		}
+
+		if err = s.dispatch(ctx, mailing); err != nil {
+			s.logger.Errorf("failed to send scheduled mailing (mailing_id=%s, club_id=%s): %v", mailing.ID, mailing.ClubID, err)
+			// The mailing is released, so it is sent again on the next tick
+			err = s.storage.SetStatus(ctx, mailing.ID, entity.ScheduledMailingDispatching, entity.ScheduledMailingPending)
+			if err != nil {
+				s.logger.Errorf("failed to release scheduled mailing %s: %v", mailing.ID, err)
+			}
+			continue
+		}
+
+		// The claimed mailing is never sent again, even if it can't be deleted now
+		if err = s.storage.DeleteDispatched(ctx, mailing.ID); err != nil {
+			s.logger.Errorf("failed to delete scheduled mailing %s: %v", mailing.ID, err)
+		}
+	}
+}
+
+// dispatch queues the mailing for the users of its audience who haven't turned off the club mailings
+func (s *ScheduledMailingService) dispatch(ctx context.Context, scheduled entity.ScheduledMailing) error {
+	var (
+		users []entity.User
+		err   error
+	)
+	switch scheduled.Audience {
+	case entity.MailingAudienceVisited:
+		users, err = s.userStorage.GetVisitedUsersByClubID(ctx, scheduled.ClubID)
+	default:
+		users, err = s.userStorage.GetUsersByClubID(ctx, scheduled.ClubID)
+	}
+	if err != nil {
+		return err
+	}
+
+	recipients := make([]entity.User, 0, len(users))
+	for _, user := range users {
+		if user.IsMailingAllowed(scheduled.ClubID) {
+			recipients = append(recipients, user)
+		}
+	}
+
+	mailing := dto.Mailing{
+		ClubID:       scheduled.ClubID,
+		AuthorID:     scheduled.AuthorID,
+		AuthorLocale: scheduled.AuthorLocale,
+		Texts: s.mailingService.Texts("club_mailing", struct {
+			ClubName string
+			Text     string
+		}{
+			ClubName: scheduled.Club.Name,
+			Text:     scheduled.Text,
+		}),
+		MediaType: scheduled.MediaType,
+		FileID:    scheduled.FileID,
+	}
+	if _, err = s.mailingService.Send(mailing, recipients); err != nil {
+		return err
+	}
+	s.logger.Infof("Scheduled mailing queued (mailing_id=%s, club_id=%s)", scheduled.ID, scheduled.ClubID)
+
+	mailingChannel, err := s.bot.ChatByID(s.mailingChannelID)
+	if err != nil {
+		s.logger.Errorf("failed to get mailing channel: %v", err)
+		return nil
+	}
+	_, err = s.bot.Send(mailingChannel, utils.NewMediaMessage(mailing.MediaType, mailing.FileID, mailing.Texts[localisation.Default]))
+	if err != nil {
+		s.logger.Errorf("failed to send scheduled mailing to mailing channel: %v", err)
+	}
+	return nil
+}
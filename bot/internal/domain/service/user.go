@@ -112,6 +112,17 @@ func (s *UserService) Ban(ctx context.Context, userID int64) (*entity.User, erro
 	return s.Update(ctx, user)
 }
 
+// SetPhone sets the phone of the user for the pass lists
+func (s *UserService) SetPhone(ctx context.Context, userID int64, phone string) (*entity.User, error) {
+	user, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Phone = phone
+	return s.Update(ctx, user)
+}
+
 func (s *UserService) GetUsersByEventID(ctx context.Context, eventID string) ([]entity.User, error) {
 	return s.userStorage.GetUsersByEventID(ctx, eventID)
 }
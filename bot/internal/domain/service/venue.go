@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/lib/pq"
+)
+
+type VenueStorage interface {
+	Upsert(ctx context.Context, venue *entity.Venue) (*entity.Venue, error)
+	Get(ctx context.Context, id string) (*entity.Venue, error)
+	GetAll(ctx context.Context) ([]entity.Venue, error)
+	DeleteExcept(ctx context.Context, names []string) error
+}
+
+type venueEventStorage interface {
+	GetByVenueID(ctx context.Context, venueID string, from, to time.Time) ([]entity.Event, error)
+}
+
+type VenueService struct {
+	storage      VenueStorage
+	eventStorage venueEventStorage
+}
+
+func NewVenueService(storage VenueStorage, eventStorage venueEventStorage) *VenueService {
+	return &VenueService{
+		storage:      storage,
+		eventStorage: eventStorage,
+	}
+}
+
+func (s *VenueService) Get(ctx context.Context, id string) (*entity.Venue, error) {
+	return s.storage.Get(ctx, id)
+}
+
+// GetAll returns the venues ordered by name
+func (s *VenueService) GetAll(ctx context.Context) ([]entity.Venue, error) {
+	return s.storage.GetAll(ctx)
+}
+
+// GetConflicts returns the venue of the event and the other events in the venue that overlap it,
+// the venue is nil if the event has no venue
+func (s *VenueService) GetConflicts(ctx context.Context, event entity.Event) (*entity.Venue, []entity.Event, error) {
+	if event.VenueID == nil {
+		return nil, nil, nil
+	}
+
+	venue, err := s.storage.Get(ctx, *event.VenueID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the events starting a day before can still be going on
+	events, err := s.eventStorage.GetByVenueID(
+		ctx,
+		venue.ID,
+		event.StartTime.Add(-venue.Buffer-24*time.Hour),
+		event.GetEndTime().Add(venue.Buffer),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var conflicts []entity.Event
+	for _, e := range events {
+		if e.ID != event.ID && venue.Overlaps(event, e) {
+			conflicts = append(conflicts, e)
+		}
+	}
+	return venue, conflicts, nil
+}
+
+// GetOccupancy returns the events in the venue that start on the day of the given time
+func (s *VenueService) GetOccupancy(ctx context.Context, venueID string, day time.Time) ([]entity.Event, error) {
+	day = day.In(location.Location())
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, location.Location())
+	return s.eventStorage.GetByVenueID(ctx, venueID, from, from.AddDate(0, 0, 1))
+}
+
+// Sync makes the venues in the database match the venues from the config
+func (s *VenueService) Sync(ctx context.Context, venues []dto.Venue) error {
+	names := make([]string, 0, len(venues))
+	for _, v := range venues {
+		venue, err := newVenue(v)
+		if err != nil {
+			return err
+		}
+
+		if _, err = s.storage.Upsert(ctx, venue); err != nil {
+			return fmt.Errorf("failed to save venue %q: %w", v.Name, err)
+		}
+		names = append(names, v.Name)
+	}
+
+	return s.storage.DeleteExcept(ctx, names)
+}
+
+// newVenue creates the venue from the config, the pass deadlines are validated
+func newVenue(v dto.Venue) (*entity.Venue, error) {
+	if v.Name == "" || v.Address == "" {
+		return nil, fmt.Errorf("venue name and address are required")
+	}
+
+	venue := &entity.Venue{
+		Name:           v.Name,
+		Address:        v.Address,
+		Capacity:       v.Capacity,
+		PassRequired:   v.PassRequired,
+		PassRecipients: []entity.PassRecipient{},
+		PassLeadDays:   1,
+		PassDeadlines:  entity.DefaultPassDeadlines,
+		ConflictMode:   entity.VenueConflictWarn,
+		Buffer:         v.Buffer,
+	}
+
+	for _, email := range v.PassEmails {
+		venue.PassRecipients = append(venue.PassRecipients, entity.PassRecipient{
+			Email:   email,
+			Format:  entity.PassFormatXLSX,
+			Columns: entity.DefaultPassColumns,
+		})
+	}
+	if v.PassChatID != 0 {
+		venue.PassRecipients = append(venue.PassRecipients, entity.PassRecipient{
+			ChatID:  v.PassChatID,
+			Format:  entity.PassFormatXLSX,
+			Columns: entity.DefaultPassColumns,
+		})
+	}
+	for _, r := range v.PassRecipients {
+		recipient, err := newPassRecipient(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pass recipient of venue %q: %w", v.Name, err)
+		}
+		venue.PassRecipients = append(venue.PassRecipients, *recipient)
+	}
+
+	if v.PassLeadDays != nil {
+		if *v.PassLeadDays < 0 {
+			return nil, fmt.Errorf("invalid pass lead days %d of venue %q", *v.PassLeadDays, v.Name)
+		}
+		venue.PassLeadDays = *v.PassLeadDays
+	}
+
+	switch mode := entity.VenueConflictMode(v.ConflictMode); mode {
+	case "":
+	case entity.VenueConflictWarn, entity.VenueConflictBlock:
+		venue.ConflictMode = mode
+	default:
+		return nil, fmt.Errorf("invalid conflict mode %q of venue %q", v.ConflictMode, v.Name)
+	}
+
+	if len(v.PassDeadlines) > 0 {
+		venue.PassDeadlines = make(pq.StringArray, 7)
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			deadline := v.PassDeadlines[strings.ToLower(day.String())]
+			if deadline == "" {
+				continue
+			}
+			if _, err := time.Parse(entity.PassDeadlineLayout, deadline); err != nil {
+				return nil, fmt.Errorf("invalid pass deadline %q of venue %q: %w", deadline, v.Name, err)
+			}
+			venue.PassDeadlines[day] = deadline
+		}
+	}
+
+	return venue, nil
+}
+
+// newPassRecipient creates the pass recipient from the config, the format and columns are validated
+func newPassRecipient(r dto.PassRecipient) (*entity.PassRecipient, error) {
+	if (r.Email == "") == (r.ChatID == 0) {
+		return nil, fmt.Errorf("either email or chat id is required")
+	}
+
+	recipient := &entity.PassRecipient{
+		Email:   r.Email,
+		ChatID:  r.ChatID,
+		Format:  entity.PassFormatXLSX,
+		Columns: entity.DefaultPassColumns,
+	}
+
+	switch format := entity.PassFormat(r.Format); format {
+	case "":
+	case entity.PassFormatXLSX, entity.PassFormatCSV, entity.PassFormatPDF:
+		recipient.Format = format
+	default:
+		return nil, fmt.Errorf("invalid format %q", r.Format)
+	}
+
+	if len(r.Columns) > 0 {
+		recipient.Columns = make([]entity.PassColumn, 0, len(r.Columns))
+		for _, c := range r.Columns {
+			column := entity.PassColumn(c)
+			if !slices.Contains(entity.PassColumns, column) {
+				return nil, fmt.Errorf("invalid column %q", c)
+			}
+			recipient.Columns = append(recipient.Columns, column)
+		}
+	}
+
+	return recipient, nil
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/common/errorz"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/localisation"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"github.com/Badsnus/cu-clubs-bot/bot/pkg/logger/types"
+	tele "gopkg.in/telebot.v3"
+	"gopkg.in/telebot.v3/layout"
+)
+
+// defaultOfferTTL is how long the user from the waitlist has to confirm the offered seat if it isn't configured
+const defaultOfferTTL = 12 * time.Hour
+
+type WaitlistStorage interface {
+	Create(ctx context.Context, entry *entity.EventWaitlist) (*entity.EventWaitlist, error)
+	Get(ctx context.Context, eventID string, userID int64) (*entity.EventWaitlist, error)
+	Update(ctx context.Context, entry *entity.EventWaitlist) (*entity.EventWaitlist, error)
+	Delete(ctx context.Context, eventID string, userID int64) error
+	CountByEventID(ctx context.Context, eventID string) (int64, error)
+	CountOffered(ctx context.Context, eventID string) (int64, error)
+	GetPosition(ctx context.Context, eventID string, userID int64) (int64, error)
+	GetWaiting(ctx context.Context, eventID string, limit int) ([]entity.EventWaitlist, error)
+	GetExpiredOffers(ctx context.Context, now time.Time) ([]entity.EventWaitlist, error)
+	GetEventIDs(ctx context.Context) ([]string, error)
+	Promote(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error)
+}
+
+type waitlistEventStorage interface {
+	Get(ctx context.Context, id string) (*entity.Event, error)
+}
+
+type waitlistParticipantStorage interface {
+	CountByEventID(ctx context.Context, eventID string) (int64, error)
+}
+
+type waitlistUserStorage interface {
+	Get(ctx context.Context, id uint) (*entity.User, error)
+}
+
+type WaitlistService struct {
+	bot    *tele.Bot
+	layout *layout.Layout
+	logger *types.Logger
+
+	storage            WaitlistStorage
+	eventStorage       waitlistEventStorage
+	participantStorage waitlistParticipantStorage
+	userStorage        waitlistUserStorage
+
+	offerTTL time.Duration
+}
+
+func NewWaitlistService(
+	bot *tele.Bot,
+	layout *layout.Layout,
+	logger *types.Logger,
+	storage WaitlistStorage,
+	eventStorage waitlistEventStorage,
+	participantStorage waitlistParticipantStorage,
+	userStorage waitlistUserStorage,
+	offerTTL time.Duration,
+) *WaitlistService {
+	if offerTTL <= 0 {
+		offerTTL = defaultOfferTTL
+	}
+
+	return &WaitlistService{
+		bot:    bot,
+		layout: layout,
+		logger: logger,
+
+		storage:            storage,
+		eventStorage:       eventStorage,
+		participantStorage: participantStorage,
+		userStorage:        userStorage,
+
+		offerTTL: offerTTL,
+	}
+}
+
+func (s *WaitlistService) Join(ctx context.Context, eventID string, userID int64) (*entity.EventWaitlist, error) {
+	return s.storage.Create(ctx, &entity.EventWaitlist{
+		EventID: eventID,
+		UserID:  userID,
+	})
+}
+
+func (s *WaitlistService) Get(ctx context.Context, eventID string, userID int64) (*entity.EventWaitlist, error) {
+	return s.storage.Get(ctx, eventID, userID)
+}
+
+func (s *WaitlistService) GetPosition(ctx context.Context, eventID string, userID int64) (int, error) {
+	position, err := s.storage.GetPosition(ctx, eventID, userID)
+	return int(position), err
+}
+
+func (s *WaitlistService) CountByEventID(ctx context.Context, eventID string) (int, error) {
+	count, err := s.storage.CountByEventID(ctx, eventID)
+	return int(count), err
+}
+
+// Leave removes the user from the event queue.
+//
+// If the user had an active offer, the seat is offered to the next user in the queue.
+func (s *WaitlistService) Leave(ctx context.Context, eventID string, userID int64) error {
+	entry, err := s.storage.Get(ctx, eventID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.storage.Delete(ctx, eventID, userID); err != nil {
+		return err
+	}
+
+	if entry.IsOffered() {
+		return s.PromoteNext(ctx, eventID)
+	}
+	return nil
+}
+
+// Confirm registers the user on the event if the offered seat has not expired yet
+func (s *WaitlistService) Confirm(ctx context.Context, eventID string, userID int64) (*entity.EventParticipant, error) {
+	entry, err := s.storage.Get(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !entry.IsOffered() || entry.OfferExpiresAt.Before(time.Now()) {
+		return nil, errorz.ErrOfferExpired
+	}
+
+	return s.storage.Promote(ctx, eventID, userID)
+}
+
+// PromoteNext offers free seats of the event to the next users in the queue until the registration closes,
+// the offers expire with the registration at the latest.
+//
+// A seat is considered free if it is not taken by a participant and not reserved by an active offer.
+func (s *WaitlistService) PromoteNext(ctx context.Context, eventID string) error {
+	event, err := s.eventStorage.Get(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	// The seats are not offered once the registration has closed
+	if event.IsOver(0) || event.IsCancelled() || !event.RegistrationEnd.After(time.Now()) {
+		return nil
+	}
+
+	participantsCount, err := s.participantStorage.CountByEventID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	offeredCount, err := s.storage.CountOffered(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	// limit -1 disables the limit for events without participants restriction
+	limit := -1
+	if event.MaxParticipants > 0 {
+		limit = event.MaxParticipants - int(participantsCount) - int(offeredCount)
+		if limit <= 0 {
+			return nil
+		}
+	}
+
+	entries, err := s.storage.GetWaiting(ctx, eventID, limit)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().In(location.Location()).Add(s.offerTTL)
+	if expiresAt.After(event.RegistrationEnd) {
+		expiresAt = event.RegistrationEnd.In(location.Location())
+	}
+
+	for _, entry := range entries {
+		entry.OfferExpiresAt = &expiresAt
+		if _, err = s.storage.Update(ctx, &entry); err != nil {
+			return err
+		}
+
+		s.logger.Infof("Offering seat to user (user_id=%d, event_id=%s)", entry.UserID, eventID)
+		chat, errGetChat := s.bot.ChatByID(entry.UserID)
+		if errGetChat != nil {
+			s.logger.Errorf("failed to get chat for user %d: %v", entry.UserID, errGetChat)
+			continue
+		}
+
+		locale := s.userLocale(ctx, entry.UserID)
+		_, errSend := s.bot.Send(chat,
+			s.layout.TextLocale(locale, "waitlist_offer", struct {
+				Name      string
+				ExpiresAt string
+			}{
+				Name:      event.Name,
+				ExpiresAt: expiresAt.Format("02.01.2006 15:04"),
+			}),
+			s.layout.MarkupLocale(locale, "waitlist:offer", struct {
+				ID string
+			}{
+				ID: eventID,
+			}),
+		)
+		if errSend != nil {
+			s.logger.Errorf("failed to send waitlist offer to user %d: %v", entry.UserID, errSend)
+		}
+	}
+
+	return nil
+}
+
+// StartWaitlistScheduler starts the scheduler that expires unconfirmed offers and promotes the queues
+func (s *WaitlistService) StartWaitlistScheduler() {
+	s.logger.Info("Starting waitlist scheduler")
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx := context.Background()
+			s.checkAndPromote(ctx)
+		}
+	}()
+}
+
+// checkAndPromote drops expired offers and offers free seats to the next users in the queues
+func (s *WaitlistService) checkAndPromote(ctx context.Context) {
+	expired, err := s.storage.GetExpiredOffers(ctx, time.Now())
+	if err != nil {
+		s.logger.Errorf("failed to get expired waitlist offers: %v", err)
+		return
+	}
+
+	for _, entry := range expired {
+		s.logger.Infof("Waitlist offer expired (user_id=%d, event_id=%s)", entry.UserID, entry.EventID)
+		if err = s.storage.Delete(ctx, entry.EventID, entry.UserID); err != nil {
+			s.logger.Errorf("failed to delete expired waitlist entry: %v", err)
+			continue
+		}
+
+		event, errGet := s.eventStorage.Get(ctx, entry.EventID)
+		if errGet != nil {
+			s.logger.Errorf("failed to get event %s: %v", entry.EventID, errGet)
+			continue
+		}
+
+		chat, errGetChat := s.bot.ChatByID(entry.UserID)
+		if errGetChat != nil {
+			s.logger.Errorf("failed to get chat for user %d: %v", entry.UserID, errGetChat)
+			continue
+		}
+
+		locale := s.userLocale(ctx, entry.UserID)
+		_, errSend := s.bot.Send(chat,
+			s.layout.TextLocale(locale, "waitlist_offer_expired", event),
+			s.layout.MarkupLocale(locale, "core:hide"),
+		)
+		if errSend != nil {
+			s.logger.Errorf("failed to send waitlist offer expiration to user %d: %v", entry.UserID, errSend)
+		}
+	}
+
+	eventIDs, err := s.storage.GetEventIDs(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to get events with waitlist: %v", err)
+		return
+	}
+
+	for _, eventID := range eventIDs {
+		if err = s.PromoteNext(ctx, eventID); err != nil {
+			s.logger.Errorf("failed to promote waitlist of event %s: %v", eventID, err)
+		}
+	}
+}
+
+// userLocale returns the language of the user, the default one is returned if the user can't be fetched
+func (s *WaitlistService) userLocale(ctx context.Context, userID int64) string {
+	user, err := s.userStorage.Get(ctx, uint(userID))
+	if err != nil {
+		s.logger.Errorf("failed to get user %d: %v", userID, err)
+		return localisation.Default
+	}
+	return localisation.Resolve(user.Localisation)
+}
@@ -3,9 +3,11 @@ package calendar
 import (
 	"bytes"
 	"fmt"
-	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
 	"time"
 
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/dto"
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+
 	ics "github.com/arran4/golang-ical"
 )
 
@@ -16,15 +18,110 @@ import (
 // classification. Additionally, reminders are added for one day and one hour before
 // the event. The function returns the serialized iCalendar data as a byte slice or
 // an error if serialization fails.
-func ExportEventToICS(event entity.Event) ([]byte, error) {
+//
+// If the event belongs to a series, the whole series is exported as a single recurring
+// event with the RRULE of the series. The materialised occurrences of the series are compared
+// with the series: the cancelled and deleted ones are excluded from the recurrence with EXDATE,
+// the ones edited separately (time, location, name or description) are added as overriding
+// VEVENTs with the RECURRENCE-ID of their original start.
+func ExportEventToICS(event entity.Event, series *entity.EventSeries, occurrences ...entity.Event) ([]byte, error) {
+	cal := newCalendar()
+
+	// Серия экспортируется как одно повторяющееся событие, начиная с первого мероприятия
+	id := event.ID
+	if series != nil {
+		id = series.ID
+		event = series.Occurrence(0)
+		event.CreatedAt = series.CreatedAt
+		event.UpdatedAt = series.UpdatedAt
+		event.Sequence = series.Sequence
+	}
+
+	e := addEvent(cal, id, event)
+
+	// Добавляем правило повторения, исключаем отмененные мероприятия серии и переопределяем измененные
+	if series != nil {
+		e.AddRrule(series.RRule())
+		for _, occurrence := range occurrences {
+			recurrenceID := series.OccurrenceStart(occurrence.SeriesIndex).UTC().Format("20060102T150405Z")
+			switch {
+			case occurrence.DeletedAt.Valid || occurrence.IsCancelled():
+				e.AddExdate(recurrenceID)
+			case isOccurrenceEdited(series, occurrence):
+				override := addEvent(cal, id, occurrence)
+				override.SetProperty(ics.ComponentPropertyRecurrenceId, recurrenceID)
+			}
+		}
+	}
+
+	return serialize(cal)
+}
+
+// isOccurrenceEdited checks if the occurrence differs from the series in the fields exported to the calendar
+func isOccurrenceEdited(series *entity.EventSeries, occurrence entity.Event) bool {
+	expected := series.Occurrence(occurrence.SeriesIndex)
+	return !occurrence.StartTime.Equal(expected.StartTime) ||
+		!occurrence.EndTime.Equal(expected.EndTime) ||
+		occurrence.Location != expected.Location ||
+		occurrence.Name != expected.Name ||
+		occurrence.Description != expected.Description
+}
+
+// ExportUserEventsToICS converts events the user has registered on into an iCalendar feed.
+//
+// Every event is exported as a separate VEVENT built the same way as in ExportEventToICS,
+// so calendar clients update the events in place when their SEQUENCE is bumped.
+func ExportUserEventsToICS(events []dto.UserEvent) ([]byte, error) {
+	cal := newCalendar()
+	cal.SetXWRCalName("CU Clubs")
+	cal.SetRefreshInterval("PT1H")
+	cal.SetXPublishedTTL("PT1H")
+
+	for _, event := range events {
+		addEvent(cal, event.ID, entity.Event{
+			CreatedAt:   event.CreatedAt,
+			UpdatedAt:   event.UpdatedAt,
+			Name:        event.Name,
+			Description: event.Description,
+			Location:    event.Location,
+			StartTime:   event.StartTime,
+			EndTime:     event.EndTime,
+			Sequence:    event.Sequence,
+			CancelledAt: event.CancelledAt,
+		})
+	}
+
+	return serialize(cal)
+}
+
+// ExportClubEventsToICS converts upcoming events of the club into an iCalendar feed
+func ExportClubEventsToICS(club entity.Club, events []entity.Event) ([]byte, error) {
+	cal := newCalendar()
+	cal.SetXWRCalName(club.Name)
+	cal.SetXWRCalDesc(club.Description)
+	cal.SetRefreshInterval("PT1H")
+	cal.SetXPublishedTTL("PT1H")
+
+	for _, event := range events {
+		addEvent(cal, event.ID, event)
+	}
+
+	return serialize(cal)
+}
+
+func newCalendar() *ics.Calendar {
 	cal := ics.NewCalendar()
 	cal.SetMethod(ics.MethodPublish)
 	cal.SetProductId("-//CU Clubs Bot//EN")
 	cal.SetVersion("2.0")
 	cal.SetCalscale("GREGORIAN")
+	return cal
+}
 
+// addEvent adds the event to the calendar as a VEVENT with the given id
+func addEvent(cal *ics.Calendar, id string, event entity.Event) *ics.VEvent {
 	// Создаем уникальный идентификатор события
-	uid := fmt.Sprintf("%s@cu-clubs-bot", event.ID)
+	uid := fmt.Sprintf("%s@cu-clubs-bot", id)
 	e := cal.AddEvent(uid)
 
 	// Устанавливаем время создания и изменения события
@@ -48,8 +145,12 @@ func ExportEventToICS(event entity.Event) ([]byte, error) {
 	e.SetDescription(event.Description)
 	e.SetLocation(event.Location)
 
-	// Добавляем статус события
-	e.SetStatus(ics.ObjectStatusConfirmed)
+	// Добавляем статус события, отмененные мероприятия удаляются из календарей клиентами
+	if event.IsCancelled() {
+		e.SetStatus(ics.ObjectStatusCancelled)
+	} else {
+		e.SetStatus(ics.ObjectStatusConfirmed)
+	}
 
 	// Добавляем прозрачность (показывает, занято ли время в календаре)
 	e.SetTimeTransparency(ics.TransparencyOpaque)
@@ -57,8 +158,8 @@ func ExportEventToICS(event entity.Event) ([]byte, error) {
 	// Добавляем класс доступности (публичное)
 	e.SetClass(ics.ClassificationPublic)
 
-	// Добавляем последовательность (для синхронизации)
-	e.SetSequence(0)
+	// Добавляем последовательность (для синхронизации), увеличивается при каждом изменении мероприятия
+	e.SetSequence(event.Sequence)
 
 	// Добавляем напоминание за день до события
 	dayAlarm := e.AddAlarm()
@@ -72,6 +173,10 @@ func ExportEventToICS(event entity.Event) ([]byte, error) {
 	hourAlarm.AddProperty("TRIGGER;VALUE=DURATION", "-PT1H")
 	hourAlarm.SetDescription(fmt.Sprintf("Напоминание: %s (через час)", event.Name))
 
+	return e
+}
+
+func serialize(cal *ics.Calendar) ([]byte, error) {
 	var buf bytes.Buffer
 	err := cal.SerializeTo(&buf)
 	if err != nil {
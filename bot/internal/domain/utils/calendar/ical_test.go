@@ -0,0 +1,104 @@
+package calendar
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/entity"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	viper.Set("settings.timezone", "Europe/Moscow")
+	os.Exit(m.Run())
+}
+
+func TestExportEventToICSSeries(t *testing.T) {
+	series := &entity.EventSeries{
+		ID:              "series",
+		Name:            "Лекция",
+		Location:        "Гашека 7",
+		StartTime:       time.Date(2026, 3, 23, 16, 0, 0, 0, time.UTC),
+		RegistrationEnd: time.Date(2026, 3, 22, 16, 0, 0, 0, time.UTC),
+		Frequency:       entity.SeriesFrequencyWeekly,
+		Interval:        1,
+	}
+	cancelledAt := time.Now()
+
+	tests := []struct {
+		name        string
+		occurrence  func() entity.Event
+		wantExdate  bool
+		wantVEvents int
+	}{
+		{
+			name:        "unchanged occurrence",
+			occurrence:  func() entity.Event { return series.Occurrence(2) },
+			wantVEvents: 1,
+		},
+		{
+			name: "cancelled occurrence",
+			occurrence: func() entity.Event {
+				event := series.Occurrence(2)
+				event.CancelledAt = &cancelledAt
+				return event
+			},
+			wantExdate:  true,
+			wantVEvents: 1,
+		},
+		{
+			name: "deleted occurrence",
+			occurrence: func() entity.Event {
+				event := series.Occurrence(2)
+				event.DeletedAt = gorm.DeletedAt{Time: cancelledAt, Valid: true}
+				return event
+			},
+			wantExdate:  true,
+			wantVEvents: 1,
+		},
+		{
+			name: "moved occurrence",
+			occurrence: func() entity.Event {
+				event := series.Occurrence(2)
+				event.StartTime = event.StartTime.Add(time.Hour)
+				return event
+			},
+			wantVEvents: 2,
+		},
+		{
+			name: "occurrence in another location",
+			occurrence: func() entity.Event {
+				event := series.Occurrence(2)
+				event.Location = "Онлайн"
+				return event
+			},
+			wantVEvents: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ExportEventToICS(entity.Event{}, series, tt.occurrence())
+			if err != nil {
+				t.Fatalf("ExportEventToICS() error = %v", err)
+			}
+			ics := strings.ReplaceAll(string(data), "\r\n ", "")
+
+			if !strings.Contains(ics, "RRULE:FREQ=WEEKLY;INTERVAL=1") {
+				t.Errorf("RRULE is missing:\n%s", ics)
+			}
+			if got := strings.Contains(ics, "EXDATE:20260406T160000Z"); got != tt.wantExdate {
+				t.Errorf("EXDATE present = %v, want %v:\n%s", got, tt.wantExdate, ics)
+			}
+			if got := strings.Count(ics, "BEGIN:VEVENT"); got != tt.wantVEvents {
+				t.Errorf("VEVENT count = %d, want %d:\n%s", got, tt.wantVEvents, ics)
+			}
+			if wantOverride := tt.wantVEvents > 1; strings.Contains(ics, "RECURRENCE-ID:20260406T160000Z") != wantOverride {
+				t.Errorf("RECURRENCE-ID present = %v, want %v:\n%s", !wantOverride, wantOverride, ics)
+			}
+		})
+	}
+}
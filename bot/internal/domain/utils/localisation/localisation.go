@@ -0,0 +1,22 @@
+package localisation
+
+import "slices"
+
+// Default is the locale used when the language of the user is unknown or not supported
+const Default = "ru"
+
+// Supported is the list of locales that have a file in the locales directory
+var Supported = []string{"ru", "en"}
+
+// IsSupported checks if there is a locale file for the locale
+func IsSupported(locale string) bool {
+	return slices.Contains(Supported, locale)
+}
+
+// Resolve returns the locale if it is supported and Default otherwise
+func Resolve(locale string) string {
+	if IsSupported(locale) {
+		return locale
+	}
+	return Default
+}
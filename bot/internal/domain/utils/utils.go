@@ -43,6 +43,41 @@ func GetMessageText(msg *tele.Message) string {
 	}
 }
 
+// GetMessageMedia returns the type and the file id of the media attached to the message,
+// the same media types as in ChangeMessageText are supported
+func GetMessageMedia(msg *tele.Message) (string, string) {
+	switch {
+	case msg.Photo != nil:
+		return "photo", msg.Photo.FileID
+	case msg.Video != nil:
+		return "video", msg.Video.FileID
+	case msg.Audio != nil:
+		return "audio", msg.Audio.FileID
+	case msg.Document != nil:
+		return "document", msg.Document.FileID
+	default:
+		return "", ""
+	}
+}
+
+// NewMediaMessage creates a sendable message from the media returned by GetMessageMedia,
+// without media the text itself is returned
+func NewMediaMessage(mediaType, fileID, text string) interface{} {
+	file := tele.File{FileID: fileID}
+	switch mediaType {
+	case "photo":
+		return &tele.Photo{File: file, Caption: text}
+	case "video":
+		return &tele.Video{File: file, Caption: text}
+	case "audio":
+		return &tele.Audio{File: file, Caption: text}
+	case "document":
+		return &tele.Document{File: file, Caption: text}
+	default:
+		return text
+	}
+}
+
 func GetMaxRegisteredEndTime(startTimeStr string) string {
 	const layout = "02.01.2006 15:04"
 
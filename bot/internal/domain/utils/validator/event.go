@@ -2,7 +2,9 @@ package validator
 
 import (
 	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 )
@@ -81,6 +83,22 @@ func EventRegisteredEndTime(registeredEnd string, params map[string]interface{})
 	return registeredEndTime.After(now.Add(time.Hour))
 }
 
+func EventCancellationEnd(cancellationEnd string, params map[string]interface{}) bool {
+	const layout = "02.01.2006 15:04"
+
+	startTimeStr, ok := params["startTime"].(string)
+	if !ok {
+		return false
+	}
+	startTime, _ := time.ParseInLocation(layout, startTimeStr, location.Location())
+	cancellationEndTime, err := time.ParseInLocation(layout, cancellationEnd, location.Location())
+	if err != nil {
+		return false
+	}
+
+	return cancellationEndTime.Before(startTime) && cancellationEndTime.After(time.Now().In(location.Location()))
+}
+
 func EventAfterRegistrationText(afterRegistrationText string, _ map[string]interface{}) bool {
 	return utf8.RuneCountInString(afterRegistrationText) >= 10 && utf8.RuneCountInString(afterRegistrationText) <= 200
 }
@@ -115,3 +133,76 @@ func EventEditMaxParticipants(maxParticipantsStr string, params map[string]inter
 	}
 	return maxParticipants > 0 && maxParticipants > previousMaxParticipants
 }
+
+func EventMaxGuests(maxGuestsStr string, _ map[string]interface{}) bool {
+	maxGuests, err := strconv.Atoi(maxGuestsStr)
+	if err != nil {
+		return false
+	}
+	return maxGuests >= 0 && maxGuests <= 10
+}
+
+func EventQuestion(question string, _ map[string]interface{}) bool {
+	question = strings.TrimSpace(question)
+	return question != "" && utf8.RuneCountInString(question) <= 200
+}
+
+// EventQuestionOptions checks the answer options of the question, one option per line
+func EventQuestionOptions(optionsStr string, _ map[string]interface{}) bool {
+	var options []string
+	for _, option := range strings.Split(optionsStr, "\n") {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+		if utf8.RuneCountInString(option) > 50 || slices.Contains(options, option) {
+			return false
+		}
+		options = append(options, option)
+	}
+	return len(options) >= 2 && len(options) <= 10
+}
+
+// EventCheckInWindow checks the minutes before the start and after the end of the event, separated by a space
+func EventCheckInWindow(window string, _ map[string]interface{}) bool {
+	parts := strings.Fields(window)
+	if len(parts) != 2 {
+		return false
+	}
+	for _, part := range parts {
+		minutes, err := strconv.Atoi(part)
+		if err != nil || minutes < 0 || minutes > 24*60 {
+			return false
+		}
+	}
+	return true
+}
+
+// EventCancelReason checks the reason of the event cancellation, it is sent to the participants as the caption of the calendar file
+func EventCancelReason(reason string, _ map[string]interface{}) bool {
+	reason = strings.TrimSpace(reason)
+	return reason != "" && utf8.RuneCountInString(reason) <= 500
+}
+
+// EventPublishAt checks the publish time of the draft, the draft must be published before the registration ends
+func EventPublishAt(publishAt string, params map[string]interface{}) bool {
+	const layout = "02.01.2006 15:04"
+
+	registrationEndStr, ok := params["registrationEnd"].(string)
+	if !ok {
+		return false
+	}
+	registrationEnd, _ := time.ParseInLocation(layout, registrationEndStr, location.Location())
+	publishAtTime, err := time.ParseInLocation(layout, publishAt, location.Location())
+	if err != nil {
+		return false
+	}
+
+	return publishAtTime.After(time.Now()) && publishAtTime.Before(registrationEnd)
+}
+
+// EventTemplateTitle checks the title of the event template, it is shown on the button in the templates list
+func EventTemplateTitle(title string, _ map[string]interface{}) bool {
+	length := utf8.RuneCountInString(strings.TrimSpace(title))
+	return length >= 3 && length <= 30
+}
@@ -1,7 +1,22 @@
 package validator
 
-import "unicode/utf8"
+import (
+	"github.com/Badsnus/cu-clubs-bot/bot/internal/domain/utils/location"
+	"time"
+	"unicode/utf8"
+)
 
 func MailingText(text string, _ map[string]interface{}) bool {
 	return utf8.RuneCountInString(text) <= 500
 }
+
+func MailingSendAt(sendAt string, _ map[string]interface{}) bool {
+	const layout = "02.01.2006 15:04"
+
+	sendAtTime, err := time.ParseInLocation(layout, sendAt, location.Location())
+	if err != nil {
+		return false
+	}
+
+	return sendAtTime.After(time.Now())
+}
@@ -34,3 +34,19 @@ func emailDomain(email string) bool {
 	}
 	return false
 }
+
+// Phone checks the phone number, it may have the spaces, dashes and brackets between the digits
+func Phone(phone string, _ map[string]interface{}) bool {
+	re := regexp.MustCompile(`^\+?[0-9][0-9 ()\-]*[0-9]$`)
+	if !re.MatchString(strings.TrimSpace(phone)) {
+		return false
+	}
+
+	var digits int
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 10 && digits <= 15
+}
@@ -0,0 +1,205 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/math/fixed"
+)
+
+// A4 page size in points
+const (
+	pageWidth  = 595
+	pageHeight = 842
+)
+
+// font is the embedded TrueType font of the document, the text is written by the glyph indexes,
+// so any script of the font can be used, and the ToUnicode map keeps the text searchable and copyable
+type font struct {
+	ttf        *truetype.Font
+	unitsPerEm fixed.Int26_6
+	// used are the runes of the used glyphs by their indexes
+	used map[truetype.Index]rune
+}
+
+func newFont() (*font, error) {
+	ttf, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+	return &font{
+		ttf:        ttf,
+		unitsPerEm: fixed.Int26_6(ttf.FUnitsPerEm()),
+		used:       make(map[truetype.Index]rune),
+	}, nil
+}
+
+// width returns the width of the glyph in thousandths of the font size
+func (f *font) width(index truetype.Index) int {
+	return int(f.ttf.HMetric(f.unitsPerEm, index).AdvanceWidth) * 1000 / int(f.unitsPerEm)
+}
+
+// textWidth returns the width of the text in points
+func (f *font) textWidth(text string, size float64) float64 {
+	var width int
+	for _, r := range text {
+		width += f.width(f.ttf.Index(r))
+	}
+	return float64(width) * size / 1000
+}
+
+// encode returns the text as the hex string of the glyph indexes
+func (f *font) encode(text string) string {
+	var sb strings.Builder
+	sb.WriteString("<")
+	for _, r := range text {
+		index := f.ttf.Index(r)
+		f.used[index] = r
+		fmt.Fprintf(&sb, "%04X", uint16(index))
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+// widths returns the /W array of the used glyphs
+func (f *font) widths() string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for _, index := range f.usedIndexes() {
+		fmt.Fprintf(&sb, " %d [%d]", index, f.width(index))
+	}
+	sb.WriteString(" ]")
+	return sb.String()
+}
+
+// toUnicode returns the CMap that maps the used glyphs back to the text
+func (f *font) toUnicode() []byte {
+	var sb strings.Builder
+	sb.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	sb.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	sb.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	sb.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	indexes := f.usedIndexes()
+	// a bfchar block can have at most 100 entries
+	for start := 0; start < len(indexes); start += 100 {
+		end := min(start+100, len(indexes))
+		fmt.Fprintf(&sb, "%d beginbfchar\n", end-start)
+		for _, index := range indexes[start:end] {
+			fmt.Fprintf(&sb, "<%04X> <", uint16(index))
+			for _, unit := range utf16.Encode([]rune{f.used[index]}) {
+				fmt.Fprintf(&sb, "%04X", unit)
+			}
+			sb.WriteString(">\n")
+		}
+		sb.WriteString("endbfchar\n")
+	}
+
+	sb.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+	return []byte(sb.String())
+}
+
+func (f *font) usedIndexes() []truetype.Index {
+	indexes := make([]truetype.Index, 0, len(f.used))
+	for index := range f.used {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	return indexes
+}
+
+// write writes the PDF document with the A4 pages drawn by the contents streams in the font
+func write(f *font, contents [][]byte) (*bytes.Buffer, error) {
+	var (
+		buf     bytes.Buffer
+		offsets []int
+	)
+	object := func(body string, stream []byte) error {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n", len(offsets))
+		if stream == nil {
+			fmt.Fprintf(&buf, "%s\nendobj\n", body)
+			return nil
+		}
+
+		var compressed bytes.Buffer
+		w := zlib.NewWriter(&compressed)
+		if _, err := w.Write(stream); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "<< %s /Filter /FlateDecode /Length %d >>\nstream\n", body, compressed.Len())
+		buf.Write(compressed.Bytes())
+		buf.WriteString("\nendstream\nendobj\n")
+		return nil
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// 1 - catalog, 2 - pages, 3-7 - font, then page and content objects for every page
+	const firstPage = 8
+	kids := ""
+	for i := range contents {
+		kids += fmt.Sprintf("%d 0 R ", firstPage+i*2)
+	}
+
+	scale := f.unitsPerEm
+	bounds := f.ttf.Bounds(scale)
+	objects := []struct {
+		body   string
+		stream []byte
+	}{
+		{"<< /Type /Catalog /Pages 2 0 R >>", nil},
+		{fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids, len(contents)), nil},
+		{"<< /Type /Font /Subtype /Type0 /BaseFont /GoRegular /Encoding /Identity-H /DescendantFonts [4 0 R] /ToUnicode 7 0 R >>", nil},
+		{fmt.Sprintf(
+			"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /GoRegular "+
+				"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+				"/FontDescriptor 5 0 R /CIDToGIDMap /Identity /W %s >>",
+			f.widths(),
+		), nil},
+		{fmt.Sprintf(
+			"<< /Type /FontDescriptor /FontName /GoRegular /Flags 32 /FontBBox [%d %d %d %d] "+
+				"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 6 0 R >>",
+			int(bounds.Min.X)*1000/int(scale), int(bounds.Min.Y)*1000/int(scale),
+			int(bounds.Max.X)*1000/int(scale), int(bounds.Max.Y)*1000/int(scale),
+			int(bounds.Max.Y)*1000/int(scale), int(bounds.Min.Y)*1000/int(scale), int(bounds.Max.Y)*1000/int(scale),
+		), nil},
+		{fmt.Sprintf("/Length1 %d", len(goregular.TTF)), goregular.TTF},
+		{"", f.toUnicode()},
+	}
+	for _, o := range objects {
+		if err := object(o.body, o.stream); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, content := range contents {
+		if err := object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPage+i*2+1,
+		), nil); err != nil {
+			return nil, err
+		}
+		if err := object("", content); err != nil {
+			return nil, err
+		}
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return &buf, nil
+}
@@ -0,0 +1,98 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// The table is drawn on A4 pages, the sizes are in points
+const (
+	margin      = 40
+	fontSize    = 10
+	rowHeight   = 20
+	cellPadding = 5
+)
+
+// Table writes a PDF document with the table as text, the header is repeated on every page
+// and the cells that don't fit the page width are cut
+func Table(header []string, rows [][]string) (*bytes.Buffer, error) {
+	f, err := newFont()
+	if err != nil {
+		return nil, err
+	}
+
+	widths := columnWidths(f, header, rows)
+
+	rowsOnPage := (pageHeight-2*margin)/rowHeight - 1
+	var contents [][]byte
+	for start := 0; start == 0 || start < len(rows); start += rowsOnPage {
+		end := min(start+rowsOnPage, len(rows))
+
+		var content bytes.Buffer
+		drawRow(&content, f, widths, header, 0, true)
+		for i, row := range rows[start:end] {
+			drawRow(&content, f, widths, row, i+1, false)
+		}
+		contents = append(contents, content.Bytes())
+	}
+
+	return write(f, contents)
+}
+
+// columnWidths returns the widths of the columns by their longest cells, scaled down to the page width
+func columnWidths(f *font, header []string, rows [][]string) []float64 {
+	widths := make([]float64, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if i >= len(widths) {
+				break
+			}
+			widths[i] = max(widths[i], f.textWidth(cell, fontSize)+2*cellPadding)
+		}
+	}
+
+	var total float64
+	for _, w := range widths {
+		total += w
+	}
+	if available := float64(pageWidth - 2*margin); total > available {
+		for i := range widths {
+			widths[i] *= available / total
+		}
+	}
+	return widths
+}
+
+// drawRow draws the row with the number from the top of the page
+func drawRow(content *bytes.Buffer, f *font, widths []float64, row []string, number int, header bool) {
+	x := float64(margin)
+	y := float64(pageHeight - margin - (number+1)*rowHeight)
+	for i, w := range widths {
+		if header {
+			fmt.Fprintf(content, "0.9 g %.2f %.2f %.2f %d re f\n", x, y, w, rowHeight)
+		}
+		fmt.Fprintf(content, "0.6 G 0.5 w %.2f %.2f %.2f %d re S\n", x, y, w, rowHeight)
+
+		if i < len(row) && row[i] != "" {
+			text := fit(f, row[i], w-2*cellPadding)
+			fmt.Fprintf(content, "0 g BT /F1 %d Tf %.2f %.2f Td %s Tj ET\n",
+				fontSize, x+cellPadding, y+(rowHeight-fontSize)/2+2, f.encode(text))
+		}
+		x += w
+	}
+}
+
+// fit cuts the text to the width
+func fit(f *font, text string, width float64) string {
+	if f.textWidth(text, fontSize) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if f.textWidth(string(runes)+"…", fontSize) <= width {
+			break
+		}
+	}
+	return string(runes) + "…"
+}
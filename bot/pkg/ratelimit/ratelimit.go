@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket is a token bucket rate limiter
+//
+// The bucket holds up to burst tokens and is refilled with rate tokens per second,
+// every call to Wait takes one token and blocks until it is available
+type TokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastFill time.Time
+}
+
+// NewTokenBucket creates a full token bucket
+func NewTokenBucket(rate int, burst int) *TokenBucket {
+	return &TokenBucket{
+		rate:     float64(rate),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastFill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available and takes it
+func (b *TokenBucket) Wait() {
+	for {
+		delay := b.take()
+		if delay == 0 {
+			return
+		}
+		time.Sleep(delay)
+	}
+}
+
+// Pause empties the bucket so no tokens are given out for the duration,
+// it is used when the remote side asks to slow down
+func (b *TokenBucket) Pause(duration time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = -duration.Seconds() * b.rate
+	b.lastFill = time.Now()
+}
+
+// take takes a token if it is available, otherwise it returns the time to wait for the next one
+func (b *TokenBucket) take() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.lastFill).Seconds()*b.rate)
+	b.lastFill = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketTake(t *testing.T) {
+	tests := []struct {
+		name string
+		// tokens and elapsed are the state of the bucket with rate 10 and burst 5 before the take
+		tokens  float64
+		elapsed time.Duration
+		want    time.Duration
+	}{
+		{name: "full bucket", tokens: 5, want: 0},
+		{name: "last token", tokens: 1, want: 0},
+		{name: "empty bucket", tokens: 0, want: 100 * time.Millisecond},
+		{name: "half a token", tokens: 0.5, want: 50 * time.Millisecond},
+		{name: "refilled", tokens: 0, elapsed: 100 * time.Millisecond, want: 0},
+		{name: "refill is capped by burst", tokens: 0, elapsed: time.Minute, want: 0},
+		{name: "paused", tokens: -10, want: 1100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewTokenBucket(10, 5)
+			b.tokens = tt.tokens
+			b.lastFill = time.Now().Add(-tt.elapsed)
+
+			got := b.take()
+			// the time passes between setting the state and taking the token
+			if got > tt.want || got < tt.want-10*time.Millisecond {
+				t.Errorf("take() = %v, want %v", got, tt.want)
+			}
+			if b.tokens > b.burst {
+				t.Errorf("tokens = %v, exceed burst %v", b.tokens, b.burst)
+			}
+		})
+	}
+}
+
+func TestTokenBucketPause(t *testing.T) {
+	b := NewTokenBucket(10, 5)
+	b.Pause(2 * time.Second)
+	if got := b.take(); got < 2*time.Second {
+		t.Errorf("take() after Pause(2s) = %v, want at least 2s", got)
+	}
+}
@@ -32,6 +32,11 @@ func NewClient(dialer *gomail.Dialer, domain string, from string) *Client {
 
 // Send отправляет письмо.
 func (c *Client) Send(to string, body, message string, subject string, file *bytes.Buffer) {
+	c.SendFile(to, body, message, subject, file, "participants.xlsx")
+}
+
+// SendFile отправляет письмо с файлом fileName.
+func (c *Client) SendFile(to string, body, message string, subject string, file *bytes.Buffer, fileName string) {
 	msg := gomail.NewMessage()
 
 	msg.SetHeader("Message-ID", generateMessageID(c.domain))
@@ -43,7 +48,7 @@ func (c *Client) Send(to string, body, message string, subject string, file *byt
 	msg.AddAlternative("text/html", message)
 
 	if file != nil {
-		msg.Attach("participants.xlsx", gomail.SetCopyFunc(func(w io.Writer) error {
+		msg.Attach(fileName, gomail.SetCopyFunc(func(w io.Writer) error {
 			_, err := w.Write(file.Bytes())
 			if err != nil {
 				logger.Log.Error(err)